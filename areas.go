@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Code Areas
+
+// areaExtensions maps file extensions to languages, or areas.
+var areaExtensions = map[string]string{
+	".go":   "Go",
+	".c":    "C",
+	".h":    "C",
+	".cc":   "C++",
+	".cpp":  "C++",
+	".rs":   "Rust",
+	".py":   "Python",
+	".sh":   "Shell",
+	".js":   "Web",
+	".jsx":  "Web",
+	".ts":   "Web",
+	".tsx":  "Web",
+	".html": "Web",
+	".css":  "Web",
+	".md":   "Docs",
+	".rst":  "Docs",
+	".txt":  "Docs",
+	".yaml": "Config",
+	".yml":  "Config",
+	".json": "Config",
+	".toml": "Config",
+}
+
+// areaOf returns the area of a changed file: CI, docs and build files go by
+// their location or name, everything else by extension.
+func areaOf(file string) string {
+	lower := strings.ToLower(file)
+	base := path.Base(lower)
+
+	switch {
+	case strings.HasPrefix(lower, ".github/workflows/"),
+		strings.HasPrefix(lower, ".circleci/"),
+		base == ".gitlab-ci.yml", base == "jenkinsfile", base == ".travis.yml":
+		return "CI"
+	case strings.HasPrefix(lower, "docs/"), strings.HasPrefix(lower, "doc/"):
+		return "Docs"
+	case base == "makefile", base == "dockerfile", strings.HasSuffix(base, ".mk"),
+		base == "go.mod", base == "go.sum":
+		return "Build"
+	}
+
+	if area, ok := areaExtensions[path.Ext(lower)]; ok {
+		return area
+	}
+	return "Other"
+}
+
+// areaStat is the work done in an area.
+type areaStat struct {
+	lines int // lines added and deleted
+	files int // files changed
+	pulls int // pull requests changing the area
+}
+
+// collectAreas attributes the changed files of the pull requests to areas.
+func collectAreas(ctx context.Context, gh *github.Client, w *work) {
+	for _, id := range w.sortedIds(w.pulls) {
+		meta := w.pulls[id]
+		owner, name, _ := strings.Cut(meta.repo, "/")
+		seen := make(map[string]bool)
+
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			files, resp, err := gh.PullRequests.ListFiles(ctx, owner, name, id.number, opt)
+			if err != nil {
+				fmt.Printf("Error fetching files of %s%s: %v\n", meta.repo, id, err)
+				break
+			}
+			for _, f := range files {
+				area := areaOf(f.GetFilename())
+				stat, ok := w.areas[area]
+				if !ok {
+					stat = &areaStat{}
+					w.areas[area] = stat
+				}
+				stat.lines += f.GetAdditions() + f.GetDeletions()
+				stat.files++
+				if !seen[area] {
+					seen[area] = true
+					stat.pulls++
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+}
+
+// areasReport returns the breakdown of the changed lines by area.
+func (w *work) areasReport() string {
+	if len(w.areas) == 0 {
+		return ""
+	}
+
+	total := 0
+	names := make([]string, 0, len(w.areas))
+	for name, stat := range w.areas {
+		total += stat.lines
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if w.areas[names[i]].lines != w.areas[names[j]].lines {
+			return w.areas[names[i]].lines > w.areas[names[j]].lines
+		}
+		return names[i] < names[j]
+	})
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Areas:"))
+	for _, name := range names {
+		stat := w.areas[name]
+		percent := 0.0
+		if total > 0 {
+			percent = 100 * float64(stat.lines) / float64(total)
+		}
+		report += fmt.Sprintf("  %-8s %5.1f%% (%d lines, %d files, %d pull requests)\n",
+			name, percent, stat.lines, stat.files, stat.pulls)
+	}
+
+	return report
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Assigned Issues
+
+const (
+	AssignedAll     = "all"     // every open issue assigned to me
+	AssignedTouched = "touched" // the ones updated in the period (closed too)
+)
+
+// assignedIssue is an issue assigned to me, as it is now.
+type assignedIssue struct {
+	repo    string // owner/repo (lowercase)
+	number  int
+	title   string
+	url     string
+	state   string // open or closed
+	labels  []string
+	created time.Time
+	updated time.Time
+}
+
+// collectAssigned fetches the issues assigned to me now (a snapshot, not as
+// they were in the period): all the open ones or, if only the touched ones
+// are wanted, the ones updated since the begin date, open or closed.
+func collectAssigned(ctx context.Context, gh *github.Client, w *work, filter *filters, which string, begin, end time.Time) error {
+	opt := &github.IssueListOptions{
+		Filter:      "assigned",
+		State:       "open",
+		Sort:        "updated",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	if which == AssignedTouched {
+		opt.State = "all"
+		opt.Since = begin
+	}
+
+	for {
+		issues, resp, err := gh.Issues.List(ctx, true, opt)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			if which == AssignedTouched && !end.IsZero() && !issue.GetUpdatedAt().Before(end) {
+				continue // touched after the period
+			}
+			repo := issue.GetRepository()
+			e := &github.Event{
+				Repo:   &github.Repository{Name: github.String(repo.GetFullName())},
+				Public: github.Bool(!repo.GetPrivate()),
+			}
+			if !filter.allows(ctx, e) {
+				continue
+			}
+			w.assigned = append(w.assigned, &assignedIssue{
+				repo:    strings.ToLower(repo.GetFullName()),
+				number:  issue.GetNumber(),
+				title:   issue.GetTitle(),
+				url:     issue.GetHTMLURL(),
+				state:   issue.GetState(),
+				labels:  labelNames(issue.Labels),
+				created: issue.GetCreatedAt(),
+				updated: issue.GetUpdatedAt(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	sort.SliceStable(w.assigned, func(i, j int) bool {
+		a, b := w.assigned[i], w.assigned[j]
+		if a.repo != b.repo {
+			return a.repo < b.repo
+		}
+		return a.number < b.number
+	})
+
+	return nil
+}
+
+// assignedReport returns the issues assigned to me, with their state, and
+// whether I worked on them in the period (carry-over work otherwise).
+func (w *work) assignedReport() string {
+	if len(w.assigned) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Assigned issues:"))
+	for _, a := range w.assigned {
+		line := fmt.Sprintf("Issue: %s#%d (%s) %s: %s, opened %s", a.repo, a.number, a.url, a.title, a.state, a.created.In(location).Format("2006-01-02"))
+		if _, ok := w.issues[newID(a.repo, a.number)]; ok {
+			line += ", worked on"
+		}
+		if len(a.labels) > 0 {
+			line += fmt.Sprintf(" [%s]", strings.Join(a.labels, ", "))
+		}
+		report += line + "\n"
+	}
+
+	return report
+}
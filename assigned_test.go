@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssignedReport(t *testing.T) {
+	location = time.UTC
+	opened := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	w := &work{
+		issues: map[id]*metadata{newID("owner/repo", 1): {}},
+		assigned: []*assignedIssue{
+			{repo: "owner/repo", number: 1, title: "Crash", url: "https://github.com/owner/repo/issues/1", state: "open", labels: []string{"bug"}, created: opened},
+			{repo: "owner/repo", number: 2, title: "Docs", url: "https://github.com/owner/repo/issues/2", state: "closed", created: opened},
+		},
+	}
+
+	report := w.assignedReport()
+	for _, want := range []string{
+		"Issue: owner/repo#1 (https://github.com/owner/repo/issues/1) Crash: open, opened 2024-03-04, worked on [bug]\n",
+		"Issue: owner/repo#2 (https://github.com/owner/repo/issues/2) Docs: closed, opened 2024-03-04\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q doesn't contain %q", report, want)
+		}
+	}
+
+	if (&work{}).assignedReport() != "" {
+		t.Error("expected no section without assigned issues")
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Priority Inbox
+
+// SummaryAttention is the summary type listing only the items waiting on me
+// (review requested, question asked, CI failed on my pull request), as a
+// daily triage.
+const SummaryAttention = "attention"
+
+// attention is an issue or pull request waiting on me.
+type attention struct {
+	repo   string // owner/repo (lowercase)
+	number int
+	title  string
+	url    string
+	reason string    // what is waiting on me
+	since  time.Time // since when
+}
+
+// ciFailed returns when the pull request last failed CI, if the latest run of
+// any of its workflows failed.
+func (w *work) ciFailed(id id) (time.Time, bool) {
+	stat := w.ci[id.repo]
+	if stat == nil {
+		return time.Time{}, false
+	}
+	var failed time.Time
+	for key, run := range stat.latest {
+		if key.number == id.number && run.GetConclusion() == "failure" && run.GetCreatedAt().After(failed) {
+			failed = run.GetCreatedAt().Time
+		}
+	}
+	return failed, !failed.IsZero()
+}
+
+// answered returns when others last responded to the item, if after my last
+// action on it.
+func (w *work) answered(id id) (time.Time, bool) {
+	responses := w.responses[id]
+	if len(responses) == 0 {
+		return time.Time{}, false
+	}
+	last := responses[len(responses)-1]
+	for _, a := range w.actions[id] {
+		if !a.at.Before(last) {
+			return time.Time{}, false
+		}
+	}
+	return last, true
+}
+
+// attentions returns the items waiting on me, the longest waiting first: the
+// reviews still requested from me, the mentions I didn't respond to, my items
+// others responded to after my last action, and my pull requests whose CI
+// failed.
+func (w *work) attentions() []*attention {
+	var items []*attention
+	for _, r := range w.reviewRequests {
+		if r.outcome == "pending" {
+			items = append(items, &attention{r.repo, r.number, r.title, r.url, "review requested by " + r.requester, r.requested})
+		}
+	}
+	for _, m := range w.mentions {
+		if m.responded.IsZero() {
+			items = append(items, &attention{m.repo, m.number, m.title, m.url, "mentioned by " + m.by, m.at})
+		}
+	}
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			meta := place[id]
+			if at, ok := w.answered(id); ok {
+				items = append(items, &attention{meta.repo, id.number, meta.title, meta.url, "responded to", at})
+			}
+			if at, ok := w.ciFailed(id); ok && meta.author {
+				items = append(items, &attention{meta.repo, id.number, meta.title, meta.url, "CI failed", at})
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].since.Before(items[j].since)
+	})
+	return items
+}
+
+// keepAttention leaves out my issues and pull requests that aren't waiting on
+// me.
+func (w *work) keepAttention() {
+	waiting := make(map[id]bool)
+	for _, a := range w.attentions() {
+		waiting[newID(a.repo, a.number)] = true
+	}
+
+	idle := make(map[id]bool)
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for id := range place {
+			if !waiting[id] {
+				idle[id] = true
+			}
+		}
+	}
+	w.removeItems(idle)
+}
+
+// attentionReport returns the items waiting on me, the longest waiting first,
+// with what is waiting and for how long (until the end date).
+func (w *work) attentionReport(end time.Time) string {
+	items := w.attentions()
+	if len(items) == 0 {
+		return ""
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Needs attention:"))
+	for _, a := range items {
+		report += fmt.Sprintf("%s#%d (%s) %s: %s %s (waiting %s)\n", a.repo, a.number, a.url, a.title, a.reason,
+			a.since.In(location).Format("2006-01-02 15:04"), formatSpan(end.Sub(a.since)))
+	}
+	return report
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAttention(t *testing.T) {
+	location = time.UTC
+	at := func(day, hour int) time.Time { return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC) }
+
+	var runs []*workflowRun
+	err := json.Unmarshal([]byte(`[
+		{"workflow_id": 1, "conclusion": "failure", "created_at": "2024-03-04T10:00:00Z", "pull_requests": [{"number": 2}]},
+		{"workflow_id": 1, "conclusion": "success", "created_at": "2024-03-04T08:00:00Z", "pull_requests": [{"number": 2}]},
+		{"workflow_id": 1, "conclusion": "failure", "created_at": "2024-03-04T08:00:00Z", "pull_requests": [{"number": 3}]},
+		{"workflow_id": 1, "conclusion": "success", "created_at": "2024-03-04T09:00:00Z", "pull_requests": [{"number": 3}]}
+	]`), &runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat := &ciStat{}
+	for _, run := range runs {
+		stat.add(run)
+	}
+
+	i1, p2, p3 := newID("owner/repo", 1), newID("owner/repo", 2), newID("owner/repo", 3)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "owner/repo", title: "Crash on start", url: "https://github.com/owner/repo/issues/1"}},
+		pulls: map[id]*metadata{
+			p2: {repo: "owner/repo", title: "Fix the crash", url: "https://github.com/owner/repo/pull/2", author: true},
+			p3: {repo: "owner/repo", title: "Add docs", url: "https://github.com/owner/repo/pull/3", author: true},
+		},
+		actions: map[id][]*action{
+			i1: {{at: at(4, 9), object: ObjectIssueComment}},
+			p2: {{at: at(4, 9), object: ObjectPR}},
+			p3: {{at: at(4, 9), object: ObjectPR}},
+		},
+		responses: map[id][]time.Time{
+			i1: {at(4, 11)},
+			p3: {at(4, 8)},
+		},
+		ci: map[string]*ciStat{"owner/repo": stat},
+		reviewRequests: []*reviewRequest{
+			{repo: "other/repo", number: 7, title: "Refactor", url: "https://github.com/other/repo/pull/7", requester: "alice", requested: at(1, 9), outcome: "pending"},
+			{repo: "other/repo", number: 8, title: "Bump", url: "https://github.com/other/repo/pull/8", requester: "bob", requested: at(1, 9), outcome: "approved"},
+		},
+		mentions: []*mention{
+			{repo: "other/repo", number: 9, title: "Question", url: "https://github.com/other/repo/issues/9", by: "carol", at: at(3, 9)},
+			{repo: "other/repo", number: 10, title: "Ping", url: "https://github.com/other/repo/issues/10", by: "dave", at: at(3, 9), responded: at(3, 10)},
+		},
+	}
+
+	want := `
+Needs attention:
+
+other/repo#7 (https://github.com/other/repo/pull/7) Refactor: review requested by alice 2024-03-01 09:00 (waiting 3d3h)
+other/repo#9 (https://github.com/other/repo/issues/9) Question: mentioned by carol 2024-03-03 09:00 (waiting 1d3h)
+owner/repo#2 (https://github.com/owner/repo/pull/2) Fix the crash: CI failed 2024-03-04 10:00 (waiting 2h)
+owner/repo#1 (https://github.com/owner/repo/issues/1) Crash on start: responded to 2024-03-04 11:00 (waiting 1h)
+`
+	if got := w.attentionReport(at(4, 12)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	w.keepAttention()
+	if _, ok := w.pulls[p3]; ok || len(w.pulls) != 1 || len(w.issues) != 1 {
+		t.Errorf("kept %d issues and %d pull requests, want the issue and the failing pull request", len(w.issues), len(w.pulls))
+	}
+}
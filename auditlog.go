@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Audit Log Exports
+
+// pathList is a repeatable flag of file paths, kept as given (not lowercase,
+// nor split at commas, as stringList does).
+type pathList []string
+
+func (l *pathList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *pathList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// auditEntry is an entry of an audit log stream export (NDJSON, as streamed
+// to S3, Splunk, ...). Only the fields the events are made of.
+type auditEntry struct {
+	Action           string `json:"action"`
+	Actor            string `json:"actor"`
+	Repo             string `json:"repo"`
+	Timestamp        int64  `json:"@timestamp"` // milliseconds since the epoch
+	CreatedAt        int64  `json:"created_at"` // milliseconds since the epoch
+	Visibility       string `json:"visibility"` // public, private or internal
+	PublicRepo       *bool  `json:"public_repo"`
+	PullRequestTitle string `json:"pull_request_title"`
+	PullRequestURL   string `json:"pull_request_url"`
+	Ref              string `json:"ref"`
+	Commits          int    `json:"commits"`
+}
+
+// at returns when the entry happened.
+func (a *auditEntry) at() time.Time {
+	ms := a.Timestamp
+	if ms == 0 {
+		ms = a.CreatedAt
+	}
+	return time.UnixMilli(ms)
+}
+
+// public returns true if the repository is public (unknown is private).
+func (a *auditEntry) public() bool {
+	if a.PublicRepo != nil {
+		return *a.PublicRepo
+	}
+	return a.Visibility == "public"
+}
+
+// auditPullNumber returns the number of the pull request from its URL.
+func auditPullNumber(url string) int {
+	i := strings.LastIndex(url, "/pull/")
+	if i < 0 {
+		return 0
+	}
+	number, _ := strconv.Atoi(strings.Trim(url[i+len("/pull/"):], "/"))
+	return number
+}
+
+// auditEvent returns the event of an audit log entry, or nil if the entry is
+// not about something the report covers.
+func auditEvent(a *auditEntry, login string) (*github.Event, error) {
+	number := auditPullNumber(a.PullRequestURL)
+	pr := &github.PullRequest{
+		Number:  github.Int(number),
+		Title:   github.String(a.PullRequestTitle),
+		HTMLURL: github.String(a.PullRequestURL),
+		User:    &github.User{Login: github.String(login)},
+	}
+	pullEvent := func(action string, merged bool) (string, interface{}) {
+		pr := *pr
+		if action != "opened" {
+			pr.User = nil // someone else's, as far as the entry tells
+		}
+		pr.Merged = github.Bool(merged)
+		return "PullRequestEvent", &github.PullRequestEvent{Action: github.String(action), Number: github.Int(number), PullRequest: &pr}
+	}
+
+	at := a.at()
+	var kind string
+	var payload interface{}
+	switch a.Action {
+	case "pull_request.create":
+		kind, payload = pullEvent("opened", false)
+	case "pull_request.merge":
+		kind, payload = pullEvent("closed", true)
+	case "pull_request.close":
+		kind, payload = pullEvent("closed", false)
+	case "pull_request.reopen":
+		kind, payload = pullEvent("reopened", false)
+	case "pull_request_review.submit":
+		kind, payload = "PullRequestReviewEvent", &github.PullRequestReviewEvent{
+			Action:      github.String("created"),
+			Review:      &github.PullRequestReview{SubmittedAt: &at, HTMLURL: github.String(a.PullRequestURL)},
+			PullRequest: pr,
+		}
+	case "pull_request_review_comment.create":
+		kind, payload = "PullRequestReviewCommentEvent", &github.PullRequestReviewCommentEvent{
+			Action:      github.String("created"),
+			Comment:     &github.PullRequestComment{CreatedAt: &at, UpdatedAt: &at},
+			PullRequest: pr,
+		}
+	case "git.push":
+		kind, payload = "PushEvent", &github.PushEvent{Ref: github.String(a.Ref), Size: github.Int(a.Commits)}
+		number = 0
+	default:
+		return nil, nil
+	}
+	if kind != "PushEvent" && number == 0 {
+		return nil, nil // no pull request to put it on
+	}
+
+	return syntheticEvent(kind, a.Repo, login, a.public(), number, a.at(), payload)
+}
+
+// readAuditLog returns my events, between the begin and end dates, allowed by
+// the filters, from audit log stream exports (NDJSON files, gzipped or not).
+// The audit log keeps what the events API forgot, but without the contents
+// (descriptions, comments, commit messages).
+func readAuditLog(ctx context.Context, paths []string, login string, begin, end time.Time, filter *filters) ([]*github.Event, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var events []*github.Event
+	for _, path := range paths {
+		err := readAuditFile(path, func(a *auditEntry) error {
+			if !strings.EqualFold(a.Actor, login) || a.at().Before(begin) || !a.at().Before(end) {
+				return nil
+			}
+			e, err := auditEvent(a, login)
+			if err != nil || e == nil {
+				return err
+			}
+			if filter.allows(ctx, e) {
+				events = append(events, e)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetCreatedAt().After(events[j].GetCreatedAt())
+	})
+
+	return events, nil
+}
+
+// readAuditFile calls add for each entry of an audit log export.
+func readAuditFile(path string, add func(*auditEntry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		a := &auditEntry{}
+		if err := json.Unmarshal([]byte(text), a); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := add(a); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
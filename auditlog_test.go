@@ -0,0 +1,97 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	begin := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := begin.AddDate(0, 0, 7)
+
+	plain := filepath.Join(dir, "audit.json")
+	err := os.WriteFile(plain, []byte(`{"action":"pull_request.create","actor":"me","repo":"o/r","@timestamp":1709629200000,"visibility":"public","pull_request_title":"Fix it","pull_request_url":"https://github.com/o/r/pull/7"}
+{"action":"pull_request.create","actor":"someone","repo":"o/r","@timestamp":1709629200000,"pull_request_url":"https://github.com/o/r/pull/8"}
+{"action":"repo.create","actor":"me","repo":"o/new","@timestamp":1709629200000}
+
+{"action":"pull_request.merge","actor":"me","repo":"o/r","@timestamp":1709715600000,"pull_request_url":"https://github.com/o/r/pull/7"}
+{"action":"git.push","actor":"me","repo":"o/r","@timestamp":1704067200000,"ref":"refs/heads/main"}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gzipped := filepath.Join(dir, "audit.json.gz")
+	f, err := os.Create(gzipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte(`{"action":"pull_request_review.submit","actor":"Me","repo":"o/s","created_at":1709802000000,"pull_request_url":"https://github.com/o/s/pull/3"}` + "\n"))
+	gz.Close()
+	f.Close()
+
+	filter := &filters{repos: make(map[string]bool), orgs: make(map[string]bool)}
+	events, err := readAuditLog(context.Background(), []string{plain, gzipped}, "me", begin, end, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+
+	want := []struct{ kind, repo string }{
+		{"PullRequestReviewEvent", "o/s"},
+		{"PullRequestEvent", "o/r"},
+		{"PullRequestEvent", "o/r"},
+	}
+	for i, w := range want {
+		if events[i].GetType() != w.kind || events[i].GetRepo().GetName() != w.repo {
+			t.Errorf("event %d: got %s in %s, want %s in %s", i, events[i].GetType(), events[i].GetRepo().GetName(), w.kind, w.repo)
+		}
+	}
+	if !events[2].GetPublic() || events[0].GetPublic() {
+		t.Errorf("visibility not kept")
+	}
+}
+
+func TestReadAuditLogInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.json")
+	os.WriteFile(path, []byte("{\"action\":\n"), 0600)
+	filter := &filters{repos: make(map[string]bool), orgs: make(map[string]bool)}
+	if _, err := readAuditLog(context.Background(), []string{path}, "me", time.Time{}, time.Time{}, filter); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestAuditPullNumber(t *testing.T) {
+	for url, want := range map[string]int{
+		"https://github.com/o/r/pull/12":  12,
+		"https://github.com/o/r/pull/12/": 12,
+		"https://github.com/o/r/issues/1": 0,
+		"":                                0,
+	} {
+		if got := auditPullNumber(url); got != want {
+			t.Errorf("auditPullNumber(%q) = %d, want %d", url, got, want)
+		}
+	}
+}
+
+func TestPathList(t *testing.T) {
+	var paths pathList
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&paths, "audit-log", "")
+	if err := flags.Parse([]string{"--audit-log", "~/Exports/Audit,2024.json.gz", "--audit-log", "b.json"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (pathList{"~/Exports/Audit,2024.json.gz", "b.json"}); !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %q, want %q", paths, want)
+	}
+}
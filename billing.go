@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Billing Codes
+
+// unbilled is the billing code of the repositories no pattern matches.
+const unbilled = "unmapped"
+
+// billingCode returns the billing code (client:project) of the repository:
+// the one of the most specific pattern (owner/repo, owner/*, *) matching it.
+func billingCode(codes map[string]string, repo string) string {
+	patterns := make([]string, 0, len(codes))
+	for pattern := range codes {
+		patterns = append(patterns, pattern)
+	}
+	if best, ok := bestPattern(patterns, repo); ok {
+		return codes[best]
+	}
+	return unbilled
+}
+
+// billingRollup is the work billed to a code.
+type billingRollup struct {
+	code   string
+	issues int
+	pulls  int
+	hours  float64 // estimated effort
+	repos  []string
+}
+
+// billingRollups returns the issues, pull requests and estimated hours of each
+// billing code, the most hours first.
+func (w *work) billingRollups(codes map[string]string, weights *effortWeights) []*billingRollup {
+	byCode := make(map[string]*billingRollup)
+	seen := make(map[string]bool) // code and repository
+	for i, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			code := billingCode(codes, id.repo)
+			r := byCode[code]
+			if r == nil {
+				r = &billingRollup{code: code}
+				byCode[code] = r
+			}
+			if i == 0 {
+				r.issues++
+			} else {
+				r.pulls++
+			}
+			r.hours += w.estimateEffort(id, weights)
+			if !seen[code+" "+id.repo] {
+				seen[code+" "+id.repo] = true
+				r.repos = append(r.repos, id.repo)
+			}
+		}
+	}
+
+	rollups := make([]*billingRollup, 0, len(byCode))
+	for _, r := range byCode {
+		sort.Strings(r.repos)
+		rollups = append(rollups, r)
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		if rollups[i].hours != rollups[j].hours {
+			return rollups[i].hours > rollups[j].hours
+		}
+		return rollups[i].code < rollups[j].code
+	})
+	return rollups
+}
+
+// billingReport returns the work rolled up by billing code, with the total.
+func (w *work) billingReport(codes map[string]string, weights *effortWeights) string {
+	if len(codes) == 0 {
+		return ""
+	}
+	rollups := w.billingRollups(codes, weights)
+	if len(rollups) == 0 {
+		return ""
+	}
+
+	total := 0.0
+	report := fmt.Sprintf("\n%s\n\n", msg("Billing:"))
+	for _, r := range rollups {
+		total += r.hours
+		report += fmt.Sprintf("%s: %d issues, %d pull requests, %.2fh estimated (%s)\n", r.code, r.issues, r.pulls, r.hours, strings.Join(r.repos, ", "))
+	}
+	report += fmt.Sprintf("Total: %.2fh estimated\n", total)
+	return report
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBillingReport(t *testing.T) {
+	codes := map[string]string{
+		"influxdata/*":        "ClientA:ProjX",
+		"influxdata/telegraf": "ClientA:ProjY",
+		"rafaeldtinoco/*":     "Internal:OSS",
+	}
+	for repo, want := range map[string]string{
+		"influxdata/influxdb":          "ClientA:ProjX",
+		"influxdata/telegraf":          "ClientA:ProjY",
+		"rafaeldtinoco/ghtimecardator": "Internal:OSS",
+		"other/repo":                   unbilled,
+	} {
+		if got := billingCode(codes, repo); got != want {
+			t.Errorf("billingCode(%s) = %s, want %s", repo, got, want)
+		}
+	}
+
+	at := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	i1, p2, p3 := newID("influxdata/influxdb", 1), newID("influxdata/influxdb", 2), newID("other/repo", 3)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "influxdata/influxdb"}},
+		pulls: map[id]*metadata{
+			p2: {repo: "influxdata/influxdb", author: true, diffStat: true, additions: 100},
+			p3: {repo: "other/repo"},
+		},
+		actions: map[id][]*action{
+			i1: {{at: at, object: ObjectIssueComment}},
+			p2: {{at: at, object: ObjectPR}},
+			p3: {{at: at, object: ObjectPRComment}},
+		},
+		responses: make(map[id][]time.Time),
+	}
+
+	want := `
+Billing:
+
+ClientA:ProjX: 1 issues, 1 pull requests, 1.25h estimated (influxdata/influxdb)
+unmapped: 0 issues, 1 pull requests, 0.25h estimated (other/repo)
+Total: 1.50h estimated
+`
+	if got := w.billingReport(codes, defaultEffort.withDefaults()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := w.billingReport(nil, defaultEffort.withDefaults()); got != "" {
+		t.Errorf("got %q, want no section without billing codes", got)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/google/go-github/v41/github"
+)
+
+// Bitbucket Cloud
+
+const bitbucketAPI = "https://api.bitbucket.org/2.0/"
+
+// bitbucketClient reads from the Bitbucket Cloud REST API (2.0), with an app
+// password (or API token).
+type bitbucketClient struct {
+	url   string
+	user  string
+	token string
+	http  *http.Client
+}
+
+func newBitbucketClient(user, token string) *bitbucketClient {
+	return &bitbucketClient{
+		url:   bitbucketAPI,
+		user:  user,
+		token: token,
+		http:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// get decodes the JSON answer of the API path (or full URL, for the next
+// pages) into out.
+func (c *bitbucketClient) get(ctx context.Context, path string, out interface{}) error {
+	if !strings.Contains(path, "://") {
+		path = c.url + path
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.user, c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("bitbucket: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// pages calls fn with each page of the paginated API path, until there are no
+// more pages or fn returns false.
+func (c *bitbucketClient) pages(ctx context.Context, path string, fn func(values json.RawMessage) (bool, error)) error {
+	for path != "" {
+		var page struct {
+			Values json.RawMessage `json:"values"`
+			Next   string          `json:"next"`
+		}
+		if err := c.get(ctx, path, &page); err != nil {
+			return err
+		}
+		more, err := fn(page.Values)
+		if err != nil || !more {
+			return err
+		}
+		path = page.Next
+	}
+	return nil
+}
+
+type bitbucketUser struct {
+	UUID        string `json:"uuid"`
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+type bitbucketLink struct {
+	HTML struct {
+		Href string `json:"href"`
+	} `json:"html"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int           `json:"id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	State       string        `json:"state"` // OPEN, MERGED, DECLINED, SUPERSEDED
+	Author      bitbucketUser `json:"author"`
+	CreatedOn   time.Time     `json:"created_on"`
+	UpdatedOn   time.Time     `json:"updated_on"`
+	Links       bitbucketLink `json:"links"`
+}
+
+type bitbucketComment struct {
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User      bitbucketUser `json:"user"`
+	Deleted   bool          `json:"deleted"`
+	CreatedOn time.Time     `json:"created_on"`
+	UpdatedOn time.Time     `json:"updated_on"`
+	Links     bitbucketLink `json:"links"`
+}
+
+type bitbucketCommit struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+	Author  struct {
+		Raw  string        `json:"raw"` // Name <email>
+		User bitbucketUser `json:"user"`
+	} `json:"author"`
+}
+
+// pullRequest returns the pull request as the GitHub REST API would.
+func (p *bitbucketPullRequest) pullRequest() *github.PullRequest {
+	state := "closed"
+	if p.State == "OPEN" {
+		state = "open"
+	}
+	pr := &github.PullRequest{
+		Number:    github.Int(p.ID),
+		Title:     github.String(p.Title),
+		Body:      github.String(p.Description),
+		HTMLURL:   github.String(p.Links.HTML.Href),
+		State:     github.String(state),
+		CreatedAt: &p.CreatedOn,
+		UpdatedAt: &p.UpdatedOn,
+		Merged:    github.Bool(p.State == "MERGED"),
+		User:      &github.User{Login: github.String(p.Author.Nickname)},
+	}
+	if p.State == "MERGED" {
+		pr.MergedAt = &p.UpdatedOn
+	}
+	return pr
+}
+
+// fetchBitbucket returns my activity, between the begin and end dates, on the
+// Bitbucket repositories (workspace/repo): pull requests opened and merged,
+// comments on pull requests and commits, as events of the GitHub user.
+func fetchBitbucket(ctx context.Context, bb *bitbucketClient, login string, repos []string, begin, end time.Time, s *spinner.Spinner) ([]*github.Event, error) {
+	s.Prefix = "Fetching Bitbucket activity "
+	s.Start()
+	defer s.Stop()
+
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var me bitbucketUser
+	if err := bb.get(ctx, "user", &me); err != nil {
+		return nil, err
+	}
+
+	var events []*github.Event
+	for _, repo := range repos {
+		repoEvents, err := bitbucketEvents(ctx, bb, &me, login, strings.ToLower(repo), begin, end)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repo, err)
+		}
+		events = append(events, repoEvents...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetCreatedAt().After(events[j].GetCreatedAt())
+	})
+
+	return events, nil
+}
+
+// bitbucketEvents returns my activity on a Bitbucket repository.
+func bitbucketEvents(ctx context.Context, bb *bitbucketClient, me *bitbucketUser, login, repo string, begin, end time.Time) ([]*github.Event, error) {
+	var events []*github.Event
+	add := func(kind string, number int, at time.Time, payload interface{}) error {
+		if at.Before(begin) || !at.Before(end) {
+			return nil
+		}
+		e, err := syntheticEvent(kind, repo, login, false, number, at, payload)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+		return nil
+	}
+	mine := func(u bitbucketUser) bool { return u.UUID == me.UUID }
+
+	// pull requests (in any state) updated in the period
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("updated_on >= %s", begin.UTC().Format(time.RFC3339)))
+	query.Set("pagelen", "50")
+	path := "repositories/" + repo + "/pullrequests?" + query.Encode() +
+		"&state=OPEN&state=MERGED&state=DECLINED&state=SUPERSEDED"
+
+	var pulls []*bitbucketPullRequest
+	err := bb.pages(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []*bitbucketPullRequest
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, err
+		}
+		pulls = append(pulls, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pulls {
+		pr := p.pullRequest()
+		number := p.ID
+		if mine(p.Author) {
+			pr.User.Login = github.String(login) // so I'm the author
+			err := add("PullRequestEvent", number, p.CreatedOn,
+				&github.PullRequestEvent{Action: github.String("opened"), Number: &number, PullRequest: pr})
+			if err != nil {
+				return nil, err
+			}
+			if p.State == "MERGED" {
+				err := add("PullRequestEvent", number, p.UpdatedOn,
+					&github.PullRequestEvent{Action: github.String("closed"), Number: &number, PullRequest: pr})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		path := fmt.Sprintf("repositories/%s/pullrequests/%d/comments?pagelen=100", repo, p.ID)
+		err := bb.pages(ctx, path, func(values json.RawMessage) (bool, error) {
+			var comments []*bitbucketComment
+			if err := json.Unmarshal(values, &comments); err != nil {
+				return false, err
+			}
+			for _, c := range comments {
+				if !mine(c.User) || c.Deleted {
+					continue
+				}
+				comment := &github.PullRequestComment{
+					Body:      github.String(c.Content.Raw),
+					HTMLURL:   github.String(c.Links.HTML.Href),
+					CreatedAt: &c.CreatedOn,
+					UpdatedAt: &c.UpdatedOn,
+				}
+				err := add("PullRequestReviewCommentEvent", number, c.CreatedOn,
+					&github.PullRequestReviewCommentEvent{Action: github.String("created"), Comment: comment, PullRequest: pr})
+				if err != nil {
+					return false, err
+				}
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// commits, newest first: there are no push events, so each of my commits
+	// is a push of its own (to an unknown branch)
+	err = bb.pages(ctx, "repositories/"+repo+"/commits?pagelen=100", func(values json.RawMessage) (bool, error) {
+		var commits []*bitbucketCommit
+		if err := json.Unmarshal(values, &commits); err != nil {
+			return false, err
+		}
+		for _, c := range commits {
+			if c.Date.Before(begin) {
+				return false, nil
+			}
+			if !mine(c.Author.User) {
+				continue
+			}
+			push := &github.PushEvent{
+				Size:         github.Int(1),
+				DistinctSize: github.Int(1),
+				Commits: []*github.HeadCommit{{
+					SHA:      github.String(c.Hash),
+					Message:  github.String(c.Message),
+					Distinct: github.Bool(true),
+				}},
+			}
+			if err := add("PushEvent", 0, c.Date, push); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// mergeEvents returns the events of both lists, newest first.
+func mergeEvents(events, more []*github.Event) []*github.Event {
+	if len(more) == 0 {
+		return events
+	}
+	events = append(events, more...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetCreatedAt().After(events[j].GetCreatedAt())
+	})
+	return events
+}
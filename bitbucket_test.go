@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBitbucketEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/ws/repo/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"values": [
+			{"id": 7, "title": "Add the thing", "state": "MERGED", "author": {"uuid": "{me}"},
+			 "created_on": "2024-03-04T10:00:00+00:00", "updated_on": "2024-03-05T10:00:00+00:00",
+			 "links": {"html": {"href": "https://bitbucket.org/ws/repo/pull-requests/7"}}},
+			{"id": 8, "title": "Someone else's", "state": "OPEN", "author": {"uuid": "{other}"},
+			 "created_on": "2024-03-04T11:00:00+00:00", "updated_on": "2024-03-04T12:00:00+00:00"}
+		]}`)
+	})
+	mux.HandleFunc("/repositories/ws/repo/pullrequests/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"values": []}`)
+	})
+	mux.HandleFunc("/repositories/ws/repo/pullrequests/8/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"values": [
+			{"content": {"raw": "LGTM"}, "user": {"uuid": "{me}"}, "created_on": "2024-03-04T12:00:00+00:00"},
+			{"content": {"raw": "thanks"}, "user": {"uuid": "{other}"}, "created_on": "2024-03-04T13:00:00+00:00"}
+		]}`)
+	})
+	mux.HandleFunc("/repositories/ws/repo/commits", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"values": [
+			{"hash": "abc", "message": "fix: the thing", "date": "2024-03-05T09:00:00+00:00", "author": {"user": {"uuid": "{me}"}}},
+			{"hash": "def", "message": "old", "date": "2024-03-01T09:00:00+00:00", "author": {"user": {"uuid": "{me}"}}}
+		]}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	bb := newBitbucketClient("me", "token")
+	bb.url = server.URL + "/"
+	begin := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	events, err := bitbucketEvents(context.Background(), bb, &bitbucketUser{UUID: "{me}"}, "me-on-github", "ws/repo", begin, begin.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// opened and merged, the comment on the other pull request, and the commit
+	// in the period
+	want := []string{"PullRequestEvent", "PullRequestEvent", "PullRequestReviewCommentEvent", "PushEvent"}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.GetType() != want[i] {
+			t.Errorf("event %d: got %s, want %s", i, e.GetType(), want[i])
+		}
+		if e.GetActor().GetLogin() != "me-on-github" {
+			t.Errorf("event %d: got actor %s, want the GitHub user", i, e.GetActor().GetLogin())
+		}
+	}
+
+	w := &work{user: "me-on-github", pulls: make(map[id]*metadata), actions: make(map[id][]*action)}
+	handleEvent(w, events[0])
+	if meta := w.pulls[newID("ws/repo", 7)]; meta == nil || !meta.author {
+		t.Errorf("the pull request isn't mine: %+v", meta)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Bot Activity
+
+// defaultBots are the accounts denied when no deny list is configured.
+var defaultBots = []string{"*[bot]"}
+
+// botFilter tells bot accounts (dependabot, renovate, CI) apart, so the
+// activity on their issues and pull requests stays out of the report. Logins
+// are matched case insensitively, with * as a wildcard. The allow list wins.
+type botFilter struct {
+	allow []string
+	deny  []string
+}
+
+// isBot returns true if the login is a denied bot account.
+func (b *botFilter) isBot(login string) bool {
+	if b == nil || login == "" {
+		return false
+	}
+	login = strings.ToLower(login)
+	for _, pattern := range b.allow {
+		if wildcardMatch(strings.ToLower(pattern), login) {
+			return false
+		}
+	}
+	for _, pattern := range b.deny {
+		if wildcardMatch(strings.ToLower(pattern), login) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches the text against a pattern where * matches anything
+// (and everything else, brackets included, is literal).
+func wildcardMatch(pattern, text string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == text
+	}
+	if !strings.HasPrefix(text, parts[0]) {
+		return false
+	}
+	text = text[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(text, part)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(part):]
+	}
+	return strings.HasSuffix(text, parts[len(parts)-1])
+}
+
+// bestPattern returns the most specific (longest) of the patterns matching
+// the repository (owner/repo, owner/*, *), if any.
+func bestPattern(patterns []string, repo string) (string, bool) {
+	best, found := "", false
+	for _, pattern := range patterns {
+		if !wildcardMatch(strings.ToLower(pattern), repo) {
+			continue
+		}
+		if !found || len(pattern) > len(best) || len(pattern) == len(best) && pattern < best {
+			best, found = pattern, true
+		}
+	}
+	return best, found
+}
+
+// itemAuthor returns the author of the issue, or pull request, the event
+// payload is about.
+func itemAuthor(payload interface{}) string {
+	switch v := payload.(type) {
+	case *github.IssuesEvent:
+		return v.GetIssue().GetUser().GetLogin()
+	case *github.IssueCommentEvent:
+		return v.GetIssue().GetUser().GetLogin()
+	case *github.PullRequestEvent:
+		return v.GetPullRequest().GetUser().GetLogin()
+	case *github.PullRequestReviewEvent:
+		return v.GetPullRequest().GetUser().GetLogin()
+	case *github.PullRequestReviewCommentEvent:
+		return v.GetPullRequest().GetUser().GetLogin()
+	}
+	return ""
+}
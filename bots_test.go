@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestBotFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		bots  *botFilter
+		login string
+		want  bool
+	}{
+		{"default dependabot", &botFilter{deny: defaultBots}, "dependabot[bot]", true},
+		{"default renovate", &botFilter{deny: defaultBots}, "Renovate[bot]", true},
+		{"default person", &botFilter{deny: defaultBots}, "rafaeldtinoco", false},
+		{"default brackets are literal", &botFilter{deny: defaultBots}, "robot", false},
+		{"allowed bot", &botFilter{allow: []string{"my-release[bot]"}, deny: defaultBots}, "my-release[bot]", false},
+		{"allow everything", &botFilter{allow: []string{"*"}, deny: defaultBots}, "dependabot[bot]", false},
+		{"denied account", &botFilter{deny: []string{"ci-*"}}, "ci-runner", true},
+		{"middle wildcard", &botFilter{deny: []string{"k8s-*-robot"}}, "k8s-ci-robot", true},
+		{"middle wildcard no match", &botFilter{deny: []string{"k8s-*-robot"}}, "k8s-robot", false},
+		{"nil filter", nil, "dependabot[bot]", false},
+		{"no login", &botFilter{deny: []string{"*"}}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bots.isBot(tt.login); got != tt.want {
+				t.Errorf("isBot(%q) = %v, want %v", tt.login, got, tt.want)
+			}
+		})
+	}
+}
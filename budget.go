@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Budget Cap
+
+const SkipOverBudget = "over budget"
+
+type budget struct {
+	maxCost   float64 // dollars, 0 means no limit
+	maxTokens int     // prompt + completion tokens, 0 means no limit
+}
+
+// limited returns true if any cap was set.
+func (b *budget) limited() bool {
+	return b.maxCost > 0 || b.maxTokens > 0
+}
+
+// allows returns true if the given tokens fit in the budget.
+func (b *budget) allows(t *tokens, model string) bool {
+	if b.maxTokens > 0 && t.prompt+t.completion > b.maxTokens {
+		return false
+	}
+	if b.maxCost > 0 && t.cost(model) > b.maxCost {
+		return false
+	}
+	return true
+}
+
+// exceeded returns true if the tokens used so far went over the budget.
+func (b *budget) exceeded(u *usage) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if b.maxTokens > 0 && u.total.prompt+u.total.completion > b.maxTokens {
+		return true
+	}
+	return b.maxCost > 0 && u.total.dollars > b.maxCost
+}
+
+// abort stops the run, listing what was left out and what was spent.
+func (b *budget) abort(w *work) {
+	fmt.Printf("\nBudget exceeded (max cost: $%.2f, max tokens: %d), aborting.\n\n", b.maxCost, b.maxTokens)
+	if ledger := w.unprocessedLedger(); ledger != "" {
+		fmt.Println(ledger)
+	}
+	if summary := tokenUsage.summary(); summary != "" {
+		fmt.Println(summary)
+	}
+	exit(1)
+}
+
+// estimateTokens returns the expected tokens needed to summarize the work:
+// one call per description, one per item and one for the timecard (or, in
+// the ledger and tempo formats, one more per item). Answers are accounted at their
+// maximum length. The timesheet summarizes nothing.
+func estimateTokens(model string, w *work, format string) *tokens {
+	t := &tokens{}
+	if format == FormatTimesheet {
+		return t
+	}
+
+	for _, j := range w.pending {
+		if _, ok := summaries.get(j); ok {
+			continue
+		}
+		t.calls++
+		t.prompt += countTokens(model, j.role+j.instr)
+		t.completion += j.length
+	}
+
+	// each item summary gets the (summarized) description and actions
+	items := len(w.issues) + len(w.pulls)
+	for _, actions := range w.actions {
+		t.prompt += (len(actions) + 1) * maxAnswerTokens
+	}
+	t.calls += items
+	t.prompt += items * countTokens(model, actionSummaryString)
+	t.completion += items * maxAnswerTokens
+
+	// the ledger (and the worklogs) condense every item summary, there is no
+	// timecard
+	if format != FormatTimecard {
+		t.calls += items
+		t.prompt += items * (countTokens(model, ledgerSummaryString) + maxAnswerTokens)
+		t.completion += items * maxLedgerTokens
+		return t
+	}
+
+	// the timecard gets every item summary
+	t.calls++
+	t.prompt += countTokens(model, timecardSummaryString+timecardSummaryExecutive+timecardSummaryTechnical)
+	t.prompt += items * maxAnswerTokens
+	t.completion += maxTimecardTokens
+
+	return t
+}
+
+// isTerminal returns true if there is someone on the terminal to answer.
+func isTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm asks a yes/no question on the terminal.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
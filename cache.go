@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Persistent Summary Cache
+
+// summaryCache stores summaries on disk, keyed by the item (repo and number),
+// when the summarized object was last updated and a hash of the prompt, so
+// the same summary is never paid for twice. Summaries are looked up in, and
+// stored to, every directory (the shared cache and the run directory).
+type summaryCache struct {
+	dirs []string // no directories disables the cache
+}
+
+type cachedSummary struct {
+	Repo    string    `json:"repo"`
+	Item    string    `json:"item"`
+	Updated time.Time `json:"updated_at"`
+	Prompt  string    `json:"prompt_hash"`
+	Summary string    `json:"summary"`
+	Created time.Time `json:"created_at"`
+}
+
+// promptHash returns the hash of everything that changes the answer: the
+// model that answered (so fallback answers are not taken for the primary
+// model ones), the answer length and the prompts.
+func promptHash(j *job) string {
+	model := j.model
+	if model == "" {
+		model = openAIModel
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s\x00%s", model, j.length, j.role, j.instr)))
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns the cache file of the job in the given directory.
+func (c *summaryCache) path(dir string, j *job) string {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s", j.repo, j.item, j.updated.UTC().Format(time.RFC3339), promptHash(j))
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached summary of the job, if any.
+func (c *summaryCache) get(j *job) (string, bool) {
+	for _, dir := range c.dirs {
+		data, err := os.ReadFile(c.path(dir, j))
+		if err != nil {
+			continue
+		}
+		cached := &cachedSummary{}
+		if err := json.Unmarshal(data, cached); err != nil {
+			continue
+		}
+		return cached.Summary, true
+	}
+
+	return "", false
+}
+
+// put stores the summary of the job. Errors are not fatal: the summary will
+// just be paid for again next time.
+func (c *summaryCache) put(j *job, summary string) {
+	data, err := json.Marshal(&cachedSummary{
+		Repo:    j.repo,
+		Item:    j.item,
+		Updated: j.updated,
+		Prompt:  promptHash(j),
+		Summary: summary,
+		Created: time.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	for _, dir := range c.dirs {
+		writeFileAtomic(c.path(dir, j), data)
+	}
+}
+
+// writeFileAtomic writes and renames the file, so concurrent readers never
+// read half of it.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummaryCacheModel(t *testing.T) {
+	cache := &summaryCache{dirs: []string{t.TempDir()}}
+	updated := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	newJob := func() *job {
+		return &job{repo: "owner/repo", item: "#7", role: "role", instr: "instr", length: 100, updated: updated}
+	}
+
+	// answered by a fallback model: not reused for the primary model
+	fallback := newJob()
+	fallback.model = "ollama:llama3"
+	cache.put(fallback, "from the fallback")
+	if got, ok := cache.get(newJob()); ok {
+		t.Errorf("got %q, want no cached summary", got)
+	}
+
+	cache.put(newJob(), "from the primary")
+	if got, ok := cache.get(newJob()); !ok || got != "from the primary" {
+		t.Errorf("got %q, %v, want the primary model summary", got, ok)
+	}
+}
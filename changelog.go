@@ -0,0 +1,97 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Changelog
+
+// SummaryChangelog is the summary type listing only the shipped work (merged
+// pull requests and closed issues), as release notes.
+const SummaryChangelog = "changelog"
+
+// conventionalPrefix matches the conventional commit prefix of a title:
+// type(scope)!: description.
+var conventionalPrefix = regexp.MustCompile(`^(\w+)(\([^)]*\))?!?:\s`)
+
+// conventionalTypes are the conventional commit types of the changelog, with
+// their aliases.
+var conventionalTypes = map[string]string{
+	"feat":     "feat",
+	"feature":  "feat",
+	"fix":      "fix",
+	"bugfix":   "fix",
+	"perf":     "perf",
+	"refactor": "refactor",
+	"docs":     "docs",
+	"doc":      "docs",
+	"test":     "test",
+	"tests":    "test",
+	"build":    "build",
+	"deps":     "build",
+	"ci":       "ci",
+	"chore":    "chore",
+}
+
+// labelTypes are the conventional commit types of the usual labels.
+var labelTypes = map[string]string{
+	"bug":           "fix",
+	"kind/bug":      "fix",
+	"enhancement":   "feat",
+	"feature":       "feat",
+	"kind/feature":  "feat",
+	"documentation": "docs",
+	"docs":          "docs",
+	"performance":   "perf",
+	"refactoring":   "refactor",
+	"tests":         "test",
+	"dependencies":  "build",
+	"ci":            "ci",
+	"chore":         "chore",
+}
+
+// changelogCategory returns the conventional commit type of an item: from the
+// title prefix or, failing that, the labels. It is empty if unknown (left to
+// the summary).
+func changelogCategory(title string, labels []string) string {
+	if m := conventionalPrefix.FindStringSubmatch(title); m != nil {
+		if kind, ok := conventionalTypes[strings.ToLower(m[1])]; ok {
+			return kind
+		}
+	}
+	for _, label := range labels {
+		if kind, ok := labelTypes[label]; ok {
+			return kind
+		}
+	}
+	return ""
+}
+
+// shipped returns true if the pull request was merged, or the issue closed,
+// in the period.
+func (w *work) shipped(id id) bool {
+	done := "closed"
+	if _, ok := w.pulls[id]; ok {
+		done = "merged"
+	}
+	for _, a := range w.actions[id] {
+		if a.action == done {
+			return true
+		}
+	}
+	return false
+}
+
+// keepShipped leaves out the issues and pull requests that didn't ship.
+func (w *work) keepShipped() {
+	unshipped := make(map[id]bool)
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for id := range place {
+			if !w.shipped(id) {
+				unshipped[id] = true
+			}
+		}
+	}
+	w.removeItems(unshipped)
+}
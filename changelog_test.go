@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestChangelogCategory(t *testing.T) {
+	tests := []struct {
+		title  string
+		labels []string
+		want   string
+	}{
+		{"feat(ebpf): add the probe", nil, "feat"},
+		{"Fix!: breaking fix", nil, "fix"},
+		{"docs: typo", []string{"bug"}, "docs"},
+		{"Crash on start", []string{"kind/bug"}, "fix"},
+		{"wip: something", []string{"dependencies"}, "build"},
+		{"Refactor the parser", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := changelogCategory(tt.title, tt.labels); got != tt.want {
+			t.Errorf("changelogCategory(%q, %v) = %q, want %q", tt.title, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestKeepShipped(t *testing.T) {
+	merged, closedPR, closed, open := newID("o/r", 1), newID("o/r", 2), newID("o/r", 3), newID("o/r", 4)
+	w := &work{
+		issues: map[id]*metadata{closed: {}, open: {}},
+		pulls:  map[id]*metadata{merged: {}, closedPR: {}},
+		actions: map[id][]*action{
+			merged:   {{action: "opened"}, {action: "merged"}},
+			closedPR: {{action: "closed"}},
+			closed:   {{action: "closed"}},
+			open:     {{action: "created"}},
+		},
+	}
+
+	w.keepShipped()
+
+	if len(w.pulls) != 1 || w.pulls[merged] == nil {
+		t.Errorf("got pulls %v, want only the merged one", w.pulls)
+	}
+	if len(w.issues) != 1 || w.issues[closed] == nil {
+		t.Errorf("got issues %v, want only the closed one", w.issues)
+	}
+}
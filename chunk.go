@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Token-Aware Chunking
+
+// contextSizes are the context windows of models langchaingo doesn't know.
+var contextSizes = map[string]int{
+	"gpt-3.5-turbo-0125":  16385,
+	"gpt-3.5-turbo-1106":  16385,
+	"gpt-3.5-turbo-16k":   16385,
+	"gpt-4-0125-preview":  128000,
+	"gpt-4-1106-preview":  128000,
+	"gpt-4-turbo":         128000,
+	"gpt-4-turbo-preview": 128000,
+	"gpt-4o":              128000,
+	"gpt-4o-mini":         128000,
+}
+
+// chunkAnswerTokens is the maximum length of each chunk summary.
+const chunkAnswerTokens = 512
+
+// contextSize returns the context window of the model.
+func contextSize(model string) int {
+	if size, ok := contextSizes[model]; ok {
+		return size
+	}
+	return llms.GetModelContextSize(model)
+}
+
+// countTokens returns the number of tokens of the text for the model.
+func countTokens(model, text string) int {
+	return llms.CountTokens(model, text)
+}
+
+// chunkTokens returns how many tokens of input fit in a single call with the
+// given role and answer length.
+func chunkTokens(model, role string, answer int) int {
+	margin := 64 // message framing
+	return contextSize(model) - countTokens(model, role) - answer - margin
+}
+
+// splitChunks splits the text, at line boundaries, into chunks of at most max
+// tokens. Lines that alone are bigger than max are split at word boundaries.
+func splitChunks(model, text string, max int) []string {
+	var chunks []string
+	var chunk strings.Builder
+	size := 0
+
+	flush := func() {
+		if chunk.Len() > 0 {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+			size = 0
+		}
+	}
+	add := func(piece string, tokens int) {
+		if size+tokens > max {
+			flush()
+		}
+		chunk.WriteString(piece)
+		size += tokens
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		tokens := countTokens(model, line)
+		if tokens <= max {
+			add(line, tokens)
+			continue
+		}
+		for _, word := range strings.SplitAfter(line, " ") {
+			add(word, countTokens(model, word))
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// summarize calls the LLM with the given role over the text. If the text does
+// not fit the model context window, it is split into chunks, each chunk is
+// summarized, and the summaries are then summarized (map-reduce).
+func summarize(item, role, text string, answer int) (string, error) {
+	limit := chunkTokens(openAIModel, role, answer)
+	if limit <= chunkAnswerTokens || countTokens(openAIModel, text) <= limit {
+		return executeAI(item, role, text, answer)
+	}
+
+	chunks := splitChunks(openAIModel, text, chunkTokens(openAIModel, chunkSummaryString, chunkAnswerTokens))
+
+	var partials []string
+	for i, chunk := range chunks {
+		instr := fmt.Sprintf("Part %d of %d:\n\n%s", i+1, len(chunks), chunk)
+		partial, err := executeAI(item, chunkSummaryString, instr, chunkAnswerTokens)
+		if err != nil {
+			return "", err
+		}
+		partials = append(partials, partial)
+	}
+
+	return summarize(item, role, strings.Join(partials, "\n\n"), answer)
+}
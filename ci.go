@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// CI Activity
+
+// workflowsPath is where the workflows of a repository are.
+const workflowsPath = ".github/workflows"
+
+// workflowRun is a workflow run, with the attempt (go-github doesn't have it).
+type workflowRun struct {
+	github.WorkflowRun
+	RunAttempt int `json:"run_attempt"`
+}
+
+// ciStat is my CI activity in a repository.
+type ciStat struct {
+	runs    int                           // runs I triggered (pushes, pull requests, re-runs, ...)
+	failed  int                           // of them, the ones that failed
+	reruns  []*workflowRun                // runs I re-ran
+	manual  []*workflowRun                // runs I dispatched by hand
+	commits []*pushCommit                 // my commits changing the workflows
+	latest  map[pullWorkflow]*workflowRun // latest run of each workflow on each pull request
+}
+
+// pullWorkflow is a workflow on a pull request.
+type pullWorkflow struct {
+	number   int
+	workflow int64
+}
+
+// activeRepos returns the repositories I was active in.
+func (w *work) activeRepos() []string {
+	seen := make(map[string]bool)
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for id := range place {
+			seen[id.repo] = true
+		}
+	}
+	for _, bp := range w.pushes {
+		seen[bp.repo] = true
+	}
+
+	repos := make([]string, 0, len(seen))
+	for repo := range seen {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// collectCI fetches, for the repositories I was active in, the workflow runs I
+// triggered between the begin and end dates (re-runs and manual runs apart)
+// and my commits changing the workflows.
+func collectCI(ctx context.Context, gh *github.Client, w *work, begin, end time.Time) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	created := begin.UTC().Format("2006-01-02T15:04:05Z") + ".." + end.UTC().Format("2006-01-02T15:04:05Z")
+
+	for _, repo := range w.activeRepos() {
+		owner, name, _ := strings.Cut(repo, "/")
+		stat := &ciStat{}
+
+		query := url.Values{}
+		query.Set("actor", w.user)
+		query.Set("created", created)
+		query.Set("per_page", "100")
+		path := fmt.Sprintf("repos/%s/%s/actions/runs?%s", owner, name, query.Encode())
+		for page := 1; page > 0; {
+			req, err := gh.NewRequest("GET", fmt.Sprintf("%s&page=%d", path, page), nil)
+			if err != nil {
+				fmt.Println("Error fetching workflow runs:", err)
+				break
+			}
+			var runs struct {
+				WorkflowRuns []*workflowRun `json:"workflow_runs"`
+			}
+			resp, err := gh.Do(ctx, req, &runs)
+			if err != nil {
+				fmt.Printf("Error fetching workflow runs of %s: %v\n", repo, err)
+				break
+			}
+			for _, run := range runs.WorkflowRuns {
+				stat.add(run)
+			}
+			page = resp.NextPage
+		}
+
+		opt := &github.CommitsListOptions{
+			Path:        workflowsPath,
+			Author:      w.user,
+			Since:       begin,
+			Until:       end,
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			commits, resp, err := gh.Repositories.ListCommits(ctx, owner, name, opt)
+			if err != nil {
+				fmt.Printf("Error fetching workflow changes of %s: %v\n", repo, err)
+				break
+			}
+			for _, c := range commits {
+				message, _, _ := strings.Cut(c.GetCommit().GetMessage(), "\n")
+				stat.commits = append(stat.commits, &pushCommit{sha: c.GetSHA(), message: message})
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+
+		if stat.runs > 0 || len(stat.commits) > 0 {
+			w.ci[repo] = stat
+		}
+	}
+}
+
+// add counts a workflow run.
+func (s *ciStat) add(run *workflowRun) {
+	s.runs++
+	if run.GetConclusion() == "failure" {
+		s.failed++
+	}
+	if s.latest == nil {
+		s.latest = make(map[pullWorkflow]*workflowRun)
+	}
+	for _, pr := range run.PullRequests {
+		key := pullWorkflow{pr.GetNumber(), run.GetWorkflowID()}
+		if last, ok := s.latest[key]; !ok || run.GetCreatedAt().After(last.GetCreatedAt().Time) {
+			s.latest[key] = run
+		}
+	}
+	switch {
+	case run.RunAttempt > 1:
+		s.reruns = append(s.reruns, run)
+	case run.GetEvent() == "workflow_dispatch":
+		s.manual = append(s.manual, run)
+	}
+}
+
+// ciReport returns the CI section of the report: per repository, the runs I
+// triggered, re-ran or dispatched, and the workflows I changed.
+func (w *work) ciReport() string {
+	if len(w.ci) == 0 {
+		return ""
+	}
+
+	repos := make([]string, 0, len(w.ci))
+	for repo := range w.ci {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	runLine := func(kind string, run *workflowRun) string {
+		conclusion := run.GetConclusion()
+		if conclusion == "" {
+			conclusion = run.GetStatus()
+		}
+		return fmt.Sprintf("  %s: %s #%d (%s) %s, %s\n", kind, run.GetName(), run.GetRunNumber(), run.GetHTMLURL(), run.GetHeadBranch(), conclusion)
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("CI:"))
+	for _, repo := range repos {
+		s := w.ci[repo]
+		report += fmt.Sprintf("Repository: %s\n", repo)
+		if s.runs > 0 {
+			report += fmt.Sprintf("  Runs: %d (%d failed, %d re-runs, %d manual)\n", s.runs, s.failed, len(s.reruns), len(s.manual))
+		}
+		for _, run := range s.reruns {
+			report += runLine("Re-run", run)
+		}
+		for _, run := range s.manual {
+			report += runLine("Manual run", run)
+		}
+		for _, c := range s.commits {
+			sha := c.sha
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			report += fmt.Sprintf("  Workflow change: %s %s\n", sha, c.message)
+		}
+	}
+
+	return report
+}
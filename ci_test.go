@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCIReport(t *testing.T) {
+	var runs []*workflowRun
+	err := json.Unmarshal([]byte(`[
+		{"name": "CI", "run_number": 10, "event": "push", "conclusion": "failure", "run_attempt": 1},
+		{"name": "CI", "run_number": 10, "event": "push", "conclusion": "success", "run_attempt": 2,
+		 "head_branch": "main", "html_url": "https://github.com/owner/repo/actions/runs/1"},
+		{"name": "Release", "run_number": 3, "event": "workflow_dispatch", "status": "in_progress",
+		 "head_branch": "main", "html_url": "https://github.com/owner/repo/actions/runs/2"}
+	]`), &runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stat := &ciStat{commits: []*pushCommit{{sha: "0123456789", message: "ci: cache the go modules"}}}
+	for _, run := range runs {
+		stat.add(run)
+	}
+	w := &work{ci: map[string]*ciStat{"owner/repo": stat}}
+
+	report := w.ciReport()
+	for _, want := range []string{
+		"Repository: owner/repo\n",
+		"  Runs: 3 (1 failed, 1 re-runs, 1 manual)\n",
+		"  Re-run: CI #10 (https://github.com/owner/repo/actions/runs/1) main, success\n",
+		"  Manual run: Release #3 (https://github.com/owner/repo/actions/runs/2) main, in_progress\n",
+		"  Workflow change: 0123456 ci: cache the go modules\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Clockify Export
+
+// ExportClockify is the export of the estimated effort per item as Clockify
+// time entries.
+const ExportClockify = "clockify"
+
+const clockifyAPI = "https://api.clockify.me/api/v1/"
+
+// clockifyEntry is a Clockify time entry, the fields written.
+type clockifyEntry struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Description string    `json:"description"`
+	ProjectID   string    `json:"projectId,omitempty"`
+}
+
+// clockifyEntries returns a time entry per item: its estimated effort (by the
+// effort weights, rounded to the increment) from my first action on it, in the
+// project of the most specific pattern (owner/repo, owner/*, *) matching the
+// repository, if any.
+func (w *work) clockifyEntries(ids []id, projects map[string]string, weights *effortWeights, increment time.Duration) []*clockifyEntry {
+	patterns := make([]string, 0, len(projects))
+	for pattern := range projects {
+		patterns = append(patterns, pattern)
+	}
+
+	var entries []*clockifyEntry
+	for _, id := range ids {
+		meta := w.getIssueOrPR(id)
+		start, _ := w.period(id)
+		hours := roundHours(w.estimateEffort(id, weights), increment)
+		e := &clockifyEntry{
+			Start:       start.UTC(),
+			End:         start.UTC().Add(time.Duration(hours * float64(time.Hour))),
+			Description: fmt.Sprintf("%s%s %s", meta.repo, id, meta.title),
+		}
+		if best, ok := bestPattern(patterns, meta.repo); ok {
+			e.ProjectID = projects[best]
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// clockifyClient writes to the Clockify API (v1), with an API key.
+type clockifyClient struct {
+	url  string
+	key  string
+	http *http.Client
+}
+
+func newClockifyClient(key string) *clockifyClient {
+	return &clockifyClient{
+		url:  clockifyAPI,
+		key:  key,
+		http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends the body (if any), as JSON, to the API path and decodes the JSON
+// answer into out (if any).
+func (c *clockifyClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		data = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", c.key)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("clockify: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// create creates the time entries in the workspace, but the ones already there
+// (same description and start, from an earlier export), and returns how many
+// it created.
+func (c *clockifyClient) create(ctx context.Context, workspace string, entries []*clockifyEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	var user struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "user", nil, &user); err != nil {
+		return 0, err
+	}
+	first, last := entries[0].Start, entries[0].End
+	for _, e := range entries {
+		if e.Start.Before(first) {
+			first = e.Start
+		}
+		if e.End.After(last) {
+			last = e.End
+		}
+	}
+
+	there := make(map[string]bool)
+	query := url.Values{}
+	query.Set("start", first.Format(time.RFC3339))
+	query.Set("end", last.Add(time.Second).Format(time.RFC3339))
+	query.Set("page-size", "200")
+	for page := 1; ; page++ {
+		var existing []struct {
+			Description  string `json:"description"`
+			TimeInterval struct {
+				Start time.Time `json:"start"`
+			} `json:"timeInterval"`
+		}
+		path := fmt.Sprintf("workspaces/%s/user/%s/time-entries?%s&page=%d", workspace, user.ID, query.Encode(), page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &existing); err != nil {
+			return 0, err
+		}
+		for _, e := range existing {
+			there[e.TimeInterval.Start.UTC().Format(time.RFC3339)+e.Description] = true
+		}
+		if len(existing) < 200 {
+			break
+		}
+	}
+
+	created := 0
+	for _, e := range entries {
+		if there[e.Start.Format(time.RFC3339)+e.Description] {
+			continue
+		}
+		if err := c.do(ctx, http.MethodPost, "workspaces/"+workspace+"/time-entries", e, nil); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
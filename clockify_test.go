@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClockify(t *testing.T) {
+	setLocation(t, time.UTC)
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	i1, p2 := newID("influxdata/influxdb", 1), newID("other/repo", 2)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "influxdata/influxdb", title: "Crash on start"}},
+		pulls:  map[id]*metadata{p2: {repo: "other/repo", title: "Docs"}},
+		actions: map[id][]*action{
+			i1: {{at: day, updated: day, object: ObjectIssueComment}, {at: day.Add(2 * time.Hour), updated: day.Add(2 * time.Hour), object: ObjectIssueComment}},
+			p2: {{at: day.Add(5 * time.Hour), updated: day.Add(5 * time.Hour), object: ObjectPRComment}},
+		},
+		responses: make(map[id][]time.Time),
+	}
+
+	entries := w.clockifyEntries([]id{i1, p2}, map[string]string{"influxdata/*": "proj1"}, defaultEffort.withDefaults(), 30*time.Minute)
+	if e := entries[0]; e.Description != "influxdata/influxdb#1 Crash on start" || e.ProjectID != "proj1" || !e.Start.Equal(day) || e.End.Sub(e.Start) != 30*time.Minute {
+		t.Errorf("got %+v", e)
+	}
+	if e := entries[1]; e.ProjectID != "" || e.End.Sub(e.Start) != 30*time.Minute {
+		t.Errorf("got %+v", e)
+	}
+
+	var created []*clockifyEntry
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			t.Errorf("no API key")
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/user":
+			rw.Write([]byte(`{"id": "u1"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws/user/u1/time-entries":
+			rw.Write([]byte(`[{"description": "influxdata/influxdb#1 Crash on start", "timeInterval": {"start": "2024-03-04T09:00:00Z"}}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/workspaces/ws/time-entries":
+			e := &clockifyEntry{}
+			json.NewDecoder(r.Body).Decode(e)
+			created = append(created, e)
+			rw.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	c := newClockifyClient("key")
+	c.url = server.URL + "/"
+	n, err := c.create(context.Background(), "ws", entries)
+	if err != nil || n != 1 || len(created) != 1 || created[0].Description != "other/repo#2 Docs" {
+		t.Errorf("created %d (%v): %+v", n, err, created)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code Blocks
+
+// maxCodeLines is the longest code block given as it is to the summaries.
+const maxCodeLines = 3
+
+// codeLanguages are the names of the languages of the fenced code blocks, by
+// info string.
+var codeLanguages = map[string]string{
+	"go": "Go", "golang": "Go", "c": "C", "cpp": "C++", "rust": "Rust", "rs": "Rust",
+	"python": "Python", "py": "Python", "js": "JavaScript", "javascript": "JavaScript",
+	"ts": "TypeScript", "typescript": "TypeScript", "java": "Java", "ruby": "Ruby",
+	"sh": "shell", "bash": "shell", "shell": "shell", "console": "shell", "zsh": "shell",
+	"yaml": "YAML", "yml": "YAML", "json": "JSON", "toml": "TOML", "sql": "SQL",
+	"diff": "diff", "patch": "diff", "log": "log", "text": "text", "dockerfile": "Dockerfile",
+	"make": "Makefile", "makefile": "Makefile", "hcl": "HCL", "terraform": "HCL",
+}
+
+// codeKind returns what the code block is, from its info string (```go) or,
+// without one, from its first line.
+func codeKind(info string, lines []string) string {
+	if fields := strings.Fields(info); len(fields) > 0 {
+		if name, ok := codeLanguages[strings.ToLower(fields[0])]; ok {
+			return name + " code"
+		}
+	}
+	if len(lines) > 0 {
+		first := strings.TrimSpace(lines[0])
+		switch {
+		case strings.HasPrefix(first, "diff --git") || strings.HasPrefix(first, "--- "):
+			return "diff"
+		case strings.HasPrefix(first, "$ ") || strings.HasPrefix(first, "# "):
+			return "shell session"
+		case strings.HasPrefix(first, "panic:") || strings.HasPrefix(first, "Traceback") || strings.HasPrefix(first, "goroutine "):
+			return "stack trace"
+		}
+	}
+	return "code"
+}
+
+// condenseCode replaces the fenced code blocks longer than maxCodeLines with
+// what they are ([shared a 40-line Go code block]), so the summaries tell
+// what was shared instead of rewriting raw code, and use fewer tokens.
+func condenseCode(text string) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		fence := ""
+		for _, f := range []string{"```", "~~~"} {
+			if strings.HasPrefix(trimmed, f) {
+				fence = f
+			}
+		}
+		if fence == "" {
+			out = append(out, lines[i])
+			continue
+		}
+
+		info := strings.TrimLeft(trimmed, fence[:1])
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), fence) {
+			end++
+		}
+		code := lines[i+1 : end] // an unterminated block runs to the end
+		if len(code) <= maxCodeLines {
+			out = append(out, lines[i:min(end+1, len(lines))]...)
+		} else {
+			out = append(out, fmt.Sprintf("[shared a %d-line %s block]", len(code), codeKind(info, code)))
+		}
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestCondenseCode(t *testing.T) {
+	for _, tc := range []struct{ text, want string }{
+		{
+			"Here is a reproducer:\n```go\npackage main\n\nfunc main() {\n\tpanic(1)\n}\n```\nIt panics.",
+			"Here is a reproducer:\n[shared a 5-line Go code block]\nIt panics.",
+		},
+		{
+			"Run:\n```\nmake test\n```\n",
+			"Run:\n```\nmake test\n```\n",
+		},
+		{
+			"~~~\n$ go test ./...\nok\nok\nFAIL\n~~~",
+			"[shared a 4-line shell session block]",
+		},
+		{
+			"Trace:\n  ```\n  panic: boom\n  goroutine 1\n  main.go:3\n  main.go:9\n",
+			"Trace:\n[shared a 5-line stack trace block]",
+		},
+		{
+			"```diff extra\n-a\n+b\n-c\n+d\n```",
+			"[shared a 4-line diff code block]",
+		},
+		{"No code here.", "No code here."},
+	} {
+		if got := condenseCode(tc.text); got != tc.want {
+			t.Errorf("condenseCode(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
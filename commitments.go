@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Commitments
+
+const maxCommitmentsTokens = 200 // a few commitments, as JSON
+
+// commitment is a follow-up I promised in a comment.
+type commitment struct {
+	item id
+	What string `json:"commitment"`    // what I promised
+	Due  string `json:"due,omitempty"` // when, as I put it (next week, after the release, ...)
+}
+
+// commitmentJobs returns the jobs extracting the commitments from my comments
+// on each item, the answers going to results. The comments are taken as they
+// are, so the jobs must be created before the comments are summarized.
+func (w *work) commitmentJobs(results map[id]*string) []*job {
+	var jobs []*job
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			meta := place[id]
+			var comments []string
+			updated := meta.updated
+			for _, a := range w.actions[id] {
+				if a.object != ObjectIssueComment && a.object != ObjectPRComment || strings.TrimSpace(a.content) == "" {
+					continue
+				}
+				comments = append(comments, fmt.Sprintf("Comment (%s):\n%s", a.at.In(location).Format("2006-01-02"), a.content))
+				if a.updated.After(updated) {
+					updated = a.updated
+				}
+			}
+			if len(comments) == 0 {
+				continue
+			}
+
+			results[id] = new(string)
+			jobs = append(jobs, &job{
+				repo:    meta.repo,
+				item:    id.String() + " commitments",
+				role:    commitmentsString,
+				instr:   fmt.Sprintf("%s%s %s\n-\n%s", meta.repo, id, meta.title, strings.Join(comments, "\n-\n")),
+				length:  maxCommitmentsTokens,
+				result:  results[id],
+				updated: updated,
+			})
+		}
+	}
+	return jobs
+}
+
+// parseCommitments returns the commitments in the answer: a JSON array, maybe
+// in a code block or with some text around it.
+func parseCommitments(answer string) ([]*commitment, error) {
+	start, end := strings.Index(answer, "["), strings.LastIndex(answer, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in the answer")
+	}
+	var commitments []*commitment
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &commitments); err != nil {
+		return nil, err
+	}
+	kept := commitments[:0]
+	for _, c := range commitments {
+		if c != nil && strings.TrimSpace(c.What) != "" {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
+
+// addCommitments adds the commitments extracted from the comments of each
+// item. Answers that aren't commitments (malformed) are left out.
+func (w *work) addCommitments(results map[id]*string) {
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			answer, ok := results[id]
+			if !ok || *answer == "" {
+				continue
+			}
+			commitments, err := parseCommitments(*answer)
+			if err != nil {
+				fmt.Printf("Error extracting the commitments of %s%s: %v\n", id.repo, id, err)
+				continue
+			}
+			for _, c := range commitments {
+				c.item = id
+				w.commitments = append(w.commitments, c)
+			}
+		}
+	}
+}
+
+// commitmentsReport returns the commitments made, with where.
+func (w *work) commitmentsReport() string {
+	if len(w.commitments) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Commitments made:"))
+	for _, c := range w.commitments {
+		meta := w.getIssueOrPR(c.item)
+		line := fmt.Sprintf("%s%s (%s): %s", meta.repo, meta.eventId, meta.url, c.What)
+		if c.Due != "" {
+			line += fmt.Sprintf(" (due: %s)", c.Due)
+		}
+		report += line + "\n"
+	}
+
+	return report
+}
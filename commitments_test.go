@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCommitments(t *testing.T) {
+	answer := "```json\n[{\"commitment\": \"Send a patch for the probe\", \"due\": \"next week\"}, {\"commitment\": \" \"}]\n```"
+	commitments, err := parseCommitments(answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commitments) != 1 || commitments[0].What != "Send a patch for the probe" || commitments[0].Due != "next week" {
+		t.Errorf("got %+v, want the patch due next week", commitments)
+	}
+
+	if commitments, err := parseCommitments("[]"); err != nil || len(commitments) != 0 {
+		t.Errorf("got %+v (%v), want none", commitments, err)
+	}
+	if _, err := parseCommitments("I will not answer in JSON"); err == nil {
+		t.Error("expected an error without a JSON array")
+	}
+}
+
+func TestCommitments(t *testing.T) {
+	location = time.UTC
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	issue, pull := newID("o/r", 1), newID("o/r", 2)
+	w := &work{
+		issues: map[id]*metadata{issue: {eventId: issue, repo: "o/r", url: "https://github.com/o/r/issues/1", title: "Crash"}},
+		pulls:  map[id]*metadata{pull: {eventId: pull, repo: "o/r", title: "Fix"}},
+		actions: map[id][]*action{
+			issue: {{action: "created", object: ObjectIssueComment, content: "I'll send a patch next week", at: at}},
+			pull:  {{action: "opened", object: ObjectPR, content: "the fix"}},
+		},
+	}
+
+	results := make(map[id]*string)
+	jobs := w.commitmentJobs(results)
+	if len(jobs) != 1 || !strings.Contains(jobs[0].instr, "Comment (2024-03-04):\nI'll send a patch next week") {
+		t.Fatalf("got %d jobs, want one for the issue comment", len(jobs))
+	}
+
+	*results[issue] = `[{"commitment": "Send a patch", "due": "next week"}]`
+	w.addCommitments(results)
+	if got, want := w.commitmentsReport(), "o/r#1 (https://github.com/o/r/issues/1): Send a patch (due: next week)\n"; !strings.Contains(got, want) {
+		t.Errorf("report %q doesn't contain %q", got, want)
+	}
+}
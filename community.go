@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Sponsors and Community Activity
+
+const (
+	CommunitySponsors = "sponsors"
+	CommunityFile     = "community file"
+)
+
+// communityFiles are the files (without extension) that describe how a
+// project is funded and run.
+var communityFiles = map[string]bool{
+	"FUNDING":         true,
+	"CODE_OF_CONDUCT": true,
+	"CONTRIBUTING":    true,
+	"GOVERNANCE":      true,
+	"SECURITY":        true,
+	"SUPPORT":         true,
+	"MAINTAINERS":     true,
+	"CODEOWNERS":      true,
+}
+
+type communityItem struct {
+	kind  string    // sponsors, community file
+	repo  string    // owner/repo (empty for sponsors)
+	title string    // what happened
+	url   string    // where it happened
+	when  time.Time // when it happened
+}
+
+// isCommunityFile returns true if the file is a community health file.
+func isCommunityFile(filename string) bool {
+	base := path.Base(filename)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	return communityFiles[strings.ToUpper(base)]
+}
+
+// collectCommunity adds sponsors activity and changes to community files,
+// pushed by the user, to the work. A zero end date means now.
+func collectCommunity(ctx context.Context, gh *github.Client, w *work, events []*github.Event, begin, end time.Time) {
+	if err := collectSponsors(ctx, gh, w, begin, end); err != nil {
+		fmt.Printf("Error fetching sponsors activity: %v\n", err)
+	}
+
+	for _, e := range events {
+		if e.GetType() != "PushEvent" {
+			continue
+		}
+		pay, err := e.ParsePayload()
+		if err != nil {
+			continue
+		}
+		push := pay.(*github.PushEvent)
+
+		repo := e.GetRepo().GetName()
+		owner, name, _ := strings.Cut(repo, "/")
+
+		for _, c := range push.Commits {
+			if !c.GetDistinct() {
+				continue
+			}
+			commit, _, err := gh.Repositories.GetCommit(ctx, owner, name, c.GetSHA(), nil)
+			if err != nil {
+				fmt.Printf("Error fetching commit %s: %v\n", c.GetSHA(), err)
+				continue
+			}
+			message, _, _ := strings.Cut(c.GetMessage(), "\n")
+			for _, f := range commit.Files {
+				if !isCommunityFile(f.GetFilename()) {
+					continue
+				}
+				w.community = append(w.community, &communityItem{
+					kind:  CommunityFile,
+					repo:  repo,
+					title: fmt.Sprintf("%s: %s", f.GetFilename(), message),
+					url:   commit.GetHTMLURL(),
+					when:  e.GetCreatedAt(),
+				})
+			}
+		}
+	}
+}
+
+const sponsorsQuery = `
+query {
+  viewer {
+    sponsorsActivities(first: 100, period: ALL, orderBy: {field: TIMESTAMP, direction: DESC}) {
+      nodes {
+        action
+        timestamp
+        sponsor {
+          ... on User { login }
+          ... on Organization { login }
+        }
+        sponsorsTier { name }
+      }
+    }
+  }
+}`
+
+// collectSponsors adds the GitHub Sponsors activity of the period to the work.
+func collectSponsors(ctx context.Context, gh *github.Client, w *work, begin, end time.Time) error {
+	var data struct {
+		Viewer struct {
+			SponsorsActivities struct {
+				Nodes []struct {
+					Action    string    `json:"action"`
+					Timestamp time.Time `json:"timestamp"`
+					Sponsor   struct {
+						Login string `json:"login"`
+					} `json:"sponsor"`
+					SponsorsTier struct {
+						Name string `json:"name"`
+					} `json:"sponsorsTier"`
+				} `json:"nodes"`
+			} `json:"sponsorsActivities"`
+		} `json:"viewer"`
+	}
+
+	if err := graphQL(ctx, gh, sponsorsQuery, nil, &data); err != nil {
+		return err
+	}
+
+	for _, n := range data.Viewer.SponsorsActivities.Nodes {
+		if n.Timestamp.Before(begin) {
+			break
+		}
+		if !end.IsZero() && !n.Timestamp.Before(end) {
+			continue // newest first, after the period
+		}
+		action := strings.ToLower(strings.ReplaceAll(n.Action, "_", " "))
+		w.community = append(w.community, &communityItem{
+			kind:  CommunitySponsors,
+			title: fmt.Sprintf("%s by @%s (%s)", action, n.Sponsor.Login, n.SponsorsTier.Name),
+			url:   "https://github.com/sponsors/" + w.user + "/dashboard",
+			when:  n.Timestamp,
+		})
+	}
+
+	return nil
+}
+
+// communityReport returns the community section of the report.
+func (w *work) communityReport() string {
+	if len(w.community) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Community:"))
+	for _, c := range w.community {
+		switch c.kind {
+		case CommunitySponsors:
+			report += fmt.Sprintf("Sponsors: %s (%s) %s\n", c.when.In(location).Format("2006-01-02"), c.url, c.title)
+		case CommunityFile:
+			report += fmt.Sprintf("File: %s (%s) %s\n", c.repo, c.url, c.title)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration
+
+// profile holds the settings that change from one context (work, personal,
+// client, etc) to another.
+type profile struct {
+	GitHubUser          string                    `yaml:"github_user,omitempty"`
+	GitHubToken         string                    `yaml:"github_token,omitempty"`
+	OpenAIToken         string                    `yaml:"openai_token,omitempty"`
+	OpenAIOrganization  string                    `yaml:"openai_organization,omitempty"`
+	OpenAIProject       string                    `yaml:"openai_project,omitempty"`
+	Repo                string                    `yaml:"repo,omitempty"`    // default repository
+	Summary             string                    `yaml:"summary,omitempty"` // default summary type
+	Timezone            string                    `yaml:"timezone,omitempty"`
+	WeekStart           string                    `yaml:"week_start,omitempty"`
+	ExcludeRepos        []string                  `yaml:"exclude_repos,omitempty"`
+	ExcludeOrgs         []string                  `yaml:"exclude_orgs,omitempty"`
+	BotAllow            []string                  `yaml:"bot_allow,omitempty"`
+	BotDeny             []string                  `yaml:"bot_deny,omitempty"`
+	Fallback            []string                  `yaml:"fallback,omitempty"`
+	Calendar            map[string]string         `yaml:"calendar,omitempty"` // day (or days) to note
+	PromptVariants      map[string]*promptVariant `yaml:"prompt_variants,omitempty"`
+	Members             map[string]string         `yaml:"members,omitempty"` // GitHub login to role
+	BitbucketUser       string                    `yaml:"bitbucket_user,omitempty"`
+	BitbucketToken      string                    `yaml:"bitbucket_token,omitempty"`
+	BitbucketRepos      []string                  `yaml:"bitbucket_repos,omitempty"` // workspace/repo
+	Significance        []*significanceRule       `yaml:"significance,omitempty"`    // first match wins
+	TempoIssues         map[string]string         `yaml:"tempo_issues,omitempty"`    // owner/repo (or pattern) to Jira issue
+	Weights             map[string]float64        `yaml:"weights,omitempty"`         // owner/repo (or pattern) to narrative weight
+	UsageStats          bool                      `yaml:"usage_stats,omitempty"`     // record the runs for stats usage (opt-in)
+	Locale              string                    `yaml:"locale,omitempty"`          // language of the report and timecard
+	Effort              *effortWeights            `yaml:"effort,omitempty"`          // weights of the estimated effort
+	GoogleCalendar      string                    `yaml:"google_calendar,omitempty"` // primary, or a calendar ID
+	GoogleCalendarToken string                    `yaml:"google_calendar_token,omitempty"`
+	BillingCodes        map[string]string         `yaml:"billing_codes,omitempty"`  // owner/repo (or pattern) to client:project
+	ObsidianVault       string                    `yaml:"obsidian_vault,omitempty"` // folder of the vault for --deliver obsidian
+	HarvestTasks        map[string]*harvestTask   `yaml:"harvest_tasks,omitempty"`  // owner/repo (or pattern) to Harvest project and task
+	HarvestName         string                    `yaml:"harvest_name,omitempty"`   // first and last name, for the time import
+	TogglWorkspace      int64                     `yaml:"toggl_workspace,omitempty"`
+	TogglToken          string                    `yaml:"toggl_token,omitempty"`
+	TogglProjects       map[string]*togglProject  `yaml:"toggl_projects,omitempty"` // owner/repo (or pattern) to Toggl Track project and tags
+	ClockifyWorkspace   string                    `yaml:"clockify_workspace,omitempty"`
+	ClockifyToken       string                    `yaml:"clockify_token,omitempty"`
+	ClockifyProjects    map[string]string         `yaml:"clockify_projects,omitempty"` // owner/repo (or pattern) to Clockify project ID
+}
+
+type config struct {
+	Profile  string              `yaml:"profile"` // default profile
+	Profiles map[string]*profile `yaml:"profiles"`
+
+	found bool // the configuration file exists
+}
+
+// configDir returns the directory holding the configuration and state.
+func configDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".ghtimecardator"
+	}
+	return filepath.Join(home, ".ghtimecardator")
+}
+
+// loadConfig reads the configuration file. A missing file is an empty
+// configuration.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.found = true
+
+	return cfg, nil
+}
+
+// memberRole returns the role assigned to the given GitHub login, if any.
+func (p *profile) memberRole(login string) string {
+	for member, role := range p.Members {
+		if strings.EqualFold(member, login) {
+			return strings.ToLower(role)
+		}
+	}
+	return ""
+}
+
+// getProfile returns the profile with the given name, or the default profile
+// if no name is given. Without profiles, an empty profile is returned.
+func (c *config) getProfile(name string) (*profile, error) {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		return &profile{}, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	return p, nil
+}
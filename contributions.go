@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/google/go-github/v41/github"
+)
+
+// GraphQL Contributions Backend
+
+// eventsKept is how far back the events API goes. Longer ranges are fetched
+// from the contributions collection, and their comments from the search API
+// (--backend auto).
+const eventsKept = 90 * 24 * time.Hour
+
+// useContributions returns true if the events should be fetched from the
+// contributions collection instead of the events API.
+func useContributions(backend string, begin time.Time) bool {
+	switch backend {
+	case "graphql":
+		return true
+	case "events":
+		return false
+	default:
+		return begin.Before(time.Now().Add(-eventsKept))
+	}
+}
+
+const contributionsQuery = `
+query($login: String!, $from: DateTime!, $to: DateTime!, $issues: String, $pulls: String, $reviews: String) {
+  user(login: $login) {
+    contributionsCollection(from: $from, to: $to) {
+      issueContributions(first: 100, after: $issues) {
+        pageInfo { hasNextPage endCursor }
+        nodes { occurredAt issue { ...issue } }
+      }
+      pullRequestContributions(first: 100, after: $pulls) {
+        pageInfo { hasNextPage endCursor }
+        nodes { occurredAt pullRequest { ...pull } }
+      }
+      pullRequestReviewContributions(first: 100, after: $reviews) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          occurredAt
+          pullRequestReview { body url submittedAt updatedAt }
+          pullRequest { ...pull }
+        }
+      }
+      commitContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner isPrivate }
+        contributions(first: 100) {
+          nodes { occurredAt commitCount }
+        }
+      }
+    }
+  }
+}
+
+fragment issue on Issue {
+  number title body url state createdAt updatedAt closedAt
+  author { login }
+  labels(first: 20) { nodes { name } }
+  repository { nameWithOwner isPrivate }
+}
+
+fragment pull on PullRequest {
+  number title body url state createdAt updatedAt merged mergedAt
+  author { login }
+  labels(first: 20) { nodes { name } }
+  repository { nameWithOwner isPrivate }
+}`
+
+type contributionRepo struct {
+	NameWithOwner string `json:"nameWithOwner"`
+	IsPrivate     bool   `json:"isPrivate"`
+}
+
+type contributionItem struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	URL       string     `json:"url"`
+	State     string     `json:"state"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	ClosedAt  *time.Time `json:"closedAt"`
+	Merged    bool       `json:"merged"`
+	MergedAt  *time.Time `json:"mergedAt"`
+	Author    struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Repository contributionRepo `json:"repository"`
+}
+
+// labels returns the labels as the REST API would.
+func (c *contributionItem) labels() []*github.Label {
+	var labels []*github.Label
+	for _, l := range c.Labels.Nodes {
+		labels = append(labels, &github.Label{Name: github.String(l.Name)})
+	}
+	return labels
+}
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type contributionsCollection struct {
+	IssueContributions struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			OccurredAt time.Time        `json:"occurredAt"`
+			Issue      contributionItem `json:"issue"`
+		} `json:"nodes"`
+	} `json:"issueContributions"`
+	PullRequestContributions struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			OccurredAt  time.Time        `json:"occurredAt"`
+			PullRequest contributionItem `json:"pullRequest"`
+		} `json:"nodes"`
+	} `json:"pullRequestContributions"`
+	PullRequestReviewContributions struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			OccurredAt        time.Time `json:"occurredAt"`
+			PullRequestReview struct {
+				Body        string    `json:"body"`
+				URL         string    `json:"url"`
+				SubmittedAt time.Time `json:"submittedAt"`
+				UpdatedAt   time.Time `json:"updatedAt"`
+			} `json:"pullRequestReview"`
+			PullRequest contributionItem `json:"pullRequest"`
+		} `json:"nodes"`
+	} `json:"pullRequestReviewContributions"`
+	CommitContributionsByRepository []struct {
+		Repository    contributionRepo `json:"repository"`
+		Contributions struct {
+			Nodes []struct {
+				OccurredAt  time.Time `json:"occurredAt"`
+				CommitCount int       `json:"commitCount"`
+			} `json:"nodes"`
+		} `json:"contributions"`
+	} `json:"commitContributionsByRepository"`
+}
+
+// issue returns the item as the REST API would.
+func (c *contributionItem) issue() *github.Issue {
+	return &github.Issue{
+		Number:    github.Int(c.Number),
+		Title:     github.String(c.Title),
+		Body:      github.String(c.Body),
+		HTMLURL:   github.String(c.URL),
+		State:     github.String(c.State),
+		CreatedAt: &c.CreatedAt,
+		UpdatedAt: &c.UpdatedAt,
+		ClosedAt:  c.ClosedAt,
+		User:      &github.User{Login: github.String(c.Author.Login)},
+		Labels:    c.labels(),
+	}
+}
+
+// pullRequest returns the item as the REST API would.
+func (c *contributionItem) pullRequest() *github.PullRequest {
+	return &github.PullRequest{
+		Number:    github.Int(c.Number),
+		Title:     github.String(c.Title),
+		Body:      github.String(c.Body),
+		HTMLURL:   github.String(c.URL),
+		State:     github.String(c.State),
+		CreatedAt: &c.CreatedAt,
+		UpdatedAt: &c.UpdatedAt,
+		Merged:    github.Bool(c.Merged),
+		MergedAt:  c.MergedAt,
+		User:      &github.User{Login: github.String(c.Author.Login)},
+		Labels:    c.labels(),
+	}
+}
+
+// fetchContributions returns the contributions of the user, between the begin
+// and end dates, allowed by the filters.
+func fetchContributions(ctx context.Context, gh *github.Client, user string, begin, end time.Time, filter *filters, s *spinner.Spinner) ([]*github.Event, error) {
+	s.Prefix = "Fetching contributions "
+	s.Start()
+	defer s.Stop()
+
+	contributions, err := contributionEvents(ctx, gh, user, begin, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*github.Event
+	for _, event := range contributions {
+		if filter.allows(ctx, event) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// searchComments returns my comments, between the begin and end dates,
+// allowed by the filters, reconstructed with the search API: comments are not
+// contributions.
+func searchComments(ctx context.Context, gh *github.Client, user string, begin, end time.Time, filter *filters, s *spinner.Spinner) ([]*github.Event, error) {
+	s.Prefix = "Searching comments "
+	s.Start()
+	defer s.Stop()
+
+	if end.IsZero() {
+		end = time.Now()
+	}
+	found, err := searchEvents(ctx, gh, user, begin, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*github.Event
+	for _, event := range found {
+		if event.GetType() == "IssueCommentEvent" && filter.allows(ctx, event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// contributionEvents returns the contributions of the user, between the begin
+// and end dates, as events: issues and pull requests opened, pull requests
+// merged, reviews and commits (pushes, one per repository and day). Comments
+// are not contributions, so they are missing. The events are returned newest
+// first, like the events API does.
+func contributionEvents(ctx context.Context, gh *github.Client, user string, begin, end time.Time) ([]*github.Event, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var events []*github.Event
+	add := func(kind string, repo contributionRepo, number int, at time.Time, payload interface{}) error {
+		if at.Before(begin) || !at.Before(end) {
+			return nil
+		}
+		e, err := syntheticEvent(kind, repo.NameWithOwner, user, !repo.IsPrivate, number, at, payload)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+		return nil
+	}
+
+	// a contributions collection spans at most a year
+	for from := begin; from.Before(end); from = from.AddDate(1, 0, 0) {
+		to := from.AddDate(1, 0, 0)
+		if to.After(end) {
+			to = end
+		}
+		if err := contributionWindow(ctx, gh, user, from, to, add); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetCreatedAt().After(events[j].GetCreatedAt())
+	})
+
+	return events, nil
+}
+
+// contributionWindow adds the contributions between from and to (at most a
+// year apart), following the pages of each kind of contribution.
+func contributionWindow(ctx context.Context, gh *github.Client, user string, from, to time.Time,
+	add func(kind string, repo contributionRepo, number int, at time.Time, payload interface{}) error) error {
+
+	vars := map[string]interface{}{
+		"login": user,
+		"from":  from.UTC().Format(time.RFC3339),
+		"to":    to.UTC().Format(time.RFC3339),
+	}
+	more := map[string]bool{"issues": true, "pulls": true, "reviews": true}
+	first := true
+
+	for more["issues"] || more["pulls"] || more["reviews"] {
+		var data struct {
+			User *struct {
+				ContributionsCollection contributionsCollection `json:"contributionsCollection"`
+			} `json:"user"`
+		}
+		if err := graphQL(ctx, gh, contributionsQuery, vars, &data); err != nil {
+			return err
+		}
+		if data.User == nil {
+			return fmt.Errorf("user %s not found", user)
+		}
+		c := data.User.ContributionsCollection
+
+		if more["issues"] {
+			for _, n := range c.IssueContributions.Nodes {
+				issue := n.Issue.issue()
+				err := add("IssuesEvent", n.Issue.Repository, n.Issue.Number, n.OccurredAt,
+					&github.IssuesEvent{Action: github.String("opened"), Issue: issue})
+				if err != nil {
+					return err
+				}
+			}
+			more["issues"] = nextPage(vars, "issues", c.IssueContributions.PageInfo)
+		}
+		if more["pulls"] {
+			for _, n := range c.PullRequestContributions.Nodes {
+				number := n.PullRequest.Number
+				pr := n.PullRequest.pullRequest()
+				err := add("PullRequestEvent", n.PullRequest.Repository, number, n.OccurredAt,
+					&github.PullRequestEvent{Action: github.String("opened"), Number: &number, PullRequest: pr})
+				if err != nil {
+					return err
+				}
+				if n.PullRequest.MergedAt != nil {
+					err := add("PullRequestEvent", n.PullRequest.Repository, number, *n.PullRequest.MergedAt,
+						&github.PullRequestEvent{Action: github.String("closed"), Number: &number, PullRequest: pr})
+					if err != nil {
+						return err
+					}
+				}
+			}
+			more["pulls"] = nextPage(vars, "pulls", c.PullRequestContributions.PageInfo)
+		}
+		if more["reviews"] {
+			for _, n := range c.PullRequestReviewContributions.Nodes {
+				r := n.PullRequestReview
+				review := &github.PullRequestReview{
+					Body:        github.String(r.Body),
+					HTMLURL:     github.String(r.URL),
+					SubmittedAt: &r.SubmittedAt,
+				}
+				err := add("PullRequestReviewEvent", n.PullRequest.Repository, n.PullRequest.Number, n.OccurredAt,
+					&github.PullRequestReviewEvent{Action: github.String("created"), Review: review, PullRequest: n.PullRequest.pullRequest()})
+				if err != nil {
+					return err
+				}
+			}
+			more["reviews"] = nextPage(vars, "reviews", c.PullRequestReviewContributions.PageInfo)
+		}
+
+		// commits are summed up per day, they come in the first page
+		if first {
+			for _, r := range c.CommitContributionsByRepository {
+				for _, n := range r.Contributions.Nodes {
+					err := add("PushEvent", r.Repository, 0, n.OccurredAt,
+						&github.PushEvent{Size: github.Int(n.CommitCount)})
+					if err != nil {
+						return err
+					}
+				}
+			}
+			first = false
+		}
+	}
+
+	return nil
+}
+
+// nextPage sets the cursor for the next page of a kind of contribution,
+// returning false if there is no next page.
+func nextPage(vars map[string]interface{}, kind string, page pageInfo) bool {
+	if !page.HasNextPage {
+		return false
+	}
+	vars[kind] = page.EndCursor
+	return true
+}
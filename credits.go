@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Credited Commits
+
+// credit is a commit of someone else I'm credited on: pair programming
+// (Co-authored-by) or a patch of mine they carried (Signed-off-by).
+type credit struct {
+	repo    string // owner/repo (lowercase)
+	sha     string
+	message string // first line
+	kind    string // co-authored or signed off
+	by      string // login of the author
+	pull    id     // pull request of the commit, zero if none
+	at      time.Time
+}
+
+// trailers returns the values of the trailers (Key: value) with the key in a
+// commit message.
+func trailers(message, key string) []string {
+	key = strings.ToLower(key) + ":"
+	var values []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > len(key) && strings.EqualFold(line[:len(key)], key) {
+			values = append(values, strings.TrimSpace(line[len(key):]))
+		}
+	}
+	return values
+}
+
+// creditOf returns the credit I get for a commit of someone else, if any. My
+// own commits, and the suggestions applied from the web UI (counted with
+// --suggestions), get none.
+func creditOf(repo string, c *github.RepositoryCommit, user *github.User) *credit {
+	if strings.EqualFold(c.GetAuthor().GetLogin(), user.GetLogin()) || c.GetCommitter().GetLogin() == webFlow && len(coAuthors(c.GetCommit().GetMessage())) > 0 {
+		return nil
+	}
+
+	kind := ""
+	for _, trailer := range []struct{ key, kind string }{{"Co-authored-by", "co-authored"}, {"Signed-off-by", "signed off"}} {
+		for _, who := range trailers(c.GetCommit().GetMessage(), trailer.key) {
+			if isMe(who, user) {
+				kind = trailer.kind
+				break
+			}
+		}
+		if kind != "" {
+			break
+		}
+	}
+	if kind == "" {
+		return nil
+	}
+
+	by := c.GetAuthor().GetLogin()
+	if by == "" {
+		by = c.GetCommit().GetAuthor().GetName()
+	}
+	message, _, _ := strings.Cut(c.GetCommit().GetMessage(), "\n")
+	return &credit{
+		repo:    strings.ToLower(repo),
+		sha:     c.GetSHA(),
+		message: message,
+		kind:    kind,
+		by:      by,
+		at:      c.GetCommit().GetAuthor().GetDate(),
+	}
+}
+
+// collectCredits fetches, from the repositories I was active in, the commits
+// of others between the begin and end dates that credit me, and attributes
+// them to their pull requests: added to the work, if not there yet, with the
+// commits as actions.
+func collectCredits(ctx context.Context, gh *github.Client, w *work, filter *filters, begin, end time.Time) error {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	user, _, err := gh.Users.Get(ctx, w.user)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range w.activeRepos() {
+		owner, name, _ := strings.Cut(repo, "/")
+		opt := &github.CommitsListOptions{Since: begin, Until: end, ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			commits, resp, err := gh.Repositories.ListCommits(ctx, owner, name, opt)
+			if err != nil {
+				fmt.Printf("Error fetching the commits of %s: %v\n", repo, err)
+				break
+			}
+			for _, c := range commits {
+				if cr := creditOf(repo, c, user); cr != nil {
+					w.addCredit(ctx, gh, filter, cr)
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+
+	return nil
+}
+
+// addCredit records the credit, and adds the commit to the actions of its
+// pull request (the first one with it).
+func (w *work) addCredit(ctx context.Context, gh *github.Client, filter *filters, cr *credit) {
+	w.credits = append(w.credits, cr)
+
+	owner, name, _ := strings.Cut(cr.repo, "/")
+	pulls, _, err := gh.PullRequests.ListPullRequestsWithCommit(ctx, owner, name, cr.sha, nil)
+	if err != nil {
+		fmt.Printf("Error fetching the pull requests of %s@%s: %v\n", cr.repo, cr.sha, err)
+		return
+	}
+	for _, pr := range pulls {
+		e, err := syntheticEvent("PullRequestEvent", cr.repo, w.user, true, pr.GetNumber(), cr.at,
+			&github.PullRequestEvent{Action: github.String("credited"), PullRequest: pr})
+		if err != nil || !filter.allows(ctx, e) {
+			continue
+		}
+		cr.pull = newID(cr.repo, pr.GetNumber())
+		w.addPullRequest(e, pr)
+		w.actions[cr.pull] = append(w.actions[cr.pull], &action{
+			action:  cr.kind,
+			object:  ObjectCommit,
+			content: cr.message,
+			updated: cr.at,
+			at:      cr.at,
+		})
+		return
+	}
+}
+
+// attributionNote returns the commits of others the pull request credits me
+// on, or an empty string if none.
+func (w *work) attributionNote(pull id) string {
+	counts := make(map[string]int) // by kind and author
+	var keys []string
+	for _, cr := range w.credits {
+		if cr.pull != pull {
+			continue
+		}
+		key := cr.kind + " by " + cr.by
+		if counts[key] == 0 {
+			keys = append(keys, key)
+		}
+		counts[key]++
+	}
+
+	var notes []string
+	for _, key := range keys {
+		kind, by, _ := strings.Cut(key, " by ")
+		notes = append(notes, fmt.Sprintf("%d commits %s, pushed by %s", counts[key], kind, by))
+	}
+	return strings.Join(notes, "; ")
+}
+
+// creditsReport returns the commits of others I'm credited on, oldest first.
+func (w *work) creditsReport() string {
+	if len(w.credits) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Credited commits:"))
+	for _, cr := range w.credits {
+		sha := cr.sha
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		line := fmt.Sprintf("%s %s %s %s (%s, pushed by %s)", cr.at.In(location).Format("2006-01-02"), cr.repo, sha, cr.message, cr.kind, cr.by)
+		if cr.pull.number != 0 {
+			line += " in " + cr.pull.repo + cr.pull.String()
+		}
+		report += line + "\n"
+	}
+	return report
+}
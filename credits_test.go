@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestCreditOf(t *testing.T) {
+	me := &github.User{Login: github.String("me"), Name: github.String("My Name")}
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	commit := func(author, committer, message string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			SHA:       github.String("0123456789abcdef"),
+			Author:    &github.User{Login: github.String(author)},
+			Committer: &github.User{Login: github.String(committer)},
+			Commit: &github.Commit{
+				Message: github.String(message),
+				Author:  &github.CommitAuthor{Date: &at},
+			},
+		}
+	}
+
+	cr := creditOf("Owner/Repo", commit("alice", "alice", "Pair on the parser\n\nCo-authored-by: My Name <me@example.com>"), me)
+	if cr == nil || cr.kind != "co-authored" || cr.by != "alice" || cr.message != "Pair on the parser" || cr.repo != "owner/repo" {
+		t.Errorf("got %+v, want co-authored by alice", cr)
+	}
+	cr = creditOf("owner/repo", commit("bob", "bob", "Carry the fix\n\nSigned-off-by: Someone <1+me@users.noreply.github.com>\nSigned-off-by: Bob <bob@example.com>"), me)
+	if cr == nil || cr.kind != "signed off" || cr.by != "bob" {
+		t.Errorf("got %+v, want signed off, by bob", cr)
+	}
+
+	for _, c := range []*github.RepositoryCommit{
+		commit("me", "bob", "Mine\n\nSigned-off-by: My Name <me@example.com>"),
+		commit("alice", webFlow, "Apply suggestions\n\nCo-authored-by: My Name <me@example.com>"),
+		commit("alice", "alice", "Theirs\n\nSigned-off-by: Alice <alice@example.com>"),
+	} {
+		if cr := creditOf("owner/repo", c, me); cr != nil {
+			t.Errorf("got %+v, want no credit for %q", cr, c.GetCommit().GetMessage())
+		}
+	}
+}
+
+func TestCreditsReport(t *testing.T) {
+	location = time.UTC
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	pull := newID("owner/repo", 7)
+	w := &work{credits: []*credit{
+		{repo: "owner/repo", sha: "0123456789", message: "Pair on the parser", kind: "co-authored", by: "alice", pull: pull, at: at},
+		{repo: "owner/repo", sha: "abcdef0123", message: "More parser", kind: "co-authored", by: "alice", pull: pull, at: at},
+		{repo: "owner/other", sha: "fedcba9876", message: "Carry the fix", kind: "signed off", by: "bob", at: at},
+	}}
+
+	want := `
+Credited commits:
+
+2024-03-04 owner/repo 0123456 Pair on the parser (co-authored, pushed by alice) in owner/repo#7
+2024-03-04 owner/repo abcdef0 More parser (co-authored, pushed by alice) in owner/repo#7
+2024-03-04 owner/other fedcba9 Carry the fix (signed off, pushed by bob)
+`
+	if got := w.creditsReport(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := w.attributionNote(pull); got != "2 commits co-authored, pushed by alice" {
+		t.Errorf("got note %q", got)
+	}
+	if got := w.attributionNote(newID("owner/repo", 8)); got != "" {
+		t.Errorf("got note %q, want none", got)
+	}
+}
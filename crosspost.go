@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// Cross-Posted Comments
+
+// crossPost is the same comment posted on several issues, or pull requests.
+type crossPost struct {
+	content      *string // the comment (summarized)
+	destinations []*metadata
+}
+
+// commentHash returns the hash of a comment body, ignoring surrounding
+// whitespace.
+func commentHash(body string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(strings.TrimSpace(body)))
+}
+
+// collapseCrossPosts finds the comments posted, exactly the same, on more than
+// one item (e.g. release announcements) and keeps a single copy of each, with
+// the list of destinations, so they are summarized and reported once. Items
+// left without actions (only the cross-posted comment) are dropped.
+func (w *work) collapseCrossPosts() {
+	type posting struct {
+		id     id
+		action *action
+	}
+	byHash := make(map[[sha256.Size]byte][]posting)
+	var order [][sha256.Size]byte
+
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			for _, a := range w.actions[id] {
+				if a.object != ObjectIssueComment && a.object != ObjectPRComment || strings.TrimSpace(a.content) == "" {
+					continue
+				}
+				h := commentHash(a.content)
+				if _, ok := byHash[h]; !ok {
+					order = append(order, h)
+				}
+				byHash[h] = append(byHash[h], posting{id, a})
+			}
+		}
+	}
+
+	drop := make(map[*string]bool) // results of the jobs no longer needed
+	emptied := make(map[id]bool)   // items left without actions
+	for _, h := range order {
+		postings := byHash[h]
+		items := make(map[id]bool)
+		for _, p := range postings {
+			items[p.id] = true
+		}
+		if len(items) < 2 {
+			continue
+		}
+
+		post := &crossPost{content: &postings[0].action.content}
+		for _, p := range postings {
+			if !items[p.id] {
+				continue // posted more than once on the same item
+			}
+			items[p.id] = false
+			post.destinations = append(post.destinations, w.getIssueOrPR(p.id))
+		}
+		for _, p := range postings {
+			if w.removeAction(p.id, p.action) {
+				emptied[p.id] = true
+			}
+			if p.action != postings[0].action {
+				drop[&p.action.content] = true
+			}
+		}
+		w.crossPosts = append(w.crossPosts, post)
+	}
+
+	pending := w.pending[:0]
+	for _, j := range w.pending {
+		if !drop[j.result] {
+			pending = append(pending, j)
+		}
+	}
+	w.pending = pending
+
+	// items only there because of a cross-posted comment
+	w.removeItems(emptied)
+}
+
+// removeAction removes the action from the item, returning true if the item
+// was left without actions.
+func (w *work) removeAction(id id, a *action) bool {
+	actions := w.actions[id][:0]
+	for _, other := range w.actions[id] {
+		if other != a {
+			actions = append(actions, other)
+		}
+	}
+	if len(actions) == 0 {
+		delete(w.actions, id)
+		return true
+	}
+	w.actions[id] = actions
+	return false
+}
+
+// crossPostsReport returns the cross-posted comments, once each, with where
+// they were posted.
+func (w *work) crossPostsReport() string {
+	if len(w.crossPosts) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Cross-posted comments:"))
+	for _, post := range w.crossPosts {
+		report += fmt.Sprintf("Comment: %s\n", *post.content)
+		var destinations []string
+		for _, meta := range post.destinations {
+			destinations = append(destinations, fmt.Sprintf("%s%s (%s) %s", meta.repo, meta.eventId, meta.url, meta.title))
+		}
+		report += fmt.Sprintf("Posted on %d items: %s\n", len(destinations), strings.Join(destinations, "; "))
+	}
+
+	return report
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestCollapseCrossPosts(t *testing.T) {
+	w := &work{
+		issues:  make(map[id]*metadata),
+		pulls:   make(map[id]*metadata),
+		actions: make(map[id][]*action),
+	}
+	add := func(repo string, number int, object, content string) {
+		item := newID(repo, number)
+		if _, ok := w.issues[item]; !ok {
+			w.issues[item] = &metadata{eventId: item, repo: repo}
+			w.pending = append(w.pending, &job{result: &w.issues[item].description})
+		}
+		a := &action{action: "created", object: object, content: content}
+		w.actions[item] = append(w.actions[item], a)
+		w.pending = append(w.pending, &job{result: &a.content})
+	}
+
+	add("owner/a", 1, ObjectIssueComment, "Released v1.0!")
+	add("owner/b", 2, ObjectIssueComment, "Released v1.0!\n")
+	add("owner/b", 2, ObjectIssue, "Released v1.0!") // not a comment
+	add("owner/c", 3, ObjectIssueComment, "Released v1.0!")
+	add("owner/c", 4, ObjectIssueComment, "Something else")
+	add("owner/c", 5, ObjectIssueComment, "Same item twice")
+	add("owner/c", 5, ObjectIssueComment, "Same item twice")
+
+	w.collapseCrossPosts()
+
+	if len(w.crossPosts) != 1 {
+		t.Fatalf("got %d cross posts, want 1", len(w.crossPosts))
+	}
+	if got := len(w.crossPosts[0].destinations); got != 3 {
+		t.Errorf("got %d destinations, want 3", got)
+	}
+	for _, gone := range []id{newID("owner/a", 1), newID("owner/c", 3)} {
+		if _, ok := w.issues[gone]; ok {
+			t.Errorf("%s%s is only the cross-posted comment, but was kept", gone.repo, gone)
+		}
+	}
+	if got := len(w.actions[newID("owner/b", 2)]); got != 1 {
+		t.Errorf("owner/b#2 has %d actions, want 1", got)
+	}
+	if got := len(w.actions[newID("owner/c", 5)]); got != 2 {
+		t.Errorf("owner/c#5 has %d actions, want 2", got)
+	}
+
+	// b#2 (description and issue), c#4 (description and comment), c#5
+	// (description and comments) and the cross-posted comment
+	if got := len(w.pending); got != 8 {
+		t.Errorf("got %d pending jobs, want 8", got)
+	}
+}
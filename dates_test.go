@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDay(t *testing.T) {
+	location = time.UTC
+
+	tests := []struct {
+		arg     string
+		want    time.Time
+		day     bool
+		wantErr bool
+	}{
+		{"2024-03-05", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), true, false},
+		{"2024-03-05T10:30:00Z", time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC), false, false},
+		{"2024-03-05T10:30:00+01:00", time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC), false, false},
+		{"05/03/2024", time.Time{}, false, true},
+		{"2024-02-30", time.Time{}, false, true},
+		{"", time.Time{}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, day, err := parseDay(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDay(%q) error = %v, want error %v", tt.arg, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) || day != tt.day {
+				t.Errorf("parseDay(%q) = %v, %v, want %v, %v", tt.arg, got, day, tt.want, tt.day)
+			}
+		})
+	}
+}
+
+func TestPickRange(t *testing.T) {
+	location = time.UTC
+	future := time.Now().AddDate(0, 0, 2).Format("2006-01-02")
+
+	tests := []struct {
+		name, from, to string
+		begin, end     time.Time
+		wantErr        bool
+	}{
+		{"open ended", "2024-03-05", "", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"to day is inclusive", "2024-03-05", "2024-03-07", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), false},
+		{"single day", "2024-03-05", "2024-03-05", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), false},
+		{"to timestamp is exact", "2024-03-05", "2024-03-05T12:00:00Z", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC), false},
+		{"to before from", "2024-03-05", "2024-03-04", time.Time{}, time.Time{}, true},
+		{"to equals from timestamp", "2024-03-05T12:00:00Z", "2024-03-05T12:00:00Z", time.Time{}, time.Time{}, true},
+		{"from in the future", future, "", time.Time{}, time.Time{}, true},
+		{"invalid from", "yesterday", "", time.Time{}, time.Time{}, true},
+		{"invalid to", "2024-03-05", "tomorrow", time.Time{}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			begin, end, err := pickRange(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pickRange(%q, %q) error = %v, want error %v", tt.from, tt.to, err, tt.wantErr)
+			}
+			if !begin.Equal(tt.begin) || !end.Equal(tt.end) {
+				t.Errorf("pickRange(%q, %q) = %v, %v, want %v, %v", tt.from, tt.to, begin, end, tt.begin, tt.end)
+			}
+		})
+	}
+}
+
+func TestDaysSinceWeekStart(t *testing.T) {
+	defer func() { weekStart = time.Monday }()
+
+	tests := []struct {
+		start time.Weekday
+		day   time.Time
+		want  int
+	}{
+		{time.Monday, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), 0},  // Monday
+		{time.Monday, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), 2},  // Wednesday
+		{time.Monday, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), 6}, // Sunday
+		{time.Sunday, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), 0}, // Sunday
+		{time.Sunday, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), 1},  // Monday
+		{time.Sunday, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), 6},  // Saturday
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.start.String()+"/"+tt.day.Weekday().String(), func(t *testing.T) {
+			weekStart = tt.start
+			if got := daysSinceWeekStart(tt.day); got != tt.want {
+				t.Errorf("daysSinceWeekStart(%s) with the week starting on %s = %d, want %d", tt.day.Weekday(), tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+// setLocation sets the location for the test, restoring it when it ends.
+func setLocation(t *testing.T, loc *time.Location) {
+	saved := location
+	t.Cleanup(func() { location = saved })
+	location = loc
+}
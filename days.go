@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Days Off
+
+// dayActivity is the activity of a single day of the period.
+type dayActivity struct {
+	day    time.Time // midnight, in the report timezone
+	events int
+	note   string // from the calendar (vacation, sick, conference, ...)
+}
+
+// parseCalendar returns the notes of the calendar days. Keys are days
+// (YYYY-MM-DD) or ranges of days (YYYY-MM-DD..YYYY-MM-DD, inclusive).
+func parseCalendar(calendar map[string]string) (map[string]string, error) {
+	notes := make(map[string]string)
+	for key, note := range calendar {
+		first, last, isRange := strings.Cut(key, "..")
+		if !isRange {
+			last = first
+		}
+		begin, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(first), location)
+		if err != nil {
+			return nil, fmt.Errorf("calendar: invalid day %q", key)
+		}
+		end, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(last), location)
+		if err != nil || end.Before(begin) {
+			return nil, fmt.Errorf("calendar: invalid days %q", key)
+		}
+		for day := begin; !day.After(end); day = day.AddDate(0, 0, 1) {
+			notes[day.Format("2006-01-02")] = note
+		}
+	}
+	return notes, nil
+}
+
+// collectDays counts the events of each day of the period (up to today), and
+// annotates the days with the calendar notes.
+func (w *work) collectDays(events []*github.Event, begin, end time.Time, notes map[string]string) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	counts := make(map[string]int)
+	for _, e := range events {
+		counts[e.GetCreatedAt().In(location).Format("2006-01-02")]++
+	}
+
+	w.days = nil
+	for day := startOfDay(begin); day.Before(end); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		w.days = append(w.days, &dayActivity{day: day, events: counts[key], note: notes[key]})
+	}
+}
+
+// daysReport returns the days without activity (and why, if known) and the
+// average activity of the days worked, so days off don't skew it.
+func (w *work) daysReport() string {
+	if len(w.days) < 2 {
+		return ""
+	}
+
+	active, events := 0, 0
+	var off []string
+	for _, d := range w.days {
+		if d.events > 0 {
+			active++
+			events += d.events
+			if d.note != "" {
+				off = append(off, fmt.Sprintf("%s (%s, but active)", d.day.Format("Mon 2006-01-02"), d.note))
+			}
+			continue
+		}
+		note := d.note
+		if note == "" && (d.day.Weekday() == time.Saturday || d.day.Weekday() == time.Sunday) {
+			note = "weekend"
+		}
+		if note == "" {
+			note = "no activity"
+		}
+		off = append(off, fmt.Sprintf("%s (%s)", d.day.Format("Mon 2006-01-02"), note))
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Days:"))
+	report += fmt.Sprintf("Active days: %d of %d", active, len(w.days))
+	if active > 0 {
+		report += fmt.Sprintf(", %.1f events per active day", float64(events)/float64(active))
+	}
+	report += "\n"
+	for _, day := range off {
+		report += fmt.Sprintf("  %s\n", day)
+	}
+
+	return report
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestParseCalendar(t *testing.T) {
+	location = time.UTC
+
+	notes, err := parseCalendar(map[string]string{
+		"2024-03-04":             "sick",
+		"2024-03-06..2024-03-08": "conference",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"2024-03-04": "sick",
+		"2024-03-06": "conference",
+		"2024-03-07": "conference",
+		"2024-03-08": "conference",
+	}
+	if len(notes) != len(want) {
+		t.Errorf("got %d days, want %d", len(notes), len(want))
+	}
+	for day, note := range want {
+		if notes[day] != note {
+			t.Errorf("%s: got %q, want %q", day, notes[day], note)
+		}
+	}
+
+	for _, bad := range []string{"2024-3-4", "2024-03-08..2024-03-06", "2024-03-06..tomorrow"} {
+		if _, err := parseCalendar(map[string]string{bad: "vacation"}); err == nil {
+			t.Errorf("parseCalendar(%q) didn't fail", bad)
+		}
+	}
+}
+
+func TestDaysReport(t *testing.T) {
+	location = time.UTC
+	at := func(day int) *github.Event {
+		created := time.Date(2024, 3, day, 12, 0, 0, 0, time.UTC)
+		return &github.Event{CreatedAt: &created}
+	}
+
+	w := &work{}
+	begin := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC) // Monday
+	end := begin.AddDate(0, 0, 7)
+	events := []*github.Event{at(4), at(4), at(5), at(8), at(8), at(8)}
+	w.collectDays(events, begin, end, map[string]string{"2024-03-06": "vacation", "2024-03-08": "conference"})
+
+	report := w.daysReport()
+	for _, want := range []string{
+		"Active days: 3 of 7, 2.0 events per active day",
+		"Wed 2024-03-06 (vacation)",
+		"Thu 2024-03-07 (no activity)",
+		"Fri 2024-03-08 (conference, but active)",
+		"Sat 2024-03-09 (weekend)",
+		"Sun 2024-03-10 (weekend)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
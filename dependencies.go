@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Dependency Updates
+
+// dependencyTitle matches the titles of the dependency update pull requests:
+// "Bump x from 1.0 to 1.1" (Dependabot), "Update dependency x to v2"
+// (Renovate) and the deps scope of conventional commits.
+var dependencyTitle = regexp.MustCompile(`(?i)^((\w+:\s*)?(bump|update|upgrade)\s+\S+\s+from\s+\S+\s+to\s|(update|upgrade)\s+(dependency|dependencies|module)\s|\w+\(deps[^)]*\)!?:)`)
+
+// dependencyBranches are the branch prefixes of the dependency update bots.
+var dependencyBranches = []string{"dependabot/", "renovate/"}
+
+// isDependencyUpdate returns true if the pull request updates dependencies:
+// opened from a Dependabot or Renovate branch, labelled dependencies, or
+// titled like it ("Bump x from 1.0 to 1.1", "chore(deps): update y").
+func isDependencyUpdate(meta *metadata) bool {
+	if _, branch, ok := strings.Cut(meta.head, ":"); ok {
+		for _, prefix := range dependencyBranches {
+			if strings.HasPrefix(branch, prefix) {
+				return true
+			}
+		}
+	}
+	for _, label := range meta.labels {
+		if label == "dependencies" || label == "deps" {
+			return true
+		}
+	}
+	return dependencyTitle.MatchString(meta.title)
+}
+
+// dependencyGroup is the dependency update pull requests, as a single item.
+type dependencyGroup struct {
+	pulls   []*metadata
+	actions map[string]int // what I did (merged, reviewed, ...) and how often
+}
+
+// groupDependencyPulls moves the dependency update pull requests, with their
+// actions, out of the pull requests into a single dependency maintenance item,
+// so they aren't summarized and reported one by one.
+func (w *work) groupDependencyPulls() {
+	group := &dependencyGroup{actions: make(map[string]int)}
+	grouped := make(map[id]bool)
+	for _, id := range w.sortedIds(w.pulls) {
+		meta := w.pulls[id]
+		if !isDependencyUpdate(meta) {
+			continue
+		}
+		group.pulls = append(group.pulls, meta)
+		for _, a := range w.actions[id] {
+			group.actions[a.action]++
+		}
+		grouped[id] = true
+	}
+	if len(grouped) < 2 {
+		return // nothing to group
+	}
+
+	w.dependencies = group
+	w.removeItems(grouped)
+}
+
+// dependenciesReport returns the dependency maintenance item: how many pull
+// requests, what I did on them, their total size, and per repository.
+func (w *work) dependenciesReport() string {
+	g := w.dependencies
+	if g == nil {
+		return ""
+	}
+
+	additions, deletions, files, sized := 0, 0, 0, 0
+	perRepo := make(map[string][]string)
+	for _, meta := range g.pulls {
+		if meta.diffStat {
+			additions += meta.additions
+			deletions += meta.deletions
+			files += meta.changedFiles
+			sized++
+		}
+		perRepo[meta.repo] = append(perRepo[meta.repo], fmt.Sprintf("%s %s", meta.eventId, meta.title))
+	}
+
+	kinds := make([]string, 0, len(g.actions))
+	for kind := range g.actions {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var actions []string
+	for _, kind := range kinds {
+		actions = append(actions, fmt.Sprintf("%s %d", kind, g.actions[kind]))
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Dependency maintenance:"))
+	report += fmt.Sprintf("Pull requests: %d (%s)\n", len(g.pulls), strings.Join(actions, ", "))
+	if sized > 0 {
+		size := fmt.Sprintf("Size: +%d -%d lines, %d files", additions, deletions, files)
+		if sized < len(g.pulls) {
+			size += fmt.Sprintf(" (of %d of them)", sized)
+		}
+		report += size + "\n"
+	}
+
+	repos := make([]string, 0, len(perRepo))
+	for repo := range perRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		report += fmt.Sprintf("Repository: %s: %s\n", repo, strings.Join(perRepo[repo], "; "))
+	}
+
+	return report
+}
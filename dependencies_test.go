@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsDependencyUpdate(t *testing.T) {
+	tests := []struct {
+		meta *metadata
+		want bool
+	}{
+		{&metadata{title: "Bump golang.org/x/net from 0.17.0 to 0.23.0"}, true},
+		{&metadata{title: "chore(deps): update module github.com/spf13/cobra to v1.8.0"}, true},
+		{&metadata{title: "fix(deps): update dependency eslint to v9"}, true},
+		{&metadata{title: "Update README", head: "owner/repo:renovate/configure"}, true},
+		{&metadata{title: "Sync go.mod", labels: []string{"dependencies"}}, true},
+		{&metadata{title: "Update the docs", head: "me/repo:docs"}, false},
+		{&metadata{title: "Bump the version in the Makefile"}, false},
+	}
+	for _, tt := range tests {
+		if got := isDependencyUpdate(tt.meta); got != tt.want {
+			t.Errorf("isDependencyUpdate(%q) = %v, want %v", tt.meta.title, got, tt.want)
+		}
+	}
+}
+
+func TestGroupDependencyPulls(t *testing.T) {
+	bump1, bump2, feature := newID("o/r", 1), newID("o/s", 2), newID("o/r", 3)
+	w := &work{
+		pulls: map[id]*metadata{
+			bump1:   {eventId: bump1, repo: "o/r", title: "Bump a from 1 to 2", diffStat: true, additions: 10, deletions: 10, changedFiles: 2},
+			bump2:   {eventId: bump2, repo: "o/s", title: "Bump b from 3 to 4"},
+			feature: {eventId: feature, repo: "o/r", title: "Add the feature"},
+		},
+		actions: map[id][]*action{
+			bump1:   {{action: "merged"}},
+			bump2:   {{action: "merged"}, {action: "created", object: ObjectPRComment}},
+			feature: {{action: "opened"}},
+		},
+	}
+
+	w.groupDependencyPulls()
+
+	if len(w.pulls) != 1 || w.pulls[feature] == nil {
+		t.Fatalf("got pulls %v, want only the feature", w.pulls)
+	}
+	report := w.dependenciesReport()
+	for _, want := range []string{
+		"Pull requests: 2 (created 1, merged 2)\n",
+		"Size: +10 -10 lines, 2 files (of 1 of them)\n",
+		"Repository: o/r: #1 Bump a from 1 to 2\n",
+		"Repository: o/s: #2 Bump b from 3 to 4\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q doesn't contain %q", report, want)
+		}
+	}
+}
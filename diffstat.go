@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Pull Request Diff Statistics
+
+// setDiffStat records the size of the pull request, if the payload has it.
+func (m *metadata) setDiffStat(pr *github.PullRequest) {
+	if pr.ChangedFiles == nil {
+		return // not in the payloads of comments and reviews
+	}
+	m.additions, m.deletions, m.changedFiles = pr.GetAdditions(), pr.GetDeletions(), pr.GetChangedFiles()
+	m.diffStat = true
+}
+
+// diffStatString returns the size of the pull request, for the prompts (empty
+// if unknown).
+func (m *metadata) diffStatString() string {
+	if !m.diffStat {
+		return ""
+	}
+	files := "files"
+	if m.changedFiles == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("+%d -%d lines, %d %s", m.additions, m.deletions, m.changedFiles, files)
+}
+
+// collectDiffStats fetches the size of the pull requests I authored (and, if
+// asked to, of the dependency updates) that the events didn't have it for.
+func collectDiffStats(ctx context.Context, gh *github.Client, w *work, dependencies bool) {
+	for _, id := range w.sortedIds(w.pulls) {
+		meta := w.pulls[id]
+		wanted := meta.author || dependencies && isDependencyUpdate(meta)
+		if !wanted || meta.diffStat {
+			continue
+		}
+		owner, name, _ := strings.Cut(meta.repo, "/")
+		pr, _, err := gh.PullRequests.Get(ctx, owner, name, id.number)
+		if err != nil {
+			fmt.Printf("Error fetching the size of %s%s: %v\n", meta.repo, id, err)
+			continue
+		}
+		meta.setDiffStat(pr)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestDiffStat(t *testing.T) {
+	meta := &metadata{}
+	meta.setDiffStat(&github.PullRequest{}) // a comment payload
+	if got := meta.diffStatString(); got != "" {
+		t.Errorf("got %q, want no size", got)
+	}
+
+	meta.setDiffStat(&github.PullRequest{Additions: github.Int(3000), Deletions: github.Int(12), ChangedFiles: github.Int(40)})
+	if got, want := meta.diffStatString(), "+3000 -12 lines, 40 files"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	meta.setDiffStat(&github.PullRequest{Additions: github.Int(1), Deletions: github.Int(1), ChangedFiles: github.Int(1)})
+	if got, want := meta.diffStatString(), "+1 -1 lines, 1 file"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Discussions
+
+const discussionsQuery = `
+query($login: String!, $discussions: String, $comments: String) {
+  user(login: $login) {
+    repositoryDiscussions(first: 50, after: $discussions, orderBy: {field: CREATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes { ...discussion body createdAt updatedAt }
+    }
+    repositoryDiscussionComments(first: 50, after: $comments) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        body url createdAt updatedAt isAnswer
+        discussion { ...discussion }
+      }
+    }
+  }
+}
+
+fragment discussion on Discussion {
+  number title url
+  repository { nameWithOwner isPrivate }
+}`
+
+type discussionNode struct {
+	Number     int              `json:"number"`
+	Title      string           `json:"title"`
+	URL        string           `json:"url"`
+	Body       string           `json:"body"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+	Repository contributionRepo `json:"repository"`
+}
+
+type discussion struct {
+	repo    string // owner/repo (lowercase)
+	number  int
+	title   string
+	url     string
+	actions []*action // created, answered, commented
+}
+
+// collectDiscussions adds the discussions the user created, answered or
+// commented on, between the begin and end dates, allowed by the filters. The
+// events API doesn't have all of them, so they come from GraphQL.
+func collectDiscussions(ctx context.Context, gh *github.Client, w *work, filter *filters, begin, end time.Time) error {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	inPeriod := func(at time.Time) bool {
+		return !at.Before(begin) && at.Before(end)
+	}
+
+	vars := map[string]interface{}{"login": w.user}
+	more := map[string]bool{"discussions": true, "comments": true}
+
+	for more["discussions"] || more["comments"] {
+		var data struct {
+			User *struct {
+				RepositoryDiscussions struct {
+					PageInfo pageInfo         `json:"pageInfo"`
+					Nodes    []discussionNode `json:"nodes"`
+				} `json:"repositoryDiscussions"`
+				RepositoryDiscussionComments struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						Body       string         `json:"body"`
+						URL        string         `json:"url"`
+						CreatedAt  time.Time      `json:"createdAt"`
+						UpdatedAt  time.Time      `json:"updatedAt"`
+						IsAnswer   bool           `json:"isAnswer"`
+						Discussion discussionNode `json:"discussion"`
+					} `json:"nodes"`
+				} `json:"repositoryDiscussionComments"`
+			} `json:"user"`
+		}
+		if err := graphQL(ctx, gh, discussionsQuery, vars, &data); err != nil {
+			return err
+		}
+		if data.User == nil {
+			return fmt.Errorf("user %s not found", w.user)
+		}
+
+		if more["discussions"] {
+			discussions := data.User.RepositoryDiscussions
+			more["discussions"] = nextPage(vars, "discussions", discussions.PageInfo)
+			for _, d := range discussions.Nodes {
+				if d.CreatedAt.Before(begin) {
+					more["discussions"] = false // newest first
+					break
+				}
+				if inPeriod(d.CreatedAt) && w.allowsDiscussion(ctx, filter, &d) {
+					w.addDiscussionAction(&d, "created", d.Body, d.CreatedAt, d.UpdatedAt)
+				}
+			}
+		}
+		if more["comments"] {
+			comments := data.User.RepositoryDiscussionComments
+			more["comments"] = nextPage(vars, "comments", comments.PageInfo)
+			for _, c := range comments.Nodes {
+				if !inPeriod(c.CreatedAt) || !w.allowsDiscussion(ctx, filter, &c.Discussion) {
+					continue
+				}
+				kind := "commented"
+				if c.IsAnswer {
+					kind = "answered"
+				}
+				w.addDiscussionAction(&c.Discussion, kind, c.Body, c.CreatedAt, c.UpdatedAt)
+			}
+		}
+	}
+
+	return nil
+}
+
+// allowsDiscussion returns true if the filters allow the discussion
+// repository.
+func (w *work) allowsDiscussion(ctx context.Context, filter *filters, d *discussionNode) bool {
+	e := &github.Event{
+		Repo:   &github.Repository{Name: github.String(d.Repository.NameWithOwner)},
+		Public: github.Bool(!d.Repository.IsPrivate),
+	}
+	return filter.allows(ctx, e)
+}
+
+// addDiscussionAction records an action on a discussion, summarizing what I
+// wrote.
+func (w *work) addDiscussionAction(d *discussionNode, kind, body string, at, updated time.Time) {
+	disc, ok := w.discussions[d.URL]
+	if !ok {
+		disc = &discussion{
+			repo:   strings.ToLower(d.Repository.NameWithOwner),
+			number: d.Number,
+			title:  d.Title,
+			url:    d.URL,
+		}
+		w.discussions[d.URL] = disc
+		w.discussionOrder = append(w.discussionOrder, d.URL)
+	}
+
+	a := &action{action: kind, object: ObjectDiscussion, content: body, updated: updated, at: at}
+	disc.actions = append(disc.actions, a)
+
+	e := &github.Event{
+		Type:      github.String("DiscussionEvent"),
+		Repo:      &github.Repository{Name: github.String(d.Repository.NameWithOwner)},
+		CreatedAt: &at,
+	}
+	w.pending = append(w.pending, descriptionSummary(e, newID(disc.repo, disc.number), updated, &a.content))
+}
+
+// discussionsReport returns the discussions section of the report.
+func (w *work) discussionsReport() string {
+	if len(w.discussions) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Discussions:"))
+	for _, url := range w.discussionOrder {
+		d := w.discussions[url]
+		report += fmt.Sprintf("Discussion: %s#%d (%s) %s\n", d.repo, d.number, d.url, d.title)
+		for _, a := range d.actions {
+			report += fmt.Sprintf("%s: %s\n", a.action, a.content)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscussionsReport(t *testing.T) {
+	w := &work{discussions: make(map[string]*discussion)}
+	d := &discussionNode{
+		Number: 42,
+		Title:  "How do I trace execve?",
+		URL:    "https://github.com/owner/repo/discussions/42",
+	}
+	d.Repository.NameWithOwner = "Owner/Repo"
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	w.addDiscussionAction(d, "answered", "Use the execve event.", at, at)
+	w.addDiscussionAction(d, "commented", "Glad it helped.", at.Add(time.Hour), at.Add(time.Hour))
+
+	if len(w.discussions) != 1 || len(w.pending) != 2 {
+		t.Fatalf("got %d discussions and %d jobs, want 1 and 2", len(w.discussions), len(w.pending))
+	}
+
+	want := "Discussion: owner/repo#42 (https://github.com/owner/repo/discussions/42) How do I trace execve?\n" +
+		"answered: Use the execve event.\n" +
+		"commented: Glad it helped.\n"
+	if report := w.discussionsReport(); !strings.Contains(report, want) {
+		t.Errorf("report doesn't have %q:\n%s", want, report)
+	}
+}
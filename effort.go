@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Effort Estimation
+
+// effortWeights turn what happened on an item into estimated hours. Zero
+// weights are the defaults.
+type effortWeights struct {
+	Span    float64 `yaml:"span,omitempty"`    // hours per hour from my first to last action (not waiting on others)
+	Comment float64 `yaml:"comment,omitempty"` // hours per comment, or review
+	Commit  float64 `yaml:"commit,omitempty"`  // hours per commit pushed
+	Line    float64 `yaml:"line,omitempty"`    // hours per line changed, on my pull requests
+	Max     float64 `yaml:"max,omitempty"`     // most hours for a single item
+}
+
+// defaultEffort are the weights used when none are configured.
+var defaultEffort = effortWeights{Span: 0.05, Comment: 0.25, Commit: 0.25, Line: 0.01, Max: 24}
+
+// withDefaults returns the weights, the ones not configured set to the
+// defaults.
+func (e *effortWeights) withDefaults() *effortWeights {
+	weights := defaultEffort
+	if e == nil {
+		return &weights
+	}
+	for _, w := range []struct{ value, def *float64 }{
+		{&e.Span, &weights.Span}, {&e.Comment, &weights.Comment}, {&e.Commit, &weights.Commit},
+		{&e.Line, &weights.Line}, {&e.Max, &weights.Max},
+	} {
+		if *w.value > 0 {
+			*w.def = *w.value
+		}
+	}
+	return &weights
+}
+
+// estimateEffort returns the hours the item probably took: the time from my
+// first to last action on it (waiting on others apart), the comments and
+// commits, and the lines changed on my pull requests, weighted, and capped.
+// Rounded to a quarter of an hour. The hours of an override win.
+func (w *work) estimateEffort(id id, weights *effortWeights) float64 {
+	if hours, ok := w.overriddenHours(id); ok {
+		return hours
+	}
+	meta := w.getIssueOrPR(id)
+	elapsed, waiting := w.timeSplit(id)
+
+	hours := weights.Span * (elapsed - waiting).Hours()
+	for _, a := range w.actions[id] {
+		switch a.object {
+		case ObjectIssueComment, ObjectPRComment:
+			hours += weights.Comment
+		case ObjectCommit:
+			hours += weights.Commit
+		}
+	}
+	if meta.author && meta.diffStat {
+		hours += weights.Line * float64(meta.additions+meta.deletions)
+	}
+
+	hours = math.Max(hours, 0.25) // touching it took some time
+	return math.Round(math.Min(hours, weights.Max)*4) / 4
+}
+
+// effortReport returns the estimated effort on all the items, in total.
+func (w *work) effortReport(weights *effortWeights) string {
+	total := 0.0
+	items := 0
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for id := range place {
+			total += w.estimateEffort(id, weights)
+			items++
+		}
+	}
+	if items == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n%s %.2fh over %d issues and pull requests\n", msg("Estimated effort:"), total, items)
+}
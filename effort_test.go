@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateEffort(t *testing.T) {
+	at := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	pull, issue := newID("owner/repo", 1), newID("owner/repo", 2)
+	w := &work{
+		issues: map[id]*metadata{issue: {}},
+		pulls:  map[id]*metadata{pull: {author: true, diffStat: true, additions: 150, deletions: 50}},
+		actions: map[id][]*action{
+			pull: {
+				{action: "opened", object: ObjectPR, at: at},
+				{action: "pushed", object: ObjectCommit, at: at.Add(time.Hour)},
+				{action: "created", object: ObjectPRComment, at: at.Add(10 * time.Hour)},
+			},
+			issue: {{action: "labeled", object: ObjectIssue, at: at}},
+		},
+		responses: make(map[id][]time.Time),
+	}
+	weights := (&effortWeights{Line: 0.02}).withDefaults()
+	if weights.Span != defaultEffort.Span || weights.Line != 0.02 {
+		t.Fatalf("got weights %+v", weights)
+	}
+
+	// 10h span * 0.05 + 1 commit * 0.25 + 1 comment * 0.25 + 200 lines * 0.02
+	if got := w.estimateEffort(pull, weights); got != 5.0 {
+		t.Errorf("got %.2fh, want 5h", got)
+	}
+	if got := w.estimateEffort(issue, weights); got != 0.25 {
+		t.Errorf("got %.2fh, want the minimum", got)
+	}
+	weights.Max = 2
+	if got := w.estimateEffort(pull, weights); got != 2 {
+		t.Errorf("got %.2fh, want the cap", got)
+	}
+
+	if got, want := w.effortReport(weights), "\nEstimated effort: 2.25h over 2 issues and pull requests\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := (&work{}).effortReport(weights); got != "" {
+		t.Errorf("got %q, want no total", got)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Run Estimate
+
+// callLatency is how long a typical call takes to be answered.
+const callLatency = 8 * time.Second
+
+// runEstimate is what the run is expected to take, before summarizing.
+type runEstimate struct {
+	Events  int     `json:"events"`
+	Items   int     `json:"items"`
+	Calls   int     `json:"calls"`
+	Tokens  int     `json:"tokens"`
+	Cost    float64 `json:"cost_usd"`
+	Seconds float64 `json:"wall_seconds"`
+
+	tokens *tokens // to check against the budget
+}
+
+// estimateRun returns the expected calls, tokens, cost and wall time of the
+// run. The wall time is the slowest of the workers going through the calls
+// and the rate limits letting them through.
+func estimateRun(model string, events int, w *work, format string, workers int, limiter *rateLimiter) *runEstimate {
+	t := estimateTokens(model, w, format)
+
+	if workers < 1 {
+		workers = 1
+	}
+	wall := math.Ceil(float64(t.calls)/float64(workers)) * callLatency.Seconds()
+	if limiter.rpm > 0 {
+		wall = math.Max(wall, float64(t.calls)/float64(limiter.rpm)*60)
+	}
+	if limiter.tpm > 0 {
+		wall = math.Max(wall, float64(t.prompt+t.completion)/float64(limiter.tpm)*60)
+	}
+
+	return &runEstimate{
+		Events:  events,
+		Items:   len(w.issues) + len(w.pulls),
+		Calls:   t.calls,
+		Tokens:  t.prompt + t.completion,
+		Cost:    t.cost(model),
+		Seconds: wall,
+		tokens:  t,
+	}
+}
+
+func (e *runEstimate) String() string {
+	return fmt.Sprintf("Estimated: %d events, %d items, %d calls, %d tokens, $%.2f, %s",
+		e.Events, e.Items, e.Calls, e.Tokens, e.Cost, time.Duration(e.Seconds*float64(time.Second)).Round(time.Second),
+	)
+}
+
+// JSON returns the estimate for scripts.
+func (e *runEstimate) JSON() string {
+	data, _ := json.MarshalIndent(e, "", "  ")
+	return string(data)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+)
+
+// GitHub Response Cache
+
+// etagTransport stores GitHub responses on disk and revalidates them with
+// conditional requests (If-None-Match). Conditional requests answered with
+// 304 Not Modified don't count against the GitHub rate limit.
+type etagTransport struct {
+	dir  string // empty disables the cache
+	base http.RoundTripper
+}
+
+// path returns the cache file of the request. The credentials are part of the
+// key, so different tokens never share responses.
+func (t *etagTransport) path(req *http.Request) string {
+	key := req.URL.String() + "\x00" + req.Header.Get("Authorization") + "\x00" + req.Header.Get("Accept")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:]))
+}
+
+// load returns the cached response of the request, if any.
+func (t *etagTransport) load(req *http.Request) *http.Response {
+	data, err := os.ReadFile(t.path(req))
+	if err != nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return nil
+	}
+	return resp
+}
+
+// store saves the response (and gives back an unread body to the caller).
+func (t *etagTransport) store(req *http.Request, resp *http.Response) {
+	data, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return
+	}
+	writeFileAtomic(t.path(req), data)
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.dir == "" || req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	cached := t.load(req)
+	if cached != nil && cached.Header.Get("ETag") != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.Header.Get("ETag"))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		// keep the fresh rate limit (and token expiration) information
+		for _, h := range []string{"X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Reset", "Date", tokenExpirationHeader} {
+			if v := resp.Header.Get(h); v != "" {
+				cached.Header.Set(h, v)
+			}
+		}
+		return cached, nil
+	}
+	if cached != nil {
+		cached.Body.Close()
+	}
+
+	if resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "" {
+		t.store(req, resp)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Model Fallback
+
+// ollamaPrefix marks the local (Ollama) models in the fallback chain.
+const ollamaPrefix = "ollama:"
+
+// chatModel is one of the models the summaries may be created with: an
+// OpenAI model or a local Ollama model.
+type chatModel struct {
+	name   string
+	openai *openai.Chat
+	ollama string       // Ollama server URL, for local models
+	http   *http.Client // for local models
+}
+
+// generate returns the model answer, accounting the tokens used to the item.
+func (m *chatModel) generate(item, role, instr string, length int) (string, error) {
+	if m.openai == nil {
+		return m.generateLocal(item, role, instr, length)
+	}
+
+	openAILimiter.wait(countTokens(openAIModel, role+instr) + length)
+
+	answers, err := m.openai.Generate(
+		context.Background(),
+		[][]schema.ChatMessage{{
+			schema.SystemChatMessage{Content: role},
+			schema.HumanChatMessage{Content: instr},
+		}},
+		llms.WithTemperature(0.2),
+		llms.WithMaxLength(length),
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(answers) == 0 || answers[0].Message == nil {
+		return "", nil
+	}
+	info := answers[0].GenerationInfo
+	promptTokens, _ := info["PromptTokens"].(int)
+	completionTokens, _ := info["CompletionTokens"].(int)
+	tokenUsage.add(item, m.name, promptTokens, completionTokens)
+
+	return answers[0].Message.GetContent(), nil
+}
+
+// generateLocal returns the answer of the local model.
+func (m *chatModel) generateLocal(item, role, instr string, length int) (string, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"model": strings.TrimPrefix(m.name, ollamaPrefix),
+		"messages": []map[string]string{
+			{"role": "system", "content": role},
+			{"role": "user", "content": instr},
+		},
+		"stream": false,
+		"options": map[string]interface{}{
+			"temperature": 0.2,
+			"num_predict": length,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := m.http.Post(m.ollama+"/api/chat", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ollama: %s", resp.Status)
+	}
+
+	answer := struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return "", err
+	}
+	tokenUsage.add(item, m.name, answer.PromptEvalCount, answer.EvalCount)
+
+	return answer.Message.Content, nil
+}
+
+// modelChain is the primary model followed by the models to fall back to,
+// in order, when the current one is out of quota or unavailable.
+type modelChain struct {
+	mu      sync.Mutex
+	models  []*chatModel
+	current int
+	notes   []string // the fallbacks that happened, and why
+}
+
+// newModelChain returns the chain starting with the primary OpenAI model. The
+// fallbacks are OpenAI models, or local models (ollama:MODEL) served at the
+// Ollama URL.
+func newModelChain(primary *openai.Chat, fallbacks []string, newClient func(model string) (*openai.Chat, error), ollamaURL string) (*modelChain, error) {
+	c := &modelChain{models: []*chatModel{{name: openAIModel, openai: primary}}}
+
+	for _, name := range fallbacks {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.HasPrefix(name, ollamaPrefix) {
+			c.models = append(c.models, &chatModel{
+				name:   name,
+				ollama: strings.TrimSuffix(ollamaURL, "/"),
+				http:   &http.Client{Timeout: 10 * time.Minute},
+			})
+			continue
+		}
+		client, err := newClient(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		c.models = append(c.models, &chatModel{name: name, openai: client})
+	}
+
+	return c, nil
+}
+
+// model returns the model in use.
+func (c *modelChain) model() *chatModel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.models[c.current]
+}
+
+// fallBack moves past the failed model, returning false if there is no model
+// left to fall back to. Concurrent calls failing on the same model fall back
+// only once.
+func (c *modelChain) fallBack(failed *chatModel, item string, err error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.models[c.current] != failed {
+		return true // another call fell back already
+	}
+	if c.current == len(c.models)-1 {
+		return false
+	}
+	c.current++
+	c.notes = append(c.notes, fmt.Sprintf("  %s -> %s (at %s): %v\n", failed.name, c.models[c.current].name, item, err))
+
+	return true
+}
+
+// report returns the fallbacks that happened during the run, if any.
+func (c *modelChain) report() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.notes) == 0 {
+		return ""
+	}
+	return "Model fallback:\n\n" + strings.Join(c.notes, "")
+}
+
+// unavailable returns true if the model failed for good: out of quota, still
+// rate limited after the retries, or the model does not exist (or isn't
+// available to the account).
+func unavailable(err error) bool {
+	msg := err.Error()
+	for _, s := range []string{
+		"status code: 429",
+		"status code: 404",
+		"insufficient_quota",
+		"model_not_found",
+		"does not exist",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
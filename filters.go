@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Collection Filters
+
+// filters decide which events are collected at all, so what is sent to the
+// LLM matches exactly what the report should cover.
+type filters struct {
+	repos    map[string]bool // only events in these repositories (owner/repo)
+	orgs     map[string]bool // only events in repositories of these owners
+	patterns []string        // only events in repositories matching these globs (owner/*)
+	excludes []string        // no events in repositories matching these globs
+
+	onlyPrivate  bool // only events in private repositories
+	onlyOwned    bool // only events in repositories owned by the user
+	excludeForks bool // drop events in forked repositories
+
+	user  string
+	gh    *github.Client
+	forks map[string]bool // repository name -> is a fork
+}
+
+// isFork returns true if the repository is a fork. Repositories are fetched
+// once and remembered.
+func (f *filters) isFork(ctx context.Context, repo string) bool {
+	if fork, ok := f.forks[repo]; ok {
+		return fork
+	}
+
+	owner, name, _ := strings.Cut(repo, "/")
+	r, _, err := f.gh.Repositories.Get(ctx, owner, name)
+	if err != nil {
+		fmt.Printf("Error fetching repository %s: %v\n", repo, err)
+		f.forks[repo] = false
+		return false
+	}
+
+	f.forks[repo] = r.GetFork()
+	return f.forks[repo]
+}
+
+// addRepo adds a repository (owner/repo), a glob pattern (owner/*) or, with a
+// leading !, an exclusion of either.
+func (f *filters) addRepo(repo string) {
+	switch {
+	case strings.HasPrefix(repo, "!"):
+		f.excludes = append(f.excludes, strings.TrimPrefix(repo, "!"))
+	case isPattern(repo):
+		f.patterns = append(f.patterns, repo)
+	default:
+		f.repos[repo] = true
+	}
+}
+
+// addOrg adds an organization (owner) or, with a leading !, an exclusion of
+// it.
+func (f *filters) addOrg(org string) {
+	if strings.HasPrefix(org, "!") {
+		f.excludes = append(f.excludes, strings.TrimPrefix(org, "!")+"/*")
+		return
+	}
+	f.orgs[org] = true
+}
+
+// stringList is a flag that may be repeated, or given comma separated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, strings.ToLower(v))
+		}
+	}
+	return nil
+}
+
+// labelNames returns the names of the labels, lowercase.
+func labelNames(labels []*github.Label) []string {
+	var names []string
+	for _, l := range labels {
+		names = append(names, strings.ToLower(l.GetName()))
+	}
+	return names
+}
+
+// filterLabels drops the issues and pull requests without any of the wanted
+// labels (if any), or with any of the excluded ones. Labels may be glob
+// patterns (area/*).
+func (w *work) filterLabels(wanted, excluded []string) {
+	if len(wanted) == 0 && len(excluded) == 0 {
+		return
+	}
+
+	drop := make(map[id]bool)
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for id, meta := range place {
+			keep := len(wanted) == 0
+			for _, label := range meta.labels {
+				if matches(excluded, label) {
+					keep = false
+					break
+				}
+				if matches(wanted, label) {
+					keep = true
+				}
+			}
+			if !keep {
+				drop[id] = true
+			}
+		}
+	}
+	w.removeItems(drop)
+}
+
+// isPattern returns true if the repository is a glob pattern (owner/*).
+func isPattern(repo string) bool {
+	return strings.ContainsAny(repo, "*?[")
+}
+
+// matches returns true if the repository matches any of the patterns.
+func matches(patterns []string, repo string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, repo); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allows returns true if the event should be collected.
+func (f *filters) allows(ctx context.Context, e *github.Event) bool {
+	repo := e.GetRepo().GetName()
+	lower := strings.ToLower(repo)
+
+	if matches(f.excludes, lower) {
+		return false
+	}
+	if len(f.repos) > 0 || len(f.orgs) > 0 || len(f.patterns) > 0 {
+		owner, _, _ := strings.Cut(lower, "/")
+		if !f.repos[lower] && !f.orgs[owner] && !matches(f.patterns, lower) {
+			return false
+		}
+	}
+
+	if f.onlyPrivate && e.GetPublic() {
+		return false
+	}
+	if f.onlyOwned {
+		owner, _, _ := strings.Cut(repo, "/")
+		if !strings.EqualFold(owner, f.user) {
+			return false
+		}
+	}
+	if f.excludeForks && f.isFork(ctx, repo) {
+		return false
+	}
+
+	return true
+}
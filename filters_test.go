@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestFiltersAllowsRepos(t *testing.T) {
+	filter := &filters{
+		repos:    map[string]bool{"owner/repo1": true},
+		orgs:     map[string]bool{"org": true},
+		patterns: []string{"aquasecurity/*", "owner/tracee-*"},
+	}
+
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{"owner/repo1", true},
+		{"Owner/Repo1", true},
+		{"owner/repo2", false},
+		{"org/anything", true},
+		{"aquasecurity/trivy", true},
+		{"AquaSecurity/Tracee", true},
+		{"aquasecurityx/trivy", false},
+		{"owner/tracee-rules", true},
+		{"owner/tracee", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			e := &github.Event{Repo: &github.Repository{Name: github.String(tt.repo)}}
+			if got := filter.allows(context.Background(), e); got != tt.want {
+				t.Errorf("allows(%s) = %v, want %v", tt.repo, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFiltersAllowsExcludes(t *testing.T) {
+	filter := &filters{repos: make(map[string]bool), orgs: make(map[string]bool)}
+	filter.addOrg("aquasecurity")
+	filter.addOrg("!legacy")
+	filter.addRepo("owner/repo1")
+	filter.addRepo("!aquasecurity/trivy-*")
+	filter.addRepo("!aquasecurity/tracee")
+
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{"aquasecurity/trivy", true},
+		{"aquasecurity/trivy-operator", false},
+		{"AquaSecurity/Tracee", false},
+		{"aquasecurity/tracee-rules", true},
+		{"owner/repo1", true},
+		{"legacy/anything", false},
+		{"other/repo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			e := &github.Event{Repo: &github.Repository{Name: github.String(tt.repo)}}
+			if got := filter.allows(context.Background(), e); got != tt.want {
+				t.Errorf("allows(%s) = %v, want %v", tt.repo, got, tt.want)
+			}
+		})
+	}
+
+	// only exclusions: everything else is included
+	filter = &filters{repos: make(map[string]bool), orgs: make(map[string]bool)}
+	filter.addRepo("!owner/noisy")
+	for repo, want := range map[string]bool{"owner/noisy": false, "owner/quiet": true} {
+		e := &github.Event{Repo: &github.Repository{Name: github.String(repo)}}
+		if got := filter.allows(context.Background(), e); got != want {
+			t.Errorf("allows(%s) = %v, want %v", repo, got, want)
+		}
+	}
+}
+
+func TestFilterLabels(t *testing.T) {
+	tests := []struct {
+		name             string
+		wanted, excluded []string
+		kept             []int
+	}{
+		{"no filters", nil, nil, []int{1, 2, 3, 4}},
+		{"wanted", []string{"area/ebpf"}, nil, []int{1, 3}},
+		{"wanted pattern", []string{"area/*"}, nil, []int{1, 2, 3}},
+		{"excluded", nil, []string{"dependencies"}, []int{1, 2, 4}},
+		{"both", []string{"area/*"}, []string{"dependencies"}, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &work{issues: make(map[id]*metadata), pulls: make(map[id]*metadata), actions: make(map[id][]*action)}
+			for number, labels := range map[int][]string{
+				1: {"area/ebpf"},
+				2: {"area/docs", "kind/bug"},
+				3: {"area/ebpf", "dependencies"},
+				4: nil,
+			} {
+				w.pulls[newID("owner/repo", number)] = &metadata{labels: labels}
+			}
+
+			w.filterLabels(tt.wanted, tt.excluded)
+
+			var kept []int
+			for _, id := range w.sortedIds(w.pulls) {
+				kept = append(kept, id.number)
+			}
+			if fmt.Sprint(kept) != fmt.Sprint(tt.kept) {
+				t.Errorf("kept %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
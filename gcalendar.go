@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Google Calendar
+
+const googleCalendarAPI = "https://www.googleapis.com/calendar/v3/"
+
+// minGap is the shortest time without activity, in the working hours, that
+// counts as a gap.
+const minGap = time.Hour
+
+// googleCalendarClient reads from the Google Calendar API (v3), with an OAuth
+// access token (calendar.readonly scope).
+type googleCalendarClient struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func newGoogleCalendarClient(token string) *googleCalendarClient {
+	return &googleCalendarClient{
+		url:   googleCalendarAPI,
+		token: token,
+		http:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// get decodes the JSON answer of the API path into out.
+func (c *googleCalendarClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("google calendar: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// meeting is a calendar event I attended (or didn't decline).
+type meeting struct {
+	title string
+	start time.Time
+	end   time.Time
+}
+
+// calendarEvent is an event of the Google Calendar API, the fields used.
+type calendarEvent struct {
+	Summary      string `json:"summary"`
+	Status       string `json:"status"`       // confirmed, tentative or cancelled
+	Transparency string `json:"transparency"` // transparent when it doesn't block time
+	Start        struct {
+		DateTime time.Time `json:"dateTime"` // zero for all day events
+	} `json:"start"`
+	End struct {
+		DateTime time.Time `json:"dateTime"`
+	} `json:"end"`
+	Attendees []struct {
+		Self           bool   `json:"self"`
+		ResponseStatus string `json:"responseStatus"`
+	} `json:"attendees"`
+}
+
+// meetingOf returns the event as a meeting, or nil if it doesn't take my time:
+// cancelled, all day, free (transparent) or declined by me.
+func meetingOf(e *calendarEvent) *meeting {
+	if e.Status == "cancelled" || e.Transparency == "transparent" || e.Start.DateTime.IsZero() || !e.End.DateTime.After(e.Start.DateTime) {
+		return nil
+	}
+	for _, a := range e.Attendees {
+		if a.Self && a.ResponseStatus == "declined" {
+			return nil
+		}
+	}
+	return &meeting{title: e.Summary, start: e.Start.DateTime, end: e.End.DateTime}
+}
+
+// fetchMeetings returns the meetings of the calendar between the begin and
+// end dates, by start time.
+func (c *googleCalendarClient) fetchMeetings(ctx context.Context, calendar string, begin, end time.Time) ([]*meeting, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var meetings []*meeting
+	pageToken := ""
+	for {
+		query := url.Values{
+			"timeMin":      {begin.Format(time.RFC3339)},
+			"timeMax":      {end.Format(time.RFC3339)},
+			"singleEvents": {"true"},
+			"orderBy":      {"startTime"},
+			"maxResults":   {"250"},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var page struct {
+			Items         []*calendarEvent `json:"items"`
+			NextPageToken string           `json:"nextPageToken"`
+		}
+		if err := c.get(ctx, "calendars/"+url.PathEscape(calendar)+"/events?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+		for _, e := range page.Items {
+			if m := meetingOf(e); m != nil {
+				meetings = append(meetings, m)
+			}
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	sort.SliceStable(meetings, func(i, j int) bool { return meetings[i].start.Before(meetings[j].start) })
+	return meetings, nil
+}
+
+// workday is what filled a day: activity, meetings, and the gaps between the
+// activity spent in meetings or idle.
+type workday struct {
+	day      time.Time // midnight, in the report timezone
+	first    time.Time // first activity (zero if none)
+	last     time.Time // last activity
+	meetings time.Duration
+	titles   []string
+	inMeet   time.Duration // of the gaps, in meetings
+	idle     time.Duration // of the gaps, not in meetings
+}
+
+// overlap returns how much of the interval the meetings take (overlapping
+// meetings count once).
+func overlap(meetings []*meeting, from, to time.Time) time.Duration {
+	var total time.Duration
+	covered := from
+	for _, m := range meetings { // by start time
+		start, end := m.start, m.end
+		if start.Before(covered) {
+			start = covered
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+			covered = end
+		}
+	}
+	return total
+}
+
+// workdays returns, for each day of the period with activity or meetings, the
+// meetings and the gaps of the working hours (from workStart to workEnd)
+// without activity, in meetings or idle.
+func (w *work) workdays(meetings []*meeting, begin, end time.Time) []*workday {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var activity []time.Time
+	for _, actions := range w.actions {
+		for _, a := range actions {
+			if !a.at.IsZero() {
+				activity = append(activity, a.at)
+			}
+		}
+	}
+	for _, bp := range w.pushes {
+		for _, c := range bp.commits {
+			activity = append(activity, c.at)
+		}
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Before(activity[j]) })
+
+	var days []*workday
+	for day := startOfDay(begin); day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		d := &workday{day: day}
+
+		var today []*meeting
+		for _, m := range meetings {
+			if m.start.Before(next) && m.end.After(day) {
+				today = append(today, m)
+				d.titles = append(d.titles, m.title)
+			}
+		}
+		d.meetings = overlap(today, day, next)
+
+		points := []time.Time{day.Add(workStart * time.Hour)}
+		for _, at := range activity {
+			if !at.Before(day) && at.Before(next) {
+				if d.first.IsZero() {
+					d.first = at
+				}
+				d.last = at
+				if at.After(points[0]) && at.Before(day.Add(workEnd*time.Hour)) {
+					points = append(points, at)
+				}
+			}
+		}
+		if d.first.IsZero() && len(today) == 0 {
+			continue
+		}
+		points = append(points, day.Add(workEnd*time.Hour))
+
+		for i := 0; i < len(points)-1; i++ {
+			if gap := points[i+1].Sub(points[i]); gap >= minGap {
+				inMeet := overlap(today, points[i], points[i+1])
+				d.inMeet += inMeet
+				d.idle += gap - inMeet
+			}
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// workdaysReport returns, for each day, the activity span, the meetings, and
+// the gaps in meetings or idle, with the totals.
+func workdaysReport(days []*workday) string {
+	if len(days) == 0 {
+		return ""
+	}
+
+	hours := func(d time.Duration) string { return fmt.Sprintf("%.1fh", d.Hours()) }
+	var meetings, inMeet, idle time.Duration
+	report := fmt.Sprintf("\n%s\n\n", msg("Workdays:"))
+	for _, d := range days {
+		meetings += d.meetings
+		inMeet += d.inMeet
+		idle += d.idle
+
+		line := d.day.Format("Mon 2006-01-02") + ": "
+		if d.first.IsZero() {
+			line += "no activity"
+		} else {
+			line += fmt.Sprintf("active %s-%s", d.first.In(location).Format("15:04"), d.last.In(location).Format("15:04"))
+		}
+		if d.meetings > 0 {
+			line += fmt.Sprintf(", meetings %s (%s)", hours(d.meetings), strings.Join(d.titles, ", "))
+		}
+		line += fmt.Sprintf(", gaps %s in meetings, %s idle", hours(d.inMeet), hours(d.idle))
+		report += line + "\n"
+	}
+	report += fmt.Sprintf("Meetings: %s, gaps in meetings: %s, idle gaps: %s\n", hours(meetings), hours(inMeet), hours(idle))
+	return report
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchMeetings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Path != "/calendars/primary/events" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"items": [
+				{"summary": "Standup", "start": {"dateTime": "2024-03-04T09:30:00Z"}, "end": {"dateTime": "2024-03-04T10:00:00Z"}},
+				{"summary": "Holiday", "start": {"date": "2024-03-04"}, "end": {"date": "2024-03-05"}},
+				{"summary": "Declined", "start": {"dateTime": "2024-03-04T11:00:00Z"}, "end": {"dateTime": "2024-03-04T12:00:00Z"},
+				 "attendees": [{"self": true, "responseStatus": "declined"}]}
+			], "nextPageToken": "2"}`))
+			return
+		}
+		w.Write([]byte(`{"items": [
+			{"summary": "Planning", "start": {"dateTime": "2024-03-04T13:00:00Z"}, "end": {"dateTime": "2024-03-04T15:00:00Z"}},
+			{"summary": "Focus", "transparency": "transparent", "start": {"dateTime": "2024-03-04T15:00:00Z"}, "end": {"dateTime": "2024-03-04T17:00:00Z"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	c := newGoogleCalendarClient("token")
+	c.url = server.URL + "/"
+	begin := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	meetings, err := c.fetchMeetings(context.Background(), "primary", begin, begin.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meetings) != 2 || meetings[0].title != "Standup" || meetings[1].title != "Planning" {
+		t.Fatalf("got %d meetings, want Standup and Planning", len(meetings))
+	}
+}
+
+func TestWorkdays(t *testing.T) {
+	location = time.UTC
+	mon := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(days, hour, minute int) time.Time {
+		return mon.AddDate(0, 0, days).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	pull := newID("owner/repo", 1)
+	w := &work{
+		actions: map[id][]*action{pull: {{at: at(0, 9, 12)}, {at: at(0, 12, 0)}, {at: at(0, 17, 30)}}},
+	}
+	meetings := []*meeting{
+		{title: "Standup", start: at(0, 9, 30), end: at(0, 10, 0)},
+		{title: "Review", start: at(0, 9, 45), end: at(0, 10, 30)}, // overlaps the standup
+		{title: "Planning", start: at(0, 13, 0), end: at(0, 15, 0)},
+		{title: "1:1", start: at(2, 10, 0), end: at(2, 11, 0)},
+	}
+
+	days := w.workdays(meetings, mon, mon.AddDate(0, 0, 3))
+	want := `
+Workdays:
+
+Mon 2024-03-04: active 09:12-17:30, meetings 3.0h (Standup, Review, Planning), gaps 3.0h in meetings, 5.3h idle
+Wed 2024-03-06: no activity, meetings 1.0h (1:1), gaps 1.0h in meetings, 8.0h idle
+Meetings: 4.0h, gaps in meetings: 4.0h, idle gaps: 13.3h
+`
+	if got := workdaysReport(days); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := workdaysReport(nil); got != "" {
+		t.Errorf("got %q, want no section", got)
+	}
+}
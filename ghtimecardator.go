@@ -4,23 +4,36 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/google/go-github/v41/github"
-	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
-	"github.com/tmc/langchaingo/schema"
 
 	"golang.org/x/oauth2"
 )
 
 // Events is used to unmarshal the list of events from the GitHub API.
 
-func getEnvOrExit(key string) string {
-	value := os.Getenv(key)
+// getEnvOrDefault returns the environment variable, or the default value (from
+// the configuration) if it is not set.
+func getEnvOrDefault(key, value string) string {
+	if env := os.Getenv(key); env != "" {
+		return env
+	}
+	return value
+}
+
+// getEnvOrExit returns the environment variable, or the default value (from
+// the configuration), exiting if neither is set.
+func getEnvOrExit(key, value string) string {
+	value = getEnvOrDefault(key, value)
 	if value == "" {
 		fmt.Printf("%s environment variable not set.\n", key)
 		os.Exit(1)
@@ -33,39 +46,104 @@ const (
 	ObjectIssueComment = "issue comment"
 	ObjectPR           = "pull request"
 	ObjectPRComment    = "pull request comment"
+	ObjectDiscussion   = "discussion"
+	ObjectCommit       = "commit"
 )
 
-type id int
+// id identifies an issue or pull request. Numbers are only unique within a
+// repository, so the repository is part of it.
+type id struct {
+	repo   string // owner/repo (lowercase)
+	number int
+}
+
+func newID(repo string, number int) id {
+	return id{repo: strings.ToLower(repo), number: number}
+}
+
+func (i id) String() string {
+	return fmt.Sprintf("#%d", i.number)
+}
 
 type metadata struct {
-	eventId     id     // issue or pull request number
-	url         string // issue or pull request URL
-	title       string // issue or pull request title
-	description string // issue or pull request description
-	author      bool   // true if I'm the author
+	eventId     id        // issue or pull request (repository and number)
+	repo        string    // owner/repo (lowercase)
+	url         string    // issue or pull request URL
+	title       string    // issue or pull request title
+	description string    // issue or pull request description
+	author      bool      // true if I'm the author
+	updated     time.Time // when the issue or pull request was last updated
+	labels      []string  // label names (lowercase)
+	head        string    // owner/repo:branch the pull request is from (lowercase)
+
+	additions    int  // lines added by the pull request
+	deletions    int  // lines deleted by the pull request
+	changedFiles int  // files changed by the pull request
+	diffStat     bool // true if the above are known
+
+	milestone string // milestone title, empty if none
 }
 
 type action struct {
-	action  string // create, edit, delete, etc.
-	object  string // issue, pull request, issue comment, pull request comment, etc.
-	content string // the content of the action (summarized)
+	action  string    // create, edit, delete, etc.
+	object  string    // issue, pull request, issue comment, pull request comment, etc.
+	content string    // the content of the action (summarized)
+	updated time.Time // when the content was last updated
+	at      time.Time // when the action happened
 }
 
 type work struct {
-	issues  map[id]*metadata
-	pulls   map[id]*metadata
-	actions map[id][]*action
-	user    string
+	issues   map[id]*metadata
+	pulls    map[id]*metadata
+	actions  map[id][]*action
+	skipped  []*skipped
+	failures []*failure
+	user     string
+
+	wiki            map[string]*wikiPage // keyed by page URL
+	wikiOrder       []string
+	releases        map[string]*release // keyed by release URL
+	releaseOrder    []string
+	milestones      map[string]*milestone // keyed by milestone URL
+	milestoneOrder  []string
+	transitions     []*transition      // issues I opened, closed or reopened
+	truncated       map[id]*truncation // items with too many actions
+	refs            map[string]*gitRef // keyed by owner/repo:type:name
+	refOrder        []string
+	discussions     map[string]*discussion // keyed by discussion URL
+	discussionOrder []string
+	projects        []*project         // project boards I changed
+	ci              map[string]*ciStat // keyed by owner/repo
+	reviewRequests  []*reviewRequest   // oldest first
+	assigned        []*assignedIssue   // issues assigned to me now
+	suggestions     suggestionStat     // review suggestions applied
+	credits         []*credit          // commits of others crediting me, oldest first
+	mentions        []*mention         // oldest first
+	community       []*communityItem
+	crossPosts      []*crossPost
+	dependencies    *dependencyGroup         // dependency update pull requests (grouped)
+	commitments     []*commitment            // follow-ups I promised
+	security        []*securityItem          // alerts triaged and advisories, oldest first
+	areas           map[string]*areaStat     // keyed by area (Go, Docs, CI, ...)
+	responses       map[id][]time.Time       // when others responded, oldest first
+	signing         *signingStat             // my commits by signature verification
+	bots            *botFilter               // issues and pull requests left out
+	pushes          map[string]*branchPushes // keyed by owner/repo@branch
+	days            []*dayActivity           // each day of the period, oldest first
+	scorer          scorer                   // significance of the actions
+	overrides       map[id]*override         // my corrections of the items
+
+	pending []*job // descriptions and comments to summarize
 }
 
-func (w *work) addIssue(issue *github.Issue) {
+func (w *work) addIssue(e *github.Event, issue *github.Issue) {
 	place := w.issues
 
 	if issue.IsPullRequest() { // sometimes issues are pull requests
 		place = w.pulls
 	}
 
-	id := id(issue.GetNumber())
+	id := newID(e.GetRepo().GetName(), issue.GetNumber())
 
 	if _, ok := place[id]; ok {
 		return
@@ -73,17 +151,22 @@ func (w *work) addIssue(issue *github.Issue) {
 
 	metadata := &metadata{
 		eventId:     id,
+		repo:        strings.ToLower(e.GetRepo().GetName()),
 		url:         issue.GetHTMLURL(),
 		title:       issue.GetTitle(),
-		description: descriptionSummary(issue.GetBody()),
+		description: issue.GetBody(),
 		author:      issue.GetUser().GetLogin() == w.user,
+		updated:     issue.GetUpdatedAt(),
+		labels:      labelNames(issue.Labels),
+		milestone:   issue.GetMilestone().GetTitle(),
 	}
 
 	place[id] = metadata
+	w.pending = append(w.pending, descriptionSummary(e, id, metadata.updated, &metadata.description))
 }
 
-func (w *work) addPullRequest(pr *github.PullRequest) {
-	id := id(pr.GetNumber())
+func (w *work) addPullRequest(e *github.Event, pr *github.PullRequest) {
+	id := newID(e.GetRepo().GetName(), pr.GetNumber())
 
 	if _, ok := w.pulls[id]; ok {
 		return
@@ -91,17 +174,57 @@ func (w *work) addPullRequest(pr *github.PullRequest) {
 
 	metadata := &metadata{
 		eventId:     id,
+		repo:        strings.ToLower(e.GetRepo().GetName()),
 		url:         pr.GetHTMLURL(),
 		title:       pr.GetTitle(),
-		description: descriptionSummary(pr.GetBody()),
+		description: pr.GetBody(),
 		author:      pr.GetUser().GetLogin() == w.user,
+		updated:     pr.GetUpdatedAt(),
+		labels:      labelNames(pr.Labels),
+		milestone:   pr.GetMilestone().GetTitle(),
+	}
+	if head := pr.GetHead(); head.GetRef() != "" {
+		metadata.head = strings.ToLower(head.GetRepo().GetFullName() + ":" + head.GetRef())
 	}
+	metadata.setDiffStat(pr)
 
 	w.pulls[id] = metadata
+	w.pending = append(w.pending, descriptionSummary(e, id, metadata.updated, &metadata.description))
 }
 
-func (w *work) addAction(id id, a *action) {
+func (w *work) addAction(e *github.Event, id id, a *action) {
+	a.at = e.GetCreatedAt()
 	w.actions[id] = append(w.actions[id], a)
+	w.pending = append(w.pending, descriptionSummary(e, id, a.updated, &a.content))
+}
+
+// removeItems removes the issues, or pull requests, with their actions and
+// what is pending to summarize for them.
+func (w *work) removeItems(ids map[id]bool) {
+	if len(ids) == 0 {
+		return
+	}
+
+	drop := make(map[*string]bool) // results of the jobs no longer needed
+	for id := range ids {
+		if meta := w.getIssueOrPR(id); meta != nil {
+			drop[&meta.description] = true
+		}
+		for _, a := range w.actions[id] {
+			drop[&a.content] = true
+		}
+		delete(w.issues, id)
+		delete(w.pulls, id)
+		delete(w.actions, id)
+	}
+
+	pending := w.pending[:0]
+	for _, j := range w.pending {
+		if !drop[j.result] {
+			pending = append(pending, j)
+		}
+	}
+	w.pending = pending
 }
 
 func (w *work) getAction(id id) []*action {
@@ -132,184 +255,1651 @@ func (w *work) getIssueOrPR(id id) *metadata {
 	if _, ok := w.pulls[id]; ok {
 		return w.pulls[id]
 	}
-	return nil
-}
-
-func (w *work) actionSummary(id id) string {
-	role := actionSummaryString
-	meta := w.getIssueOrPR(id)
-
-	var instr string
-	instr += fmt.Sprintf("Summary of #%d (%s) %s\n-\n", meta.eventId, meta.url, meta.title)
-	instr += fmt.Sprintf("Author: %t\n-\n", meta.author)
-	instr += fmt.Sprintf("Description: %s\n-\n", meta.description)
-	instr += fmt.Sprintf("Actions: %d\n-\n", len(w.actions[id]))
+	return nil
+}
+
+// isDone returns true if the issue or pull request was closed, or merged, in
+// the period.
+func (w *work) isDone(id id) bool {
+	for _, a := range w.actions[id] {
+		if a.action == "closed" || a.action == "merged" {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedIds returns the ids of the issues or pull requests in order.
+func (w *work) sortedIds(place map[id]*metadata) []id {
+	ids := make([]id, 0, len(place))
+	for id := range place {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].repo != ids[j].repo {
+			return ids[i].repo < ids[j].repo
+		}
+		return ids[i].number < ids[j].number
+	})
+	return ids
+}
+
+func (w *work) actionSummary(id id, result *string) *job {
+	role := actionSummaryString
+	meta := w.getIssueOrPR(id)
+
+	var instr string
+	instr += fmt.Sprintf("Summary of %s (%s) %s\n-\n", meta.eventId, meta.url, meta.title)
+	instr += fmt.Sprintf("Author: %t\n-\n", meta.author)
+	if len(meta.labels) > 0 {
+		instr += fmt.Sprintf("Labels: %s\n-\n", strings.Join(meta.labels, ", "))
+	}
+	if meta.milestone != "" {
+		instr += fmt.Sprintf("Milestone: %s\n-\n", meta.milestone)
+	}
+	if size := meta.diffStatString(); size != "" {
+		instr += fmt.Sprintf("Size: %s\n-\n", size)
+	}
+	instr += fmt.Sprintf("Description: %s\n-\n", meta.description)
+	instr += fmt.Sprintf("Actions: %d\n-\n", len(w.actions[id]))
+	if note := w.truncationNote(id); note != "" {
+		instr += note + " (mention it)\n-\n"
+	}
+
+	for _, action := range w.keptActions(id) {
+		instr += fmt.Sprintf(
+			"Action: %s\nObject: %s\nContent: %s\n-\n",
+			action.action, action.object, action.content,
+		)
+	}
+
+	// the summary changes whenever the item, or any action, changes
+	updated := meta.updated
+	for _, action := range w.actions[id] {
+		if action.updated.After(updated) {
+			updated = action.updated
+		}
+	}
+
+	return &job{
+		repo:    meta.repo,
+		item:    id.String(),
+		role:    role,
+		instr:   instr,
+		length:  maxAnswerTokens,
+		result:  result,
+		updated: updated,
+	}
+}
+
+// Main Program
+
+const openAIModel = "gpt-4"
+
+var (
+	models        *modelChain
+	openAILimiter = &rateLimiter{}
+	summaries     = &summaryCache{}
+	db            *store    // nil when not recording
+	redaction     *redactor // nil when not redacting
+	tokenUsage    = newUsage(openAIModel)
+)
+
+func main() {
+	var err error
+
+	maxCost := flag.Float64("max-cost", 0, "maximum estimated cost, in dollars, for the run (0: no limit)")
+	maxTokens := flag.Int("max-tokens", 0, "maximum number of tokens for the run (0: no limit)")
+	community := flag.Bool("community", false, "include sponsors activity and community files changes")
+	toggl := flag.Bool("toggl", false, "write my work sessions (activity clustered by --session-gap) as Toggl Track time entries")
+	dryRun := flag.Bool("dry-run", false, "with --toggl, print the time entries instead of creating them")
+	sessionsICS := flag.String("sessions-ics", "", "write my work sessions (activity clustered by --session-gap) to this iCalendar (.ics) file")
+	sessionGap := flag.Duration("session-gap", time.Hour, "longest time without activity within a work session")
+	heatmapFlag := flag.Bool("heatmap", false, "show the activity by day of the week and hour, to spot after hours work")
+	heatmapSVG := flag.String("heatmap-svg", "", "also write the activity heatmap, as an SVG image, to this file")
+	effort := flag.Bool("effort", false, "estimate the hours spent on each issue and pull request, and add an estimated effort column to the timecard")
+	timeline := flag.Bool("timeline", false, "give a day-by-day account of what I did, and write the timecard that way")
+	areas := flag.Bool("areas", false, "break the pull requests work down by language, or area (Go, docs, CI, ...)")
+	signing := flag.Bool("signing", false, "report how many of my commits were signed and verified")
+	groupBy := flag.String("group-by", "", "group the report, and the timecard, by: repo, or label:<prefix> (workstreams, e.g. label:epic/)")
+	assigned := flag.String("assigned", "", "include the issues assigned to me now: all (open) or touched (updated in the period)")
+	mentions := flag.Bool("mentions", false, "include the issues and pull requests of others where I was mentioned, and whether I responded")
+	credits := flag.Bool("credits", false, "include the commits of others crediting me (Co-authored-by, Signed-off-by), attributed to their pull requests")
+	suggestions := flag.Bool("suggestions", false, "include the review suggestions applied: mine by the authors, and of others to my pull requests")
+	reviewQueue := flag.Bool("review-queue", false, "chart my pending review queue day by day (implies --review-requests)")
+	reviewQueueHTML := flag.String("review-queue-html", "", "also write the review queue chart, as an HTML page, to this file")
+	reviewRequests := flag.Bool("review-requests", false, "include the reviews requested from me, and whether I reviewed, approved or am still pending")
+	ci := flag.Bool("ci", false, "include the workflow runs I triggered, re-ran or dispatched, and the workflows I changed")
+	projects := flag.Bool("projects", false, "include the items I added to, or moved on, project boards (v2)")
+	discussions := flag.Bool("discussions", false, "include the discussions I created, answered or commented on")
+	waitTime := flag.Bool("wait-time", false, "split the elapsed time of pull requests in waiting on others and on me")
+	concurrency := flag.Int("concurrency", 4, "number of concurrent calls to OpenAI")
+	rpm := flag.Int("rpm", 0, "maximum OpenAI requests per minute (0: no limit)")
+	tpm := flag.Int("tpm", 0, "maximum OpenAI tokens per minute (0: no limit)")
+	configFile := flag.String("config", filepath.Join(configDir(), "config.yaml"), "configuration file")
+	profileName := flag.String("profile", "", "configuration profile to use")
+	openAIOrg := flag.String("openai-org", os.Getenv("OPENAI_ORGANIZATION"), "OpenAI organization ID to bill")
+	openAIProject := flag.String("openai-project", os.Getenv("OPENAI_PROJECT"), "OpenAI project ID to bill")
+	orgArg := flag.String("org", "", "only include activity in repositories of these organizations, comma separated (!org to exclude one)")
+	var excludeRepos, excludeOrgs, wantedLabels, excludedLabels, botAllow, botDeny, bitbucketRepos stringList
+	var auditLogs pathList
+	flag.Var(&bitbucketRepos, "bitbucket", "also include my activity in this Bitbucket Cloud repository (workspace/repo) (repeatable)")
+	flag.Var(&auditLogs, "audit-log", "read my events from this audit log stream export (NDJSON, .gz), instead of the events API (repeatable)")
+	flag.Var(&botAllow, "bot-allow", "accounts (* wildcards) whose issues and pull requests are kept, even if denied (repeatable)")
+	flag.Var(&botDeny, "bot-deny", "bot accounts (* wildcards) whose issues and pull requests are left out (repeatable, default: *[bot])")
+	flag.Var(&wantedLabels, "label", "only include issues and pull requests with this label, or glob pattern (area/*) (repeatable)")
+	flag.Var(&excludedLabels, "exclude-label", "exclude issues and pull requests with this label, or glob pattern (repeatable)")
+	flag.Var(&excludeRepos, "exclude-repo", "exclude activity in this repository, or glob pattern (owner/*) (repeatable)")
+	flag.Var(&excludeOrgs, "exclude-org", "exclude activity in repositories of this organization (repeatable)")
+	onlyPrivate := flag.Bool("only-private", false, "only include activity in private repositories")
+	onlyOwned := flag.Bool("only-owned", false, "only include activity in repositories I own")
+	excludeForks := flag.Bool("exclude-forks", false, "exclude activity in forked repositories")
+	planFile := flag.String("plan", "", "YAML file with the planned work, to compare with the done work")
+	overridesFile := flag.String("overrides", "", "YAML file with my corrections (category, hours, summary) of the issues and pull requests (owner/repo#number)")
+	planMilestone := flag.String("plan-milestone", "", "milestone (owner/repo:title) with the planned work")
+	noCache := flag.Bool("no-cache", false, "don't use (or store) cached summaries and GitHub responses")
+	format := flag.String("format", FormatTimecard, "output format: timecard, ledger (one line per item), tempo or tempo-json (worklogs), or timesheet (hours per day and project)")
+	export := flag.String("export", "", "export the timesheet hours, instead of the report, to: harvest (time entries, by the API), harvest-csv (the time import format) or clockify (time entries per item)")
+	timesheetRounding := flag.Duration("timesheet-rounding", 15*time.Minute, "increment the timesheet hours are rounded to (15m, 30m, 1h, ...)")
+	dbPath := flag.String("db", filepath.Join(configDir(), "db.sqlite"), "database recording events, items and summaries (empty: don't record)")
+	from := flag.String("from", "", "begin of the period (YYYY-MM-DD or RFC3339), instead of the date argument")
+	to := flag.String("to", "", "end of the period (YYYY-MM-DD, inclusive, or RFC3339), with --from")
+	googleCalendar := flag.String("google-calendar", "", "Google Calendar (primary, or an ID) to annotate the activity gaps with meetings")
+	pushWorklogs := flag.Bool("push-worklogs", false, "with the tempo formats, also add the worklogs to their Jira issues, where Tempo shows them")
+	jiraIssue := flag.String("jira-issue", "", "Jira issue (KEY-123) to add the timecard to, as comments")
+	deliver := flag.String("deliver", "", "also deliver the timecard to: obsidian (a note in the obsidian_vault of the profile)")
+	localeArg := flag.String("locale", "", "language of the report headers and labels, and of the timecard: "+locales()+" (default: en)")
+	timezone := flag.String("timezone", "", "timezone (e.g. Europe/Lisbon) for the periods and dates (default: local)")
+	weekStartFlag := flag.String("week-start", "", "first day of the week: monday or sunday (default: monday)")
+	security := flag.Bool("security", false, "include the security alerts I dismissed and the advisories I drafted or published")
+	commitments := flag.Bool("commitments", false, "extract the follow-ups I promised in my comments into a commitments made section")
+	groupDependencies := flag.Bool("group-dependency-prs", false, "report the dependency update pull requests (Dependabot, Renovate, ...) as a single dependency maintenance item")
+	notifyFlag := flag.Bool("notify", false, "show a desktop notification when the report is ready, or the run fails")
+	statsOnly := flag.Bool("stats-only", false, "print the commits, issues, pull requests and reviews per day (no summaries) and exit")
+	estimateOnly := flag.Bool("estimate", false, "print the estimated calls, tokens, cost and time (JSON) and exit")
+	emitEvents := flag.String("emit-events", "", "file to stream the collected events to, as NDJSON (-: stdout)")
+	sinceLastRun := flag.Bool("since-last-run", false, "only report the events since the last --since-last-run run, instead of the date argument")
+	memberRole := flag.String("role", "", "role of the member, to tailor what the timecard emphasizes: ic, lead or manager (overrides the config)")
+	redactModel := flag.String("redact-model", "", "local Ollama model to redact personal data and secrets with, before calling OpenAI")
+	ollamaURL := flag.String("ollama-url", "http://localhost:11434", "Ollama server URL, for --redact-model and ollama: fallbacks")
+	backend := flag.String("backend", "auto", "where events are fetched from: events (events API), graphql (contributions) or auto (graphql for ranges older than 90 days)")
+	variant := flag.String("prompt-variant", "", "prompt variant (from the profile) to summarize with, recorded with the archived timecard")
+	fallback := flag.String("fallback", "", "comma separated models to fall back to, in order, when out of quota or the model is unavailable (ollama:MODEL for a local model)")
+	resume := flag.String("resume", "", "continue an interrupted run")
+	maxActions := flag.Int("max-actions", 40, "actions of an item given as they are, the first and last ones, the middle ones summarized together (0: no limit)")
+	retries := flag.Int("retries", 5, "attempts for each OpenAI call before giving up on it")
+	retryDelay := flag.Duration("retry-delay", time.Second, "delay before the first retry (doubles on each retry)")
+	tokenExpiryDays := flag.Int("token-expiry-warning", 7, "warn when the GitHub token expires within these days (0: once expired)")
+
+	flag.Usage = func() {
+		fmt.Println("Usage: github [flags] [date] [summary type] [owner/repo]")
+		fmt.Println("       github [flags] prefetch [date] [owner/repo]")
+		fmt.Println("       github [flags] --resume <run id>")
+		fmt.Println("       github [flags] fetch [--output file] [date] [owner/repo]")
+		fmt.Println("       github [flags] report --from-file file [summary type]")
+		fmt.Println("       github [flags] regenerate --section name [date] [summary type] [owner/repo]")
+		fmt.Println("       github [flags] stats usage")
+		fmt.Printf("  date: today, yesterday, last-3days, this-week, last-week, this-month, last-month,\n")
+		fmt.Printf("        this-quarter, last-quarter, this-year, last-year\n")
+		fmt.Printf("        (or --from and --to, or --since-last-run, without the date argument)\n")
+		fmt.Printf("  type: executive, technical, detailed, changelog, attention\n")
+		fmt.Printf("  owner/repo: the repositories to report on, comma separated, or glob patterns (owner/*),\n")
+		fmt.Printf("              !owner/repo (or !owner/*) to exclude repositories\n")
+		fmt.Printf("  prefetch: fetch and summarize into the cache, for faster reports later\n")
+		fmt.Printf("  fetch: only fetch the events, into a file (default: events.json)\n")
+		fmt.Printf("  report: only create the report, from the events in a file (offline)\n")
+		fmt.Printf("  regenerate: rewrite only a section of the last timecard of the period (summaries cached)\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+	args := flag.Args()
+	notifyEnabled = *notifyFlag
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
+	// Nothing to run with: set up, and try, a first run over today
+	if firstRun(cfg) {
+		cfg, err = setup(*configFile)
+		if err != nil {
+			fmt.Println("Error setting up:", err)
+			os.Exit(1)
+		}
+		args = []string{"today"}
+	}
+
+	prof, err := cfg.getProfile(*profileName)
+	if err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
+	command := ""
+	if len(args) > 0 {
+		switch args[0] {
+		case "prefetch", "fetch", "report", "regenerate", "stats":
+			command, args = args[0], args[1:]
+		}
+	}
+	prefetch := command == "prefetch"
+
+	// Usage statistics are local, from the database
+	if command == "stats" {
+		if len(args) != 1 || args[0] != "usage" || *dbPath == "" {
+			fmt.Println("Usage: ghtimecardator [--db path] stats usage")
+			os.Exit(1)
+		}
+		st, err := openStore(*dbPath)
+		if err != nil {
+			fmt.Println("Error opening database:", err)
+			os.Exit(1)
+		}
+		defer st.close()
+		runs, err := st.loadRuns()
+		if err != nil {
+			fmt.Println("Error loading runs:", err)
+			os.Exit(1)
+		}
+		fmt.Print(usageReport(runs))
+		return
+	}
+
+	// The fetch, report and regenerate commands have their own flags
+	var outputFile, inputFile, section string
+	switch command {
+	case "fetch":
+		fetchFlags := flag.NewFlagSet("fetch", flag.ExitOnError)
+		fetchFlags.StringVar(&outputFile, "output", "events.json", "file to save the events to")
+		fetchFlags.Parse(args)
+		args = fetchFlags.Args()
+	case "report":
+		reportFlags := flag.NewFlagSet("report", flag.ExitOnError)
+		reportFlags.StringVar(&inputFile, "from-file", "", "file with the events to report on")
+		reportFlags.Parse(args)
+		args = reportFlags.Args()
+		if inputFile == "" {
+			fmt.Println("The report command needs --from-file.")
+			flag.Usage()
+			os.Exit(1)
+		}
+	case "regenerate":
+		regenerateFlags := flag.NewFlagSet("regenerate", flag.ExitOnError)
+		regenerateFlags.StringVar(&section, "section", "", "section of the timecard to regenerate (its heading, or part of it: technical, owner/repo, ...)")
+		regenerateFlags.Parse(args)
+		args = regenerateFlags.Args()
+		if section == "" {
+			fmt.Println("The regenerate command needs --section.")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *dbPath == "" || *noCache {
+			fmt.Println("The regenerate command needs the recorded timecard (--db) and the cached summaries (no --no-cache).")
+			os.Exit(1)
+		}
+	}
+
+	if *googleCalendar == "" {
+		*googleCalendar = prof.GoogleCalendar
+	}
+	var gcal *googleCalendarClient
+	if *googleCalendar != "" && command != "report" {
+		gcal = newGoogleCalendarClient(getEnvOrExit("GOOGLE_CALENDAR_TOKEN", prof.GoogleCalendarToken))
+	}
+
+	var jira *jiraClient
+	if *jiraIssue != "" || *pushWorklogs {
+		jira = newJiraClient(getEnvOrExit("JIRA_URL", ""), getEnvOrExit("JIRA_USER", ""), getEnvOrExit("JIRA_TOKEN", ""))
+	}
+	if !validDeliver(*deliver) {
+		fmt.Println("Invalid delivery:", *deliver)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *deliver == DeliverObsidian && prof.ObsidianVault == "" {
+		fmt.Println("The obsidian delivery needs obsidian_vault in the profile.")
+		os.Exit(1)
+	}
+	if !validExport(*export) {
+		fmt.Println("Invalid export:", *export)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if (*export == ExportHarvest || *export == ExportHarvestCSV) && len(prof.HarvestTasks) == 0 {
+		fmt.Println("The harvest exports need harvest_tasks (repository to Harvest project and task) in the profile")
+		os.Exit(1)
+	}
+	if *export == ExportClockify && prof.ClockifyWorkspace == "" {
+		fmt.Println("The clockify export needs clockify_workspace in the profile")
+		os.Exit(1)
+	}
+	if *dryRun && !*toggl {
+		fmt.Println("--dry-run needs --toggl.")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *toggl && prof.TogglWorkspace == 0 {
+		fmt.Println("The Toggl Track time entries need toggl_workspace in the profile.")
+		os.Exit(1)
+	}
+	var togglTrack *togglClient
+	if *toggl && !*dryRun {
+		togglTrack = newTogglClient(getEnvOrExit("TOGGL_TOKEN", prof.TogglToken))
+	}
+	var harvest *harvestClient
+	if *export == ExportHarvest {
+		harvest = newHarvestClient(getEnvOrExit("HARVEST_ACCOUNT_ID", ""), getEnvOrExit("HARVEST_TOKEN", ""))
+	}
+	var clockify *clockifyClient
+	if *export == ExportClockify {
+		clockify = newClockifyClient(getEnvOrExit("CLOCKIFY_TOKEN", prof.ClockifyToken))
+	}
+	bitbucketRepos = append(bitbucketRepos, prof.BitbucketRepos...)
+	for _, repo := range bitbucketRepos {
+		if strings.Count(repo, "/") != 1 {
+			fmt.Println("Invalid Bitbucket workspace/repo:", repo)
+			os.Exit(1)
+		}
+	}
+	var bitbucket *bitbucketClient
+	if len(bitbucketRepos) > 0 && command != "report" {
+		bitbucket = newBitbucketClient(getEnvOrExit("BITBUCKET_USER", prof.BitbucketUser), getEnvOrExit("BITBUCKET_TOKEN", prof.BitbucketToken))
+	}
+
+	// Offline reports don't need GitHub, fetching doesn't need OpenAI
+	var githubUser, githubToken, openAIToken string
+	if command == "report" {
+		githubToken = getEnvOrDefault("GITHUB_TOKEN", prof.GitHubToken)
+	} else {
+		githubUser = getEnvOrExit("GITHUB_USER", prof.GitHubUser)
+		githubToken = getEnvOrExit("GITHUB_TOKEN", prof.GitHubToken)
+	}
+	if command != "fetch" && !*statsOnly {
+		openAIToken = getEnvOrExit("OPENAI_TOKEN", prof.OpenAIToken)
+	}
+	if *openAIOrg == "" {
+		*openAIOrg = prof.OpenAIOrganization
+	}
+	if *openAIProject == "" {
+		*openAIProject = prof.OpenAIProject
+	}
+	if *timezone == "" {
+		*timezone = prof.Timezone
+	}
+	if *localeArg == "" {
+		*localeArg = prof.Locale
+	}
+	if *fallback == "" {
+		*fallback = strings.Join(prof.Fallback, ",")
+	}
+	if *variant != "" {
+		if err := useVariant(prof.PromptVariants, *variant); err != nil {
+			fmt.Println("Error loading config:", err)
+			os.Exit(1)
+		}
+	}
+	*memberRole = strings.ToLower(*memberRole)
+	if _, ok := roleEmphasis[*memberRole]; *memberRole != "" && !ok {
+		fmt.Println("Invalid role:", *memberRole)
+		flag.Usage()
+		os.Exit(1)
+	}
+	for member, role := range prof.Members {
+		if _, ok := roleEmphasis[strings.ToLower(role)]; !ok {
+			fmt.Printf("Invalid role for %s in config: %s\n", member, role)
+			os.Exit(1)
+		}
+	}
+	if *weekStartFlag == "" {
+		*weekStartFlag = prof.WeekStart
+	}
+	switch strings.ToLower(*weekStartFlag) {
+	case "", "monday":
+		weekStart = time.Monday
+	case "sunday":
+		weekStart = time.Sunday
+	default:
+		fmt.Println("Invalid week start:", *weekStartFlag)
+		flag.Usage()
+		os.Exit(1)
+	}
+	switch *backend {
+	case "auto", "events", "graphql":
+	default:
+		fmt.Println("Invalid backend:", *backend)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if !validLocale(*localeArg) {
+		fmt.Printf("Invalid locale: %s (known: %s)\n", *localeArg, locales())
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *localeArg != "en" {
+		locale = *localeArg
+	}
+	if *timezone != "" {
+		location, err = time.LoadLocation(*timezone)
+		if err != nil {
+			fmt.Println("Invalid timezone:", err)
+			os.Exit(1)
+		}
+	}
+	calendar, err := parseCalendar(prof.Calendar) // days in the report timezone
+	if err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
+	if *tokenExpiryDays < 0 {
+		fmt.Println("Invalid token expiry warning days:", *tokenExpiryDays)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *retries < 1 {
+		fmt.Println("Invalid retries (at least 1 attempt):", *retries)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *maxActions < 0 {
+		fmt.Println("Invalid max actions (0 for no limit):", *maxActions)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *reviewQueue || *reviewQueueHTML != "" {
+		*reviewRequests = true // the queue is made of them
+	}
+
+	if *sessionGap <= 0 {
+		fmt.Println("Invalid session gap:", *sessionGap)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if !validGroupBy(*groupBy) {
+		fmt.Println("Invalid group by:", *groupBy)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *assigned != "" && *assigned != AssignedAll && *assigned != AssignedTouched {
+		fmt.Println("Invalid assigned issues:", *assigned)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	switch *format {
+	case FormatTimecard, FormatLedger, FormatTimesheet:
+	case FormatTempo, FormatTempoJSON:
+	default:
+		fmt.Println("Invalid format:", *format)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *pushWorklogs && *format != FormatTempo && *format != FormatTempoJSON {
+		fmt.Println("--push-worklogs needs --format tempo or tempo-json.")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if (*format == FormatTimesheet || *export != "") && *timesheetRounding <= 0 {
+		fmt.Println("Invalid timesheet rounding:", *timesheetRounding)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	runBudget := &budget{maxCost: *maxCost, maxTokens: *maxTokens}
+	openAILimiter = &rateLimiter{rpm: *rpm, tpm: *tpm}
+	if !*noCache {
+		summaries.dirs = append(summaries.dirs, filepath.Join(configDir(), "cache", "summaries"))
+	}
+
+	// Stream the events out, for external pipelines (opened first, so
+	// nothing else goes to stdout when streaming there)
+	var sink *eventSink
+	if *emitEvents != "" {
+		sink, err = newEventSink(*emitEvents)
+		if err != nil {
+			fmt.Println("Error opening event sink:", err)
+			os.Exit(1)
+		}
+		defer sink.close()
+	}
+
+	if *dbPath != "" {
+		db, err = openStore(*dbPath)
+		if err != nil {
+			fmt.Println("Error opening database (the run won't be recorded):", err)
+		}
+		defer db.close()
+	}
+
+	var dateArg, summaryType, repoArg string
+	var run *runState
+	var replay *eventsFile
+
+	if *resume != "" {
+		run, err = loadRun(*resume)
+		if err != nil {
+			fmt.Println("Error resuming run:", err)
+			os.Exit(1)
+		}
+		dateArg, summaryType = run.Date, run.Type
+	} else if prefetch || command == "fetch" {
+		if prefetch && *noCache {
+			fmt.Println("Prefetching needs the cache, don't use --no-cache.")
+			os.Exit(1)
+		}
+		if *from == "" && !*sinceLastRun {
+			if len(args) < 1 {
+				flag.Usage()
+				os.Exit(1)
+			}
+			dateArg, args = args[0], args[1:]
+		}
+		if len(args) > 0 {
+			repoArg = args[0]
+		}
+	} else if command == "report" {
+		if len(args) < 1 {
+			flag.Usage()
+			os.Exit(1)
+		}
+		replay, err = readEventsFile(inputFile)
+		if err != nil {
+			fmt.Println("Error reading events:", err)
+			os.Exit(1)
+		}
+		dateArg, summaryType = replay.Date, args[0]
+	} else {
+		if *from == "" && !*sinceLastRun {
+			if len(args) < 1 {
+				flag.Usage()
+				os.Exit(1)
+			}
+			dateArg, args = args[0], args[1:]
+		}
+		// the summary type, and repository, may come from the profile
+		summaryType, repoArg = prof.Summary, prof.Repo
+		if len(args) > 0 && !strings.Contains(args[0], "/") {
+			summaryType, args = args[0], args[1:]
+		}
+		if len(args) > 0 {
+			repoArg = args[0]
+		}
+		if summaryType == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	if !prefetch && command != "fetch" && !validSummaryType(summaryType) {
+		fmt.Println("Invalid summary type:", summaryType)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if summaryType == SummaryAttention {
+		// What waits on me is in them
+		*reviewRequests, *mentions, *ci, *waitTime = true, true, true, true
+	}
+
+	// Record the run for the usage statistics, if wanted
+	if prof.UsageStats && db != nil && !prefetch && command != "fetch" {
+		runUsage = &usageRecorder{started: time.Now(), summaryType: summaryType, format: *format}
+	}
+
+	var wantedRepos, wantedOrgs []string
+	if run != nil {
+		wantedRepos, wantedOrgs = run.Repos, run.Orgs
+	}
+	for _, wantedRepo := range strings.Split(strings.ToLower(repoArg), ",") {
+		wantedRepo = strings.TrimSpace(wantedRepo)
+		if wantedRepo == "" {
+			continue
+		}
+		pattern := strings.TrimPrefix(wantedRepo, "!")
+		if _, err := path.Match(pattern, ""); err != nil || !strings.Contains(pattern, "/") {
+			fmt.Println("Invalid owner/repo:", wantedRepo)
+			flag.Usage()
+			os.Exit(1)
+		}
+		wantedRepos = append(wantedRepos, wantedRepo)
+	}
+	for _, wantedOrg := range strings.Split(strings.ToLower(*orgArg), ",") {
+		wantedOrg = strings.TrimSpace(wantedOrg)
+		if wantedOrg == "" {
+			continue
+		}
+		if strings.ContainsAny(strings.TrimPrefix(wantedOrg, "!"), "/!*?[") {
+			fmt.Println("Invalid organization:", wantedOrg)
+			flag.Usage()
+			os.Exit(1)
+		}
+		wantedOrgs = append(wantedOrgs, wantedOrg)
+	}
+	for _, excluded := range append(excludeRepos, prof.ExcludeRepos...) {
+		if _, err := path.Match(excluded, ""); err != nil || !strings.Contains(excluded, "/") {
+			fmt.Println("Invalid excluded owner/repo:", excluded)
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	// Get the begin and end dates (a zero end date means now)
+	var beginDate, endDate time.Time
+	switch {
+	case run != nil:
+		beginDate, endDate = run.Begin, run.End // "today" is the day of the first attempt
+	case replay != nil:
+		beginDate, endDate = replay.Begin, replay.End
+	case *from != "" && *sinceLastRun:
+		err = fmt.Errorf("--from and --since-last-run can't be used together")
+	case *from != "":
+		beginDate, endDate, err = pickRange(*from, *to)
+		dateArg = *from + ".." + *to
+	case *sinceLastRun:
+		beginDate, err = loadLastRun()
+		dateArg = "since-last-run"
+	case *to != "":
+		err = fmt.Errorf("--to needs --from")
+	default:
+		beginDate, endDate, err = pickDate(dateArg)
+	}
+	if err != nil {
+		fmt.Println(err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	// Only a section of the last timecard is regenerated
+	var previous string
+	if command == "regenerate" {
+		previous, err = db.loadTimecard(beginDate, summaryType, *variant)
+		if err != nil {
+			fmt.Println("Error loading the timecard:", err)
+			os.Exit(1)
+		}
+		if previous == "" {
+			fmt.Println("No timecard recorded for the period and summary type to regenerate.")
+			os.Exit(1)
+		}
+		if _, _, err := findSection(previous, section); err != nil {
+			fmt.Println("Error regenerating:", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx := context.Background()
+
+	// Create a local redactor
+	if *redactModel != "" && command != "fetch" && !*statsOnly {
+		redaction = newRedactor(*redactModel, *ollamaURL)
+	}
+
+	// Create an OpenAI client
+	if command != "fetch" && !*statsOnly {
+		newClient := func(model string) (*openai.Chat, error) {
+			return newOpenAIClient(model, openAIToken, *openAIOrg, *openAIProject, &retryTransport{
+				attempts: *retries,
+				delay:    *retryDelay,
+				maxDelay: time.Minute,
+			})
+		}
+		llm, err := newClient(openAIModel)
+		if err != nil {
+			fmt.Println("Error creating OpenAI client:", err)
+			os.Exit(1)
+		}
+		models, err = newModelChain(llm, strings.Split(*fallback, ","), newClient, *ollamaURL)
+		if err != nil {
+			fmt.Println("Error creating fallback client:", err)
+			os.Exit(1)
+		}
+
+		// Better now than after fetching everything
+		checker := &http.Client{Timeout: 10 * time.Second}
+		if err := checkOpenAIKey(ctx, checker, openAIModelsAPI, openAIToken, *openAIOrg, *openAIProject, openAIModel); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning:", err)
+			notify("Warning: " + err.Error())
+		}
+	}
+
+	// Create a GitHub client (with a cache of responses)
+	responses := &etagTransport{base: http.DefaultTransport}
+	if !*noCache {
+		responses.dir = filepath.Join(configDir(), "cache", "github")
+	}
+	cacheCtx := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: responses})
+	tokenSrc := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: githubToken})
+	tokenClient := oauth2.NewClient(cacheCtx, tokenSrc)
+	ghClient := github.NewClient(tokenClient)
+
+	// Get the GitHub username
+	var login string
+	if replay != nil {
+		login = replay.User
+	} else {
+		user, resp, err := ghClient.Users.Get(ctx, "")
+		if err != nil {
+			fmt.Println("Error fetching user:", err)
+			return
+		}
+		login = user.GetLogin()
+
+		// Scheduled runs shouldn't start failing out of the blue
+		if warning := expiryWarning(tokenExpiry(resp.Header), time.Now(), *tokenExpiryDays); warning != "" {
+			fmt.Fprintln(os.Stderr, "Warning:", warning)
+			notify("Warning: " + warning)
+		}
+	}
+
+	// The role of the member the timecard is for
+	if *memberRole == "" {
+		*memberRole = prof.memberRole(login)
+	}
+
+	// Get the planned work
+	var plan []*planItem
+	if *planFile != "" {
+		plan, err = loadPlan(*planFile)
+		if err != nil {
+			fmt.Println("Error loading plan:", err)
+			os.Exit(1)
+		}
+	}
+	if *planMilestone != "" {
+		milestone, err := milestonePlan(ctx, ghClient, *planMilestone)
+		if err != nil {
+			fmt.Println("Error fetching milestone:", err)
+			os.Exit(1)
+		}
+		plan = append(plan, milestone...)
+	}
+
+	// My corrections of the items, over what is summarized and estimated
+	var overrides map[id]*override
+	if *overridesFile != "" {
+		overrides, err = loadOverrides(*overridesFile)
+		if err != nil {
+			fmt.Println("Error loading overrides:", err)
+			os.Exit(1)
+		}
+	}
+
+	// The issues and pull requests of bots are noise
+	bots := &botFilter{allow: append(botAllow, prof.BotAllow...), deny: append(botDeny, prof.BotDeny...)}
+	if len(bots.deny) == 0 {
+		bots.deny = defaultBots
+	}
+
+	// What the actions are worth: the significance policy, or the defaults
+	var significance scorer = defaultScorer{}
+	if len(prof.Significance) > 0 {
+		significance, err = newPolicyScorer(prof.Significance)
+		if err != nil {
+			fmt.Println("Error loading config:", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := checkWeights(prof.Weights); err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
+	// Initialize the work
+	work := &work{
+		issues:      make(map[id]*metadata),
+		pulls:       make(map[id]*metadata),
+		actions:     make(map[id][]*action),
+		user:        login,
+		wiki:        make(map[string]*wikiPage),
+		areas:       make(map[string]*areaStat),
+		responses:   make(map[id][]time.Time),
+		bots:        bots,
+		pushes:      make(map[string]*branchPushes),
+		releases:    make(map[string]*release),
+		milestones:  make(map[string]*milestone),
+		refs:        make(map[string]*gitRef),
+		discussions: make(map[string]*discussion),
+		scorer:      significance,
+		ci:          make(map[string]*ciStat),
+		overrides:   overrides,
+	}
+
+	// Without a repository, and a configuration, ask which ones to include
+	if run == nil && replay == nil && repoArg == "" && *orgArg == "" && !cfg.found && isTerminal() {
+		wantedRepos, wantedOrgs, err = pickRepos(ctx, ghClient, githubUser)
+		if err != nil {
+			fmt.Println("Error picking repositories:", err)
+			os.Exit(1)
+		}
+	}
+
+	filter := &filters{
+		repos:        make(map[string]bool),
+		orgs:         make(map[string]bool),
+		onlyPrivate:  *onlyPrivate,
+		onlyOwned:    *onlyOwned,
+		excludeForks: *excludeForks,
+		user:         login,
+		gh:           ghClient,
+		forks:        make(map[string]bool),
+	}
+	for _, repo := range wantedRepos {
+		filter.addRepo(repo)
+	}
+	for _, org := range wantedOrgs {
+		filter.addOrg(org)
+	}
+	for _, repo := range append(excludeRepos, prof.ExcludeRepos...) {
+		filter.addRepo("!" + strings.ToLower(repo))
+	}
+	for _, org := range append(excludeOrgs, prof.ExcludeOrgs...) {
+		filter.addOrg("!" + strings.ToLower(org))
+	}
+
+	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+
+	// Counting needs no events, nor summaries: the contributions are enough
+	if *statsOnly {
+		var days map[string]*dayStats
+		if replay != nil {
+			days = countStats(replay.Events)
+		} else {
+			days, err = fetchStats(ctx, ghClient, githubUser, beginDate, endDate, filter, s)
+			if err != nil {
+				fmt.Println("Error fetching contributions:", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Print(statsReport(days))
+		return
+	}
+
+	// Keep the state of the run, so it can be resumed if interrupted
+	if run == nil && !prefetch && command != "fetch" {
+		run, err = newRun(dateArg, beginDate, endDate, summaryType, wantedRepos, wantedOrgs)
+		if err != nil {
+			fmt.Println("Error creating run:", err)
+			os.Exit(1)
+		}
+		run.SinceLastRun = *sinceLastRun
+	}
+	if run != nil {
+		activeRun = run
+		summaries.dirs = append(summaries.dirs, run.summariesDir())
+		exitOnInterrupt()
+	}
+
+	// Get all the events for the user
+	var events []*github.Event
+	if run != nil && run.Fetched {
+		events, err = run.loadEvents()
+	} else if replay != nil {
+		events = replay.Events
+		if run != nil {
+			err = run.saveEvents(events)
+		}
+	} else {
+		if len(auditLogs) > 0 {
+			events, err = readAuditLog(ctx, auditLogs, login, beginDate, endDate, filter)
+		} else if useContributions(*backend, beginDate) {
+			events, err = fetchContributions(ctx, ghClient, githubUser, beginDate, endDate, filter, s)
+			if err == nil && *backend != "graphql" {
+				comments, serr := searchComments(ctx, ghClient, githubUser, beginDate, endDate, filter, s)
+				if serr != nil {
+					fmt.Printf("Warning: searching comments failed (%v), comments are missing.\n", serr)
+				}
+				events = mergeEvents(events, comments)
+			}
+		} else {
+			events, err = fetchEvents(ctx, ghClient, githubUser, beginDate, endDate, filter, s)
+		}
+		if err == nil && bitbucket != nil {
+			var more []*github.Event
+			more, err = fetchBitbucket(ctx, bitbucket, login, bitbucketRepos, beginDate, endDate, s)
+			events = mergeEvents(events, more)
+		}
+		if err == nil && run != nil {
+			err = run.saveEvents(events)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Error fetching events: %v\n", err)
+		exit(1)
+	}
+	if err := db.saveEvents(events); err != nil {
+		fmt.Println("Error saving events to database:", err)
+	}
+
+	if command == "fetch" {
+		err := writeEventsFile(outputFile, &eventsFile{
+			User:   login,
+			Date:   dateArg,
+			Begin:  beginDate,
+			End:    endDate,
+			Events: events,
+		})
+		if err != nil {
+			fmt.Println("Error saving events:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved %d events to %s\n", len(events), outputFile)
+		return
+	}
+
+	// Add the events to the work (descriptions are summarized later)
+	for _, event := range events {
+		if err := sink.emit(event); err != nil {
+			fmt.Println("Error emitting event:", err)
+			exit(1)
+		}
+		handleEvent(work, event)
+	}
+
+	// Pull requests merged by merge queues, or auto-merge, aren't in my events
+	// (offline reports stay offline)
+	if replay == nil {
+		s.Prefix = "Checking queued merges "
+		s.Start()
+		collectQueuedMerges(ctx, ghClient, work, filter, beginDate, endDate)
+		s.Stop()
+	}
+
+	// Commits pushed to my pull requests are reported with them, not twice
+	// (offline, only by their branches)
+	var lookup *github.Client
+	if replay == nil {
+		lookup = ghClient
+	}
+	s.Prefix = "Matching commits to pull requests "
+	s.Start()
+	work.correlatePushes(ctx, lookup)
+	s.Stop()
+
+	// Labels are on the issues and pull requests, not on the events
+	work.filterLabels(wantedLabels, excludedLabels)
+
+	// Actions that don't matter (by the significance policy) are left out
+	work.dropInsignificant()
+
+	// The changelog is only what shipped
+	if summaryType == SummaryChangelog {
+		work.keepShipped()
+	}
+
+	// The size of my pull requests isn't in all the events
+	if replay == nil {
+		s.Prefix = "Fetching pull request sizes "
+		s.Start()
+		collectDiffStats(ctx, ghClient, work, *groupDependencies && *format == FormatTimecard)
+		s.Stop()
+	}
+
+	// Days without activity (and days off) don't count for the averages
+	work.collectDays(events, beginDate, endDate, calendar)
+
+	// Meetings tell the gaps in the activity apart from idle time
+	var workdays []*workday
+	if gcal != nil && replay == nil {
+		s.Prefix = "Fetching meetings "
+		s.Start()
+		meetings, err := gcal.fetchMeetings(ctx, *googleCalendar, beginDate, endDate)
+		s.Stop()
+		if err != nil {
+			fmt.Println("Error fetching meetings:", err)
+		} else {
+			workdays = work.workdays(meetings, beginDate, endDate)
+		}
+	}
+
+	if err := db.saveWork(work); err != nil {
+		fmt.Println("Error saving work to database:", err)
+	}
+
+	// Summarize, and report, the same comment posted on many items once, and
+	// the dependency updates as one item (the ledger has a line per item, with
+	// all its activity)
+	if *format == FormatTimecard {
+		work.collapseCrossPosts()
+		if *groupDependencies {
+			work.groupDependencyPulls()
+		}
+	}
+
+	if *community {
+		s.Prefix = "Fetching community activity "
+		s.Start()
+		collectCommunity(ctx, ghClient, work, events, beginDate, endDate)
+		s.Stop()
+	}
+
+	if *areas {
+		s.Prefix = "Fetching changed files "
+		s.Start()
+		collectAreas(ctx, ghClient, work)
+		s.Stop()
+	}
+
+	if *signing && replay == nil {
+		s.Prefix = "Checking commit signatures "
+		s.Start()
+		collectSigning(ctx, ghClient, work, filter, beginDate, endDate)
+		s.Stop()
+	}
+
+	if *discussions && replay == nil {
+		s.Prefix = "Fetching discussions "
+		s.Start()
+		if err := collectDiscussions(ctx, ghClient, work, filter, beginDate, endDate); err != nil {
+			fmt.Println("Error fetching discussions:", err)
+		}
+		s.Stop()
+	}
+
+	if *assigned != "" && replay == nil {
+		s.Prefix = "Fetching assigned issues "
+		s.Start()
+		if err := collectAssigned(ctx, ghClient, work, filter, *assigned, beginDate, endDate); err != nil {
+			fmt.Println("Error fetching assigned issues:", err)
+		}
+		s.Stop()
+	}
+
+	if *mentions && replay == nil {
+		s.Prefix = "Fetching mentions "
+		s.Start()
+		collectMentions(ctx, ghClient, work, filter, beginDate, endDate)
+		s.Stop()
+	}
+
+	if *suggestions && replay == nil {
+		s.Prefix = "Fetching applied suggestions "
+		s.Start()
+		if err := collectSuggestions(ctx, ghClient, work, beginDate, endDate); err != nil {
+			fmt.Println("Error fetching applied suggestions:", err)
+		}
+		s.Stop()
+	}
+
+	if *credits && replay == nil {
+		s.Prefix = "Fetching credited commits "
+		s.Start()
+		if err := collectCredits(ctx, ghClient, work, filter, beginDate, endDate); err != nil {
+			fmt.Println("Error fetching credited commits:", err)
+		}
+		s.Stop()
+	}
+
+	if *reviewRequests && replay == nil {
+		s.Prefix = "Fetching review requests "
+		s.Start()
+		collectReviewRequests(ctx, ghClient, work, filter, beginDate, endDate)
+		s.Stop()
+	}
+
+	if *ci && replay == nil {
+		s.Prefix = "Fetching workflow runs "
+		s.Start()
+		collectCI(ctx, ghClient, work, beginDate, endDate)
+		s.Stop()
+	}
+
+	if *projects && replay == nil {
+		s.Prefix = "Fetching project boards "
+		s.Start()
+		if err := collectProjects(ctx, ghClient, work, beginDate, endDate); err != nil {
+			fmt.Println("Error fetching project boards:", err)
+		}
+		s.Stop()
+	}
+
+	if *security && replay == nil {
+		s.Prefix = "Fetching security activity "
+		s.Start()
+		collectSecurity(ctx, ghClient, work, beginDate, endDate)
+		s.Stop()
+	}
+
+	if *waitTime && replay == nil {
+		s.Prefix = "Fetching responses "
+		s.Start()
+		collectResponses(ctx, ghClient, work)
+		s.Stop()
+	}
+
+	// The priority inbox is only what waits on me
+	if summaryType == SummaryAttention {
+		work.keepAttention()
+	}
+
+	runUsage.counted(len(events), len(work.issues)+len(work.pulls))
+
+	// Check the expected spend, and time, before summarizing anything
+	estimate := estimateRun(openAIModel, len(events), work, *format, *concurrency, openAILimiter)
+	if *estimateOnly {
+		fmt.Println(estimate.JSON())
+		run.remove()
+		return
+	}
+
+	// The exports are only the estimated hours, nothing is summarized
+	if *export != "" {
+		ids := append(work.sortedIds(work.issues), work.sortedIds(work.pulls)...)
+		if *export == ExportClockify {
+			entries := work.clockifyEntries(ids, prof.ClockifyProjects, prof.Effort.withDefaults(), *timesheetRounding)
+			created, err := clockify.create(ctx, prof.ClockifyWorkspace, entries)
+			if err != nil {
+				fmt.Println("Error exporting to Clockify:", err)
+				exit(1)
+			}
+			fmt.Printf("Clockify time entries: %d created (%d already there)\n", created, len(entries)-created)
+		} else {
+			entries, unmapped := work.harvestEntries(ids, prof.HarvestTasks, prof.Effort.withDefaults(), *timesheetRounding)
+			if harvest != nil {
+				created, err := harvest.push(ctx, entries)
+				if err != nil {
+					fmt.Println("Error exporting to Harvest:", err)
+					exit(1)
+				}
+				fmt.Printf("Harvest time entries: %d created (%d already there)\n", created, len(entries)-created)
+			} else {
+				report, err := harvestCSV(entries, prof.HarvestName)
+				if err != nil {
+					fmt.Println("Error writing time entries:", err)
+					exit(1)
+				}
+				fmt.Print(report)
+			}
+			if len(unmapped) > 0 {
+				fmt.Fprintf(os.Stderr, "No Harvest task in harvest_tasks for (left out): %s\n", strings.Join(unmapped, ", "))
+			}
+		}
+		run.finish()
+		runUsage.record("ok")
+		notify("The " + *export + " export is done")
+		return
+	}
+
+	// The timesheet is only the estimated hours, nothing is summarized
+	if *format == FormatTimesheet {
+		ids := append(work.sortedIds(work.issues), work.sortedIds(work.pulls)...)
+		report, err := timesheetReport(work.timesheet(ids, prof.BillingCodes, prof.Effort.withDefaults(), *timesheetRounding))
+		if err != nil {
+			fmt.Println("Error writing timesheet:", err)
+			exit(1)
+		}
+		fmt.Print(report)
+		run.finish()
+		runUsage.record("ok")
+		notify("The timesheet is ready")
+		return
+	}
+	fmt.Fprintln(os.Stderr, estimate) // stdout is for the report (and ledger rows)
+	if runBudget.limited() {
+		fits := runBudget.allows(estimate.tokens, openAIModel)
+		question := "Continue?"
+		if !fits {
+			question = "The estimate is over budget, continue until the cap is reached?"
+		}
+		if isTerminal() && !confirm(question) || !isTerminal() && !fits {
+			fmt.Println("Aborting.")
+			os.Exit(1)
+		}
+	}
+
+	summarizer := &pool{workers: *concurrency, budget: runBudget}
+
+	// The commitments are in the comments as written, not summarized
+	promised := make(map[id]*string)
+	var commitmentJobs []*job
+	if *commitments && *format == FormatTimecard {
+		commitmentJobs = work.commitmentJobs(promised)
+	}
+
+	// Summarize all descriptions and comments
+	s.Prefix = "Summarizing events "
+	s.Start()
+	if skipped := summarizer.run(work.pending); len(skipped) > 0 {
+		seen := make(map[*github.Event]bool)
+		for _, j := range skipped {
+			if !seen[j.event] {
+				seen[j.event] = true
+				work.addSkipped(j.event, SkipOverBudget)
+			}
+		}
+		s.Stop()
+		runBudget.abort(work)
+	}
+	s.Stop()
+	work.addFailures(work.pending)
+
+	// Summarize the middle actions of the items with too many of them
+	if truncated := work.truncateActions(*maxActions); len(truncated) > 0 {
+		s.Prefix = "Summarizing long items "
+		s.Start()
+		if skipped := summarizer.run(truncated); len(skipped) > 0 {
+			s.Stop()
+			runBudget.abort(work)
+		}
+		s.Stop()
+		work.addFailures(truncated)
+	}
+
+	if prefetch {
+		prefetchSummaries(work, summarizer)
+		return
+	}
+
+	// Create a big report (will be used for the timecard)
+
+	s.Prefix = "Creating report "
+	s.Start()
+	issues := work.sortedIds(work.issues)
+	pulls := work.sortedIds(work.pulls)
+	results := make(map[id]*string)
+	var jobs []*job
+	for _, id := range append(issues, pulls...) {
+		results[id] = new(string)
+		jobs = append(jobs, work.actionSummary(id, results[id]))
+	}
+	if skipped := summarizer.run(jobs); len(skipped) > 0 {
+		s.Stop()
+		runBudget.abort(work)
+	}
+	work.addFailures(jobs)
+	work.overrideSummaries(results)
+
+	if len(commitmentJobs) > 0 {
+		if skipped := summarizer.run(commitmentJobs); len(skipped) > 0 {
+			s.Stop()
+			runBudget.abort(work)
+		}
+		work.addFailures(commitmentJobs)
+		work.addCommitments(promised)
+	}
+
+	// Summarize each workstream as a whole, from the summaries of its items
+	var workstreams map[string]*string
+	if prefix := labelPrefix(*groupBy); prefix != "" && *format == FormatTimecard {
+		var workstreamJobs []*job
+		workstreams, workstreamJobs = work.workstreamJobs(issues, pulls, prefix, results)
+		if skipped := summarizer.run(workstreamJobs); len(skipped) > 0 {
+			s.Stop()
+			runBudget.abort(work)
+		}
+		work.addFailures(workstreamJobs)
+	}
+
+	if *format != FormatTimecard {
+		ids := append(issues, pulls...)
+		rows := make(map[id]*string)
+		jobs = nil
+		for _, id := range ids {
+			rows[id] = new(string)
+			jobs = append(jobs, work.ledgerSummary(id, *results[id], rows[id]))
+		}
+		if skipped := summarizer.run(jobs); len(skipped) > 0 {
+			s.Stop()
+			runBudget.abort(work)
+		}
+		work.addFailures(jobs)
+		s.Stop()
+
+		if *format == FormatLedger {
+			fmt.Print(work.ledgerReport(ids, rows))
+		} else {
+			worklogs, unmapped := work.tempoWorklogs(ids, rows, prof.TempoIssues, prof.Effort.withDefaults())
+			report, err := tempoReport(worklogs, *format)
+			if err != nil {
+				fmt.Println("Error writing worklogs:", err)
+				exit(1)
+			}
+			fmt.Print(report)
+			if len(unmapped) > 0 {
+				fmt.Fprintf(os.Stderr, "No Jira issue mentioned or in tempo_issues for (left out): %s\n", strings.Join(unmapped, ", "))
+			}
+			if *pushWorklogs {
+				added, err := jira.logWork(worklogs)
+				if err != nil {
+					fmt.Println("Error adding the worklogs to Jira:", err)
+					exit(1)
+				}
+				fmt.Fprintf(os.Stderr, "Added %d worklogs to Jira\n", added)
+			}
+		}
+		if ledger := work.unprocessedLedger(); ledger != "" {
+			fmt.Fprintln(os.Stderr, ledger)
+		}
+		if fallbacks := models.report(); fallbacks != "" {
+			fmt.Fprintln(os.Stderr, fallbacks)
+		}
+		if summary := tokenUsage.summary(); summary != "" {
+			fmt.Fprintln(os.Stderr, summary)
+		}
+		run.finish()
+		runUsage.record("ok")
+		notify("The " + *format + " report is ready")
+		return
+	}
+
+	// The most significant items come first
+	issues, pulls = work.rankedIds(work.issues), work.rankedIds(work.pulls)
+
+	effortConf := prof.Effort.withDefaults()
+	issueEntry := func(id id) string {
+		issue := work.issues[id]
+		report := fmt.Sprintf("%s %s (%s) %s\n", msg("Issue:"), issue.eventId, issue.url, issue.title)
+		if len(issue.labels) > 0 {
+			report += fmt.Sprintf("%s %s\n", msg("Labels:"), strings.Join(issue.labels, ", "))
+		}
+		if issue.milestone != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Milestone:"), issue.milestone)
+		}
+		if category, _ := work.overridden(id, changelogCategory(issue.title, issue.labels), ""); summaryType == SummaryChangelog && category != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Category:"), category)
+		}
+		if note := work.truncationNote(id); note != "" {
+			report += note + "\n"
+		}
+		if *effort {
+			report += fmt.Sprintf("%s %.2fh\n", msg("Estimated effort:"), work.estimateEffort(id, effortConf))
+		}
+		report += fmt.Sprintf("%s %s\n", msg("Description:"), *results[id])
+		return report
+	}
+	pullEntry := func(id id) string {
+		pull := work.pulls[id]
+		report := fmt.Sprintf("%s %s (%s) %s\n", msg("PR:"), pull.eventId, pull.url, pull.title)
+		if len(pull.labels) > 0 {
+			report += fmt.Sprintf("%s %s\n", msg("Labels:"), strings.Join(pull.labels, ", "))
+		}
+		if pull.milestone != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Milestone:"), pull.milestone)
+		}
+		if size := pull.diffStatString(); size != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Size:"), size)
+		}
+		if note := work.attributionNote(id); note != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Attribution:"), note)
+		}
+		if category, _ := work.overridden(id, changelogCategory(pull.title, pull.labels), ""); summaryType == SummaryChangelog && category != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Category:"), category)
+		}
+		if note := work.truncationNote(id); note != "" {
+			report += note + "\n"
+		}
+		report += fmt.Sprintf("%s %s\n", msg("Description:"), *results[id])
+		report += fmt.Sprintf("%s\n", work.timeReport(id, effortConf))
+		return report
+	}
 
-	for _, action := range w.actions[id] {
-		instr += fmt.Sprintf(
-			"Action: %s\nObject: %s\nContent: %s\n-\n",
-			action.action, action.object, action.content,
-		)
+	report := ""
+	if *groupBy == GroupByRepo {
+		report += work.groupedReport(issues, pulls, issueEntry, pullEntry)
+	} else if prefix := labelPrefix(*groupBy); prefix != "" {
+		report += work.workstreamsReport(issues, pulls, prefix, workstreams, issueEntry, pullEntry)
+	} else {
+		report += fmt.Sprintf("\n%s\n\n", msg("Issues:"))
+		for _, id := range issues {
+			report += issueEntry(id)
+		}
+		report += fmt.Sprintf("\n%s\n\n", msg("Pulls:"))
+		for _, id := range pulls {
+			report += pullEntry(id)
+		}
 	}
+	s.Stop()
 
-	return executeAI(role, instr)
-}
-
-// Main Program
+	if summaryType == SummaryChangelog {
+		report += work.releasesReport()
+		report += work.dependenciesReport()
+	} else if summaryType == SummaryAttention {
+		report += work.attentionReport(endDate)
+	} else {
+		report += work.daysReport()
+		report += workdaysReport(workdays)
+		if *timeline {
+			report += work.timelineReport()
+		}
+		report += work.pushesReport()
+		report += work.releasesReport()
+		report += work.milestonesReport()
+		report += work.transitionsReport()
+		report += work.refsReport()
+		report += work.discussionsReport()
+		report += work.projectsReport()
+		report += work.ciReport()
+		report += work.securityReport()
+		report += work.reviewRequestsReport()
+		report += work.assignedReport()
+		report += work.suggestionsReport()
+		report += work.creditsReport()
+		report += work.mentionsReport()
+		report += work.wikiReport()
+		report += work.crossPostsReport()
+		report += work.dependenciesReport()
+		report += work.communityReport()
+		report += work.areasReport()
+		report += work.signingReport()
+		report += work.planReport(plan)
+		report += work.commitmentsReport()
+	}
+	report += work.weightsReport(prof.Weights)
+	report += work.billingReport(prof.BillingCodes, effortConf)
+	if *effort {
+		report += work.effortReport(effortConf)
+	}
 
-var llm *openai.Chat
+	if runBudget.exceeded(tokenUsage) {
+		runBudget.abort(work)
+	}
 
-func main() {
-	var err error
+	// Create the timecard
+	s.Prefix = "Creating timecard "
+	s.Start()
+	var timecard string
+	if command == "regenerate" {
+		role := timecardRole(summaryType, *memberRole, *groupBy, *timeline, *effort, len(prof.BillingCodes) > 0)
+		timecard, err = regenerateSection(role, report, previous, section)
+	} else {
+		timecard, err = timecardSummary(summaryType, *memberRole, *groupBy, *timeline, *effort, len(prof.BillingCodes) > 0, report)
+	}
+	s.Stop()
+	if err != nil {
+		fmt.Printf("Error creating timecard: %v\n", err)
+		exit(1)
+	}
+	if err := db.saveTimecard(beginDate, summaryType, *variant, timecard); err != nil {
+		fmt.Println("Error saving timecard to database:", err)
+	}
+	fmt.Println(timecard)
+	if *variant != "" {
+		fmt.Printf("(prompt variant: %s)\n\n", *variant)
+	}
 
-	githubUser := getEnvOrExit("GITHUB_USER")
-	githubToken := getEnvOrExit("GITHUB_TOKEN")
-	openAIToken := getEnvOrExit("OPENAI_TOKEN")
+	// Deliver the timecard
+	if jira != nil {
+		if err := jira.comment(*jiraIssue, timecard); err != nil {
+			fmt.Println("Error delivering to Jira:", err)
+		}
+	}
+	if *deliver == DeliverObsidian {
+		if path, err := work.writeObsidianNote(prof.ObsidianVault, timecard, summaryType, beginDate, endDate); err != nil {
+			fmt.Println("Error delivering to Obsidian:", err)
+		} else {
+			fmt.Printf("Obsidian note: %s\n\n", path)
+		}
+	}
 
-	flag.Usage = func() {
-		fmt.Println("Usage: github [date] [summary type] [owner/repo]")
-		fmt.Printf("  date: today, yesterday, last-3days, this-week, last-week, this-month, last-month\n")
-		fmt.Printf("  type: executive, technical, detailed\n")
-		fmt.Printf("  owner/repo: the repository to report on\n")
-		flag.PrintDefaults()
+	// Show where the work went
+	if breakdown := work.areasReport(); breakdown != "" {
+		fmt.Println(breakdown)
 	}
 
-	flag.Parse()
-	args := flag.Args()
+	// Break the work down by client and project
+	if billing := work.billingReport(prof.BillingCodes, effortConf); billing != "" {
+		fmt.Println(billing)
+	}
 
-	if len(args) < 2 {
-		flag.Usage()
-		os.Exit(1)
+	// Show when the work happened
+	if *heatmapFlag || *heatmapSVG != "" {
+		activity := work.collectHeatmap()
+		if text := activity.String(); *heatmapFlag && text != "" {
+			fmt.Println(text)
+		}
+		if *heatmapSVG != "" {
+			if err := activity.writeSVG(*heatmapSVG); err != nil {
+				fmt.Println("Error writing the heatmap:", err)
+			}
+		}
 	}
 
-	summaryType := args[1]
-	if summaryType != "executive" && summaryType != "technical" && summaryType != "detailed" {
-		fmt.Println("Invalid summary type:", summaryType)
-		flag.Usage()
-		os.Exit(1)
+	// Export the work sessions, to overlay them on the calendar
+	if *sessionsICS != "" {
+		if count, err := work.writeSessionsICS(*sessionsICS, *sessionGap); err != nil {
+			fmt.Println("Error writing the work sessions:", err)
+		} else {
+			fmt.Printf("Work sessions: %d, written to %s\n\n", count, *sessionsICS)
+		}
+	}
+	if *toggl {
+		entries := togglEntries(work.sessions(*sessionGap), prof.TogglProjects, prof.TogglWorkspace)
+		if togglTrack == nil {
+			fmt.Printf("Toggl Track time entries (dry run):\n\n%s\n", togglPreview(entries))
+		} else if created, err := togglTrack.create(ctx, entries); err != nil {
+			fmt.Println("Error writing Toggl Track time entries:", err)
+		} else {
+			fmt.Printf("Toggl Track time entries: %d created (%d already there)\n\n", created, len(entries)-created)
+		}
 	}
 
-	wantedRepo := args[2]
-	if wantedRepo != "" {
-		wantedRepo = strings.ToLower(wantedRepo)
-		if !strings.Contains(wantedRepo, "/") {
-			fmt.Println("Invalid owner/repo:", wantedRepo)
-			flag.Usage()
-			os.Exit(1)
+	// Show whether I kept up with the reviews requested
+	if *reviewQueue || *reviewQueueHTML != "" {
+		queue := work.reviewQueue(beginDate, endDate)
+		if text := reviewQueueReport(queue); *reviewQueue && text != "" {
+			fmt.Println(text)
+		}
+		if *reviewQueueHTML != "" {
+			if err := writeReviewQueueHTML(*reviewQueueHTML, queue); err != nil {
+				fmt.Println("Error writing the review queue:", err)
+			}
 		}
 	}
 
-	// Get the begin date
-	beginDate, err := pickDate(args[0])
-	if err != nil {
-		fmt.Println(err)
-		flag.Usage()
-		os.Exit(1)
+	// Show the commit signing adherence
+	if signed := work.signingReport(); signed != "" {
+		fmt.Println(signed)
 	}
 
-	ctx := context.Background()
+	// Compare the planned work with the done work
+	if planned := work.planReport(plan); planned != "" {
+		fmt.Println(planned)
+	}
 
-	// Create an OpenAI client
-	llm, err = openai.NewChat(
-		openai.WithModel("gpt-4"),
-		openai.WithToken(openAIToken),
-	)
-	if err != nil {
-		fmt.Println("Error creating OpenAI client:", err)
-		os.Exit(1)
+	// List the follow-ups promised
+	if promises := work.commitmentsReport(); promises != "" {
+		fmt.Println(promises)
 	}
 
-	// Create a GitHub client
-	tokenSrc := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: githubToken})
-	tokenClient := oauth2.NewClient(ctx, tokenSrc)
-	ghClient := github.NewClient(tokenClient)
-	opt := &github.ListOptions{PerPage: 100}
+	// List what the timecard does not cover
+	if ledger := work.unprocessedLedger(); ledger != "" {
+		fmt.Println(ledger)
+	}
 
-	// Get the GitHub username
-	user, _, err := ghClient.Users.Get(ctx, "")
-	if err != nil {
-		fmt.Println("Error fetching user:", err)
-		return
+	// Note the models the run fell back to
+	if fallbacks := models.report(); fallbacks != "" {
+		fmt.Println(fallbacks)
 	}
 
-	// Initialize the work
-	work := &work{
-		issues:  make(map[id]*metadata),
-		pulls:   make(map[id]*metadata),
-		actions: make(map[id][]*action),
-		user:    user.GetLogin(),
+	// Show what the run cost
+	if summary := tokenUsage.summary(); summary != "" {
+		fmt.Println(summary)
 	}
 
-	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	run.finish()
+	runUsage.record("ok")
+	notify("The timecard is ready")
+}
+
+// fetchEvents returns the events performed by the user, between the begin and
+// end dates, allowed by the filters.
+func fetchEvents(ctx context.Context, gh *github.Client, user string, begin, end time.Time, filter *filters, s *spinner.Spinner) ([]*github.Event, error) {
+	var events []*github.Event
+	var oldest time.Time // of the events fetched
+	opt := &github.ListOptions{PerPage: 100}
+
+	defer s.Stop()
 
-	// Get all the events for the user
-	myEvents := ghClient.Activity.ListEventsPerformedByUser
 	for {
 		s.Prefix = fmt.Sprintf("Fetching events... page %d ", opt.Page)
 		s.Start()
 
-		ghEvents, resp, err := myEvents(ctx, githubUser, false, opt)
+		ghEvents, resp, err := gh.Activity.ListEventsPerformedByUser(ctx, user, false, opt)
+		if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity && !oldest.IsZero() {
+			ghEvents, resp, err = nil, &github.Response{}, nil // paging past the kept events
+		}
 		if err != nil {
-			fmt.Printf("Error fetching events: %v\n", err)
-			os.Exit(1)
+			return nil, err
 		}
 		for _, event := range ghEvents {
-			eventTime := event.GetCreatedAt()
-			if eventTime.Before(beginDate) {
-				break
+			oldest = event.GetCreatedAt()
+			if event.GetCreatedAt().Before(begin) {
+				return events, nil // events are newest first
 			}
-			repoName := event.GetRepo().GetName()
-			if wantedRepo != "" && repoName != wantedRepo {
+			if !end.IsZero() && !event.GetCreatedAt().Before(end) {
 				continue
 			}
-
-			handleEvent(work, event)
+			if !filter.allows(ctx, event) {
+				continue
+			}
+			events = append(events, event)
 		}
 
 		if resp.NextPage == 0 {
-			opt.Page = resp.FirstPage
-			break
+			// GitHub only keeps the last 90 days (and 300 events) of activity,
+			// the older activity is reconstructed with the search API
+			gapEnd := oldest
+			if gapEnd.IsZero() || !end.IsZero() && end.Before(gapEnd) {
+				gapEnd = end
+			}
+			if gapEnd.IsZero() {
+				gapEnd = time.Now()
+			}
+			s.Prefix = "Searching older activity "
+			older, err := searchEvents(ctx, gh, user, begin, gapEnd)
+			if err != nil {
+				fmt.Printf("\nWarning: GitHub has no events before %s, and searching failed (%v), older activity is missing.\n",
+					gapEnd.In(location).Format("2006-01-02"), err)
+				return events, nil
+			}
+			for _, event := range older {
+				if filter.allows(ctx, event) {
+					events = append(events, event)
+				}
+			}
+			return events, nil
 		}
 		opt.Page = resp.NextPage
-
-		s.Stop()
-	}
-
-	// Create a big report (will be used for the timecard)
-
-	s.Prefix = "Creating report "
-	s.Start()
-	report := ""
-	report += fmt.Sprintf("\nIssues:\n\n")
-	for _, issue := range work.issues {
-		id := issue.eventId
-		result := work.actionSummary(id)
-		report += fmt.Sprintf("Issue: #%d (%s) %s\n", issue.eventId, issue.url, issue.title)
-		report += fmt.Sprintf("Description: %s\n", result)
 	}
-	s.Stop()
-
-	s.Prefix = "Creating timecard "
-	s.Start()
-	report += fmt.Sprintf("\nPulls:\n\n")
-	for _, pull := range work.pulls {
-		id := pull.eventId
-		result := work.actionSummary(id)
-		report += fmt.Sprintf("PR: #%d (%s) %s\n", pull.eventId, pull.url, pull.title)
-		report += fmt.Sprintf("Description: %s\n", result)
-	}
-	s.Stop()
-
-	// Create the timecard
-	fmt.Println(timecardSummary(summaryType, report))
 }
 
 // handleEvent is called for each event and adds it to the work.
 func handleEvent(w *work, e *github.Event) {
 	pay, err := e.ParsePayload()
 	if err != nil {
-		fmt.Println("Error parsing payload:", err)
-		os.Exit(1)
+		w.addSkipped(e, SkipParseError)
+		return
+	}
+	if w.bots.isBot(itemAuthor(pay)) {
+		w.addSkipped(e, SkipBot) // merging, or reviewing, a bot pull request
+		return
 	}
 
 	switch v := pay.(type) {
@@ -317,12 +1907,17 @@ func handleEvent(w *work, e *github.Event) {
 	// General Events
 	//
 	case *github.IssuesEvent:
-		w.addIssue(v.GetIssue())
-		w.addAction(id(v.GetIssue().GetNumber()),
+		realAction := v.GetAction()
+		if !v.GetIssue().IsPullRequest() {
+			realAction = w.addTransition(e, v.GetIssue(), realAction)
+		}
+		w.addIssue(e, v.GetIssue())
+		w.addAction(e, newID(e.GetRepo().GetName(), v.GetIssue().GetNumber()),
 			&action{
-				action:  v.GetAction(),
+				action:  realAction,
 				object:  ObjectIssue,
-				content: descriptionSummary(v.GetIssue().GetBody()),
+				content: v.GetIssue().GetBody(),
+				updated: v.GetIssue().GetUpdatedAt(),
 			})
 	case *github.PullRequestEvent:
 		realAction := v.GetAction()
@@ -333,62 +1928,110 @@ func handleEvent(w *work, e *github.Event) {
 				realAction = "closed"
 			}
 		}
-		w.addPullRequest(v.GetPullRequest())
-		w.addAction(id(v.GetPullRequest().GetNumber()),
+		w.addPullRequest(e, v.GetPullRequest())
+		w.addAction(e, newID(e.GetRepo().GetName(), v.GetPullRequest().GetNumber()),
 			&action{
 				action:  realAction,
 				object:  ObjectPR,
-				content: descriptionSummary(v.GetPullRequest().GetBody()),
+				content: v.GetPullRequest().GetBody(),
+				updated: v.GetPullRequest().GetUpdatedAt(),
 			})
 	//
 	// Related to Comments, Reviews, etc.
 	//
 	case *github.IssueCommentEvent:
-		w.addIssue(v.GetIssue())
-		w.addAction(id(v.GetIssue().GetNumber()),
+		w.addIssue(e, v.GetIssue())
+		w.addAction(e, newID(e.GetRepo().GetName(), v.GetIssue().GetNumber()),
 			&action{
 				action:  v.GetAction(),
 				object:  ObjectIssueComment,
-				content: descriptionSummary(v.GetComment().GetBody()),
+				content: v.GetComment().GetBody(),
+				updated: v.GetComment().GetUpdatedAt(),
 			})
 	case *github.PullRequestReviewEvent:
-		w.addPullRequest(v.GetPullRequest())
-		w.addAction(id(v.GetPullRequest().GetNumber()),
+		w.addPullRequest(e, v.GetPullRequest())
+		w.addAction(e, newID(e.GetRepo().GetName(), v.GetPullRequest().GetNumber()),
 			&action{
 				action:  v.GetAction(),
 				object:  ObjectPRComment,
-				content: descriptionSummary(v.GetReview().GetBody()),
+				content: v.GetReview().GetBody(),
+				updated: v.GetReview().GetSubmittedAt(),
 			})
 	case *github.PullRequestReviewCommentEvent:
-		w.addPullRequest(v.GetPullRequest())
-		w.addAction(id(v.GetPullRequest().GetNumber()),
+		w.addPullRequest(e, v.GetPullRequest())
+		w.addAction(e, newID(e.GetRepo().GetName(), v.GetPullRequest().GetNumber()),
 			&action{
 				action:  v.GetAction(),
 				object:  ObjectPRComment,
-				content: descriptionSummary(v.GetComment().GetBody()),
+				content: v.GetComment().GetBody(),
+				updated: v.GetComment().GetUpdatedAt(),
 			})
 	//
-	// TODO
+	// Commits
+	//
+	case *github.PushEvent:
+		w.addPush(e, v)
+	//
+	// Branches and tags
 	//
-	case *github.CommitCommentEvent:
 	case *github.CreateEvent:
+		w.addRef(e, v.GetRefType(), v.GetRef(), "created")
 	case *github.DeleteEvent:
-	case *github.MilestoneEvent:
-	case *github.PackageEvent:
-	case *github.PushEvent:
+		w.addRef(e, v.GetRefType(), v.GetRef(), "deleted")
+	//
+	// Releases
+	//
 	case *github.ReleaseEvent:
-	case *github.RepositoryEvent:
+		w.addRelease(e, v.GetRelease(), v.GetAction())
+	case *github.MilestoneEvent:
+		w.addMilestone(e, v.GetMilestone(), v.GetAction())
+	//
+	// Security
+	//
 	case *github.RepositoryVulnerabilityAlertEvent:
+		w.addSecurityAlert(e, v)
+	//
+	// Documentation
+	//
+	case *github.GollumEvent:
+		for _, page := range v.Pages {
+			w.addWikiPage(e.GetRepo().GetName(), page)
+		}
+	//
+	// TODO
+	//
+	case *github.CommitCommentEvent,
+		*github.PackageEvent,
+		*github.RepositoryEvent:
+		w.addSkipped(e, SkipUnhandled)
 	default:
-		fmt.Println("Unknown event type:", e.GetType())
+		w.addSkipped(e, SkipUnknownType)
 	}
 }
 
 // Summarization
 
+// roleEmphasis tells the timecard what to emphasize for each role.
+var roleEmphasis = map[string]string{
+	"ic":      timecardRoleIC,
+	"lead":    timecardRoleLead,
+	"manager": timecardRoleManager,
+}
+
+// validSummaryType returns true for the known summary types.
+func validSummaryType(summaryType string) bool {
+	return summaryType == "executive" || summaryType == "technical" || summaryType == "detailed" || summaryType == SummaryChangelog || summaryType == SummaryAttention
+}
+
 // timecardSummary returns a summary of the timecard using openai.
-func timecardSummary(summaryType, report string) string {
-	role := timecardSummaryString
+func timecardSummary(summaryType, memberRole, groupBy string, timeline, effort, billing bool, report string) (string, error) {
+	return summarize("timecard", timecardRole(summaryType, memberRole, groupBy, timeline, effort, billing), report, maxTimecardTokens)
+}
+
+// timecardRole returns the prompt of the timecard: the summary type, and what
+// the report has.
+func timecardRole(summaryType, memberRole, groupBy string, timeline, effort, billing bool) string {
+	role := timecardSummaryString + roleEmphasis[memberRole]
 
 	switch summaryType {
 	case "executive":
@@ -397,82 +2040,203 @@ func timecardSummary(summaryType, report string) string {
 		role += timecardSummaryTechnical
 	case "detailed":
 		role += timecardSummaryExecutive + timecardSummaryTechnical
+	case SummaryChangelog:
+		role += timecardSummaryChangelog
+	case SummaryAttention:
+		role += timecardSummaryAttention
+	}
+	if groupBy == GroupByRepo {
+		role += timecardGroupByRepo
+	} else if labelPrefix(groupBy) != "" {
+		role += timecardGroupByLabel
+	}
+	if timeline {
+		role += timecardTimeline
+	}
+	if effort {
+		role += timecardEffort
+	}
+	if billing {
+		role += timecardBilling
+	}
+	if name := localeNames[locale]; name != "" {
+		role += fmt.Sprintf(timecardLocale, name, name)
 	}
 
-	return executeAI(role, report)
+	return role
 }
 
-// descriptionSummary returns a summary of the description using openai.
-func descriptionSummary(text string) string {
-	role := "You are a BOT that rewrites GitHub Issue and PR descriptions."
-	instr := "Rewrite description below in couple of lines:\n\n" + text
-	return executeAI(role, instr)
+// descriptionSummary returns the job that summarizes the description, in
+// place, using openai.
+func descriptionSummary(e *github.Event, id id, updated time.Time, text *string) *job {
+	return &job{
+		repo:    strings.ToLower(e.GetRepo().GetName()),
+		updated: updated,
+		item:    id.String(),
+		role:    descriptionSummaryString,
+		instr:   "Rewrite description below in couple of lines:\n\n" + condenseCode(*text),
+		length:  maxAnswerTokens,
+		result:  text,
+		event:   e,
+	}
 }
 
-// executeAI is a helper function that calls the openai api.
-func executeAI(role, instr string) string {
-	answer, err := llm.Call(
-		context.Background(),
-		[]schema.ChatMessage{
-			schema.SystemChatMessage{Content: role},
-			schema.HumanChatMessage{Content: instr},
-		},
-		llms.WithTemperature(0.2),
-		llms.WithMaxLength(180),
-	)
+const (
+	maxAnswerTokens   = 180  // maximum length of each answer
+	maxTimecardTokens = 1024 // maximum length of the timecard
+)
+
+// executeAI is a helper function that calls the openai api. The tokens used
+// by the call are accounted to the given item.
+func executeAI(item, role, instr string, length int) (string, error) {
+	instr, err := redaction.redact(instr)
 	if err != nil {
-		fmt.Printf("Error calling OpenAI: %v\n", err)
-		os.Exit(1)
+		return "", fmt.Errorf("redacting: %w", err)
 	}
-	if answer == nil {
-		return ""
+
+	for {
+		model := models.model()
+		answer, err := model.generate(item, role, instr, length)
+		if err == nil {
+			return answer, nil
+		}
+		if !unavailable(err) || !models.fallBack(model, item, err) {
+			return "", err
+		}
 	}
-	return answer.GetContent()
 }
 
 // Date Helpers
 
-// pickDate returns the begin date for the report.
-func pickDate(arg string) (time.Time, error) {
-	var beginDate time.Time
+// location is the timezone the periods are computed, and dates shown, in.
+var location = time.Local
+
+// weekStart is the first day of the week.
+var weekStart = time.Monday
+
+// daysSinceWeekStart returns how many days ago the week started.
+func daysSinceWeekStart(t time.Time) int {
+	return (int(t.Weekday()) - int(weekStart) + 7) % 7
+}
+
+// startOfDay returns the midnight, in the report timezone, of the day.
+func startOfDay(t time.Time) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+// pickDate returns the begin and end dates for the report, at day boundaries
+// in the report timezone. The end date is zero (now) unless the period is
+// already over.
+func pickDate(arg string) (time.Time, time.Time, error) {
+	var beginDate, endDate time.Time
+
+	today := startOfDay(time.Now())
 
 	switch arg {
 	case "today":
-		beginDate = time.Now()
+		beginDate = today
 	case "yesterday":
-		beginDate = time.Now().AddDate(0, 0, -1)
+		beginDate = today.AddDate(0, 0, -1)
+		endDate = today
 	case "last-3days":
-		beginDate = time.Now().AddDate(0, 0, -3)
+		beginDate = today.AddDate(0, 0, -3)
 	case "this-week":
-		// Assuming the week starts on Monday
-		today := time.Now()
-		offset := int(today.Weekday()) - int(time.Monday)
-		if offset < 0 {
-			offset += 7 // Handle Sunday
-		}
-		beginDate = today.AddDate(0, 0, -offset)
+		beginDate = today.AddDate(0, 0, -daysSinceWeekStart(today))
 	case "last-week":
-		today := time.Now()
-		offset := int(today.Weekday()) + 6 // Go back to the previous Monday
-		beginDate = today.AddDate(0, 0, -offset)
+		endDate = today.AddDate(0, 0, -daysSinceWeekStart(today))
+		beginDate = endDate.AddDate(0, 0, -7)
 	case "this-month":
-		today := time.Now()
-		beginDate = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		beginDate = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, location)
 	case "last-month":
-		today := time.Now()
-		firstOfThisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		firstOfThisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, location)
 		beginDate = firstOfThisMonth.AddDate(0, -1, 0)
+		endDate = firstOfThisMonth
+	case "this-quarter":
+		beginDate = startOfQuarter(today)
+	case "last-quarter":
+		endDate = startOfQuarter(today)
+		beginDate = endDate.AddDate(0, -3, 0)
+	case "this-year":
+		beginDate = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, location)
+	case "last-year":
+		endDate = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, location)
+		beginDate = endDate.AddDate(-1, 0, 0)
 	default:
-		return time.Now(), fmt.Errorf("invalid argument")
+		return time.Now(), time.Time{}, fmt.Errorf("invalid argument")
+	}
+
+	return beginDate, endDate, nil
+}
+
+// startOfQuarter returns the first day of the quarter of the day.
+func startOfQuarter(t time.Time) time.Time {
+	month := (t.Month()-1)/3*3 + 1
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, location)
+}
+
+// parseDay parses a RFC3339 time or a YYYY-MM-DD day (report timezone). It returns
+// true if a whole day was given.
+func parseDay(arg string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, arg); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", arg, location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date %q (want YYYY-MM-DD or RFC3339)", arg)
+	}
+	return t, true, nil
+}
+
+// pickRange returns the begin and end dates given with --from and --to. A
+// --to day is inclusive: the period ends when the day ends.
+func pickRange(from, to string) (time.Time, time.Time, error) {
+	beginDate, _, err := parseDay(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if beginDate.After(time.Now()) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from %s is in the future", from)
+	}
+
+	var endDate time.Time
+	if to != "" {
+		end, day, err := parseDay(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if day {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !end.After(beginDate) {
+			return time.Time{}, time.Time{}, fmt.Errorf("--to %s is before --from %s", to, from)
+		}
+		endDate = end
 	}
 
-	return beginDate, nil
+	return beginDate, endDate, nil
 }
 
 //
 // Prompt Strings
 //
 
+var redactString string = `
+Rewrite the text below exactly as it is, but replace personal data (names of
+people, emails, phone numbers, addresses) with [REDACTED] and secrets (tokens,
+passwords, keys, internal hostnames) with [SECRET]. Keep GitHub usernames,
+issue and pull request numbers and URLs. Output only the rewritten text.`
+
+var descriptionSummaryString string = "You are a BOT that rewrites GitHub Issue and PR descriptions. " +
+	"Long code blocks are replaced by what they were ([shared a 40-line Go code block]): say so, briefly."
+
+var chunkSummaryString string = `
+You will be given one part of a bigger report of GitHub issues and pull
+requests. Condense it, keeping every issue and pull request number, URL and
+title, and what was done in each of them. Don't add anything that is not in the
+report.
+`
+
 var actionSummaryString string = `
 You will be given a summary of a GitHub Issue or PR and a series of actions made
 by me on it. They will be in the form of:
@@ -504,15 +2268,194 @@ form of:
 
 Issues:
 Issue: number (URL) title
+Milestone: title (if any)
 Description: summary of what I did in the issue
 Issue:
 ...
 
 Pulls:
 PR: number (URL) title
+Milestone: title (if any)
+Size: +additions -deletions lines, number files (of the ones I authored)
+Attribution: commits of others crediting me (co-authored or signed off)
 Description: summary of what I did in the pull request
 PR:
 ...
+
+Releases:
+Release: owner/repo tag (URL) title
+Actions: published, edited
+Notes: summary of the release notes
+Release:
+...
+
+Milestones:
+Milestone: owner/repo title (URL) state, due date
+Actions: created, closed, edited
+Items: numbers of the issues and pull requests in the milestone
+...
+
+Workdays:
+day date: active first-last, meetings hours (titles), gaps hours in meetings, hours idle
+Meetings: hours, gaps in meetings: hours, idle gaps: hours
+
+Timeline:
+day date: verb owner/repo#number (title), ...; verb ...
+...
+
+Issue transitions:
+Issues: opened number, closed number (number as not planned), reopened number
+date owner/repo#number (URL) title: open -> closed (reason)
+...
+
+Branches and tags:
+Repository: owner/repo
+  branch name: created, deleted (PR owner/repo#number title)
+  tag name: created
+...
+
+Discussions:
+Discussion: owner/repo#number (URL) title
+created: summary of the discussion I started
+answered: summary of my answer (marked as the answer)
+commented: summary of my comment
+Discussion:
+...
+
+Projects:
+Project: title (URL)
+  date added: owner/repo#number (URL) title
+  date Status: In progress: owner/repo#number (URL) title
+...
+
+Security:
+date owner/repo: kind (Dependabot alert, code scanning alert, advisory) action title (severity), reason (URL)
+...
+
+Review requests:
+owner/repo#number (URL) title: requested date by login, outcome date (after time)
+Requested: number (by outcome)
+
+Dependency maintenance:
+Pull requests: number (what I did on them)
+Size: +additions -deletions lines, number files
+Repository: owner/repo: number title; number title
+...
+
+Assigned issues:
+Issue: owner/repo#number (URL) title: state, opened date, worked on [labels]
+...
+
+Credited commits:
+date owner/repo sha message (co-authored or signed off, pushed by login) in owner/repo#number
+...
+
+Suggestions applied:
+Mine, applied by the authors: number
+  date owner/repo#number (URL) title: sha commit message
+Of others, applied to my pull requests: number
+  date owner/repo#number (URL) title: sha commit message (suggested by names)
+...
+
+Mentions:
+Issue: owner/repo#number (URL) title: mentioned date by login, responded date (after time)
+Pull request: owner/repo#number (URL) title: mentioned date by login, not responded
+Mentioned: number (responded: number)
+
+CI:
+Repository: owner/repo
+  Runs: number (failed, re-runs, manual)
+  Re-run: workflow #number (URL) branch, conclusion
+  Manual run: workflow #number (URL) branch, conclusion
+  Workflow change: sha commit message
+...
+
+Docs:
+Wiki page: owner/repo (URL) title
+Actions: created, edited
+Wiki page:
+...
+
+Community:
+Sponsors: date (URL) sponsorship activity
+File: owner/repo (URL) community file changed: commit message
+...
+
+Areas:
+area percentage (lines, files and pull requests changed)
+...
+
+Planned vs done:
+status (done, in progress, not started, untracked) owner/repo#number title
+...
+unplanned: items that were not planned
+
+Commitments made:
+owner/repo#number (URL): what I promised (due: when)
+...
+
+Weights:
+owner/repo: share of the narrative (side project: one sentence)
+...
+
+If there is a planned vs done section, add a short comparison of the planned
+work with the done work, including the unplanned work.
+
+If there is a commitments made section, list the commitments at the end, as
+action items, with when they are due.
+
+If there is a weights section, give each repository a share of the timecard in
+proportion to its share of the narrative: the primary ones in detail, each
+side project in a single sentence, and leave out the ones to leave out.
+
+The size of a pull request tells a one-line fix from a large feature: give the
+larger ones more weight.
+Releases are deliverables: mention them prominently, with what they ship.
+Milestones group the work: closing one is a deliverable, and the items of a
+milestone can be described together, as progress towards it.
+Branches belong with the pull requests opened from them, if any.
+Discussions are community support and design work. Project boards changes are
+planning and triage work. CI re-runs, failed runs and workflow changes are time
+spent fixing CI. Security items are vulnerability triage and advisories: keep
+them in their own section. Review requests are the review load: mention the
+pending ones. Assigned issues are ongoing responsibilities: the ones not worked
+on are carry-over work. Suggestions applied are the concrete impact of the
+reviews: give their number. Mentions are being pulled into the discussions of
+others: mention the ones not responded. Dependency maintenance is a single
+item: one line, with the number of pull requests, whatever the number. Wiki
+pages are documentation work. Community items are community management work
+(sponsors, funding, code of conduct, contributing guidelines, etc).
+`
+
+var commitmentsString string = `
+You extract the commitments from my comments on an issue, or pull request:
+the explicit follow-ups, or TODOs, I promised to do ("I'll send a patch next
+week", "will fix the docs after the release"). Not the things already done,
+asked of others, or only suggested. Answer only with a JSON array of objects
+with "commitment" (what I promised, in a short sentence) and "due" (when, as I
+put it, or empty): [{"commitment": "...", "due": "..."}], or [] if there are
+none.
+`
+
+var ledgerSummaryString string = `
+You will be given the summary of what I did in a GitHub Issue or PR. Answer with
+a single line in the form "category: summary", where category is one of: %s.
+The summary must be at most 10 words long.
+`
+
+var timecardRoleIC string = `
+I'm an individual contributor: emphasize the code I wrote (features, fixes,
+tests) over reviews and discussions.
+`
+
+var timecardRoleLead string = `
+I'm a tech lead: emphasize the reviews, coordination and unblocking of others,
+as much as the code I wrote.
+`
+
+var timecardRoleManager string = `
+I'm a manager: emphasize the coordination, planning, reviews and community
+work, and summarize the code work at a higher level.
 `
 
 var timecardSummaryExecutive string = `
@@ -527,3 +2470,78 @@ language. Differentiate between features, fixes, docs, tests, management, ...
 Split the technical summary into sections, if needed. Use emojis to
 differentiate between sections.
 `
+var truncatedSummaryString string = `
+You will be given a series of actions made by me, in the middle of many more,
+on a GitHub Issue or PR: comments, reviews, edits, etc. Summarize them together
+in a few sentences: what was discussed, decided or changed. Don't add anything
+that is not in the actions.
+`
+
+var workstreamSummaryString string = `
+You will be given the summaries of the GitHub issues and pull requests of a
+workstream (an epic, or project, shared by all of them) that I worked on.
+Summarize the workstream as one piece of work, in a few sentences: what
+progressed, what was delivered and what is still going on. Don't add anything
+that is not in the summaries.
+`
+
+var timecardGroupByRepo string = `
+The issues and pull requests are grouped by repository, each with its totals
+(Repository: owner/repo, Totals: issues, pull requests, comments). Structure
+the summary the same way: a section per repository, with its totals, most
+active repositories first.
+`
+var timecardBilling string = `
+The report ends with the work rolled up by billing code (Billing: client:project:
+issues, pull requests, estimated hours). Break the summary down by billing code,
+a section per client and project, the most hours first.
+`
+var timecardEffort string = `
+Each issue and pull request has an estimated effort (Estimated effort: hours),
+and the report ends with the total. Include an estimated effort column: a table
+of the issues and pull requests (number, title, estimated hours), with the
+total, after the summary. Say the hours are estimates.
+`
+var timecardTimeline string = `
+The report has a timeline, what I did each day (Timeline: day: verb items;
+verb items). Write the summary as a day-by-day account, a line per day with
+activity (Mon: reviewed #123, opened #456, ...), using the rest of the report
+to tell what each item was about, and end with a short overall summary.
+`
+var timecardLocale string = `
+The section headers and labels of the report are in %s, translated from the
+form above. Write the summary in %s.
+`
+var timecardGroupByLabel string = `
+The issues and pull requests are grouped in workstreams, the items sharing a
+label (Workstream: label, Items: issues and pull requests, Summary: the
+workstream as a whole). Structure the summary the same way: a section per
+workstream, telling its progress as one piece of work, and the items in no
+workstream last.
+`
+var timecardSummaryChangelog string = `
+Provide a changelog of the shipped work in the report below: the merged pull
+requests and closed issues (and releases), like release notes. Group it by
+repository and, in each repository, by conventional commit type (Features,
+Fixes, Performance, Refactoring, Docs, Tests, Build, CI, Chores), using the
+category of the item when there is one. One bullet per item: what changed, in
+a sentence, and the link. No introduction, conclusion or empty groups.
+`
+
+var timecardSummaryAttention string = `
+Provide a triage list of the items waiting on me in the report below: the
+reviews requested from me, the mentions and responses I haven't answered and
+my pull requests whose CI failed, the longest waiting first. One bullet per
+item: what is needed from me, in a sentence, since when, and the link. No
+introduction, conclusion or items that aren't waiting on me.
+`
+
+var sectionSummaryString string = `
+This timecard was already written from the report below:
+
+%[2]s
+
+Rewrite only its section starting with "%[1]s", better and from the report,
+keeping its heading. Answer only with the section, heading included: the rest
+of the timecard stays as it is.
+`
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// GitHub GraphQL API
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQL runs a query against the GitHub GraphQL API (using the same client,
+// and credentials, as the REST API) and unmarshals the data into out.
+func graphQL(ctx context.Context, gh *github.Client, query string, vars map[string]interface{}, out interface{}) error {
+	req, err := gh.NewRequest("POST", "graphql", &graphQLRequest{Query: query, Variables: vars})
+	if err != nil {
+		return err
+	}
+
+	resp := &graphQLResponse{}
+	if _, err := gh.Do(ctx, req, resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		var msgs []string
+		for _, e := range resp.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return fmt.Errorf("graphql: %s", strings.Join(msgs, "; "))
+	}
+
+	return json.Unmarshal(resp.Data, out)
+}
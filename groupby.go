@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Grouping by Repository, or Label
+
+const (
+	GroupByRepo  = "repo"
+	GroupByLabel = "label:" // followed by the label prefix (epic/, project/, ...)
+)
+
+// validGroupBy returns true for repo and label:<prefix>.
+func validGroupBy(groupBy string) bool {
+	return groupBy == "" || groupBy == GroupByRepo ||
+		strings.HasPrefix(groupBy, GroupByLabel) && len(groupBy) > len(GroupByLabel)
+}
+
+// labelPrefix returns the label prefix items are grouped by, or an empty
+// string if they are not grouped by label.
+func labelPrefix(groupBy string) string {
+	if !strings.HasPrefix(groupBy, GroupByLabel) {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(groupBy, GroupByLabel))
+}
+
+// repoTotals counts the issues, pull requests and comments of a repository.
+type repoTotals struct {
+	issues   int
+	pulls    int
+	comments int
+}
+
+// groupIds splits the issues and pull requests (keeping their order) by the
+// given key, returning the keys with more items first. Items with an empty
+// key are left out.
+func groupIds(issues, pulls []id, key func(id) string) ([]string, map[string][2][]id) {
+	groups := make(map[string][2][]id) // issues and pull requests of each key
+	for i, ids := range [][]id{issues, pulls} {
+		for _, id := range ids {
+			k := key(id)
+			if k == "" {
+				continue
+			}
+			group := groups[k]
+			group[i] = append(group[i], id)
+			groups[k] = group
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := groups[keys[i]], groups[keys[j]]
+		if len(a[0])+len(a[1]) != len(b[0])+len(b[1]) {
+			return len(a[0])+len(a[1]) > len(b[0])+len(b[1])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys, groups
+}
+
+// itemsReport returns the Issues and Pulls sections of the given items, each
+// section only if there are items for it.
+func itemsReport(group [2][]id, issueEntry, pullEntry func(id) string) string {
+	report := ""
+	if len(group[0]) > 0 {
+		report += fmt.Sprintf("\n%s\n\n", msg("Issues:"))
+		for _, id := range group[0] {
+			report += issueEntry(id)
+		}
+	}
+	if len(group[1]) > 0 {
+		report += fmt.Sprintf("\n%s\n\n", msg("Pulls:"))
+		for _, id := range group[1] {
+			report += pullEntry(id)
+		}
+	}
+	return report
+}
+
+// groupedReport returns the issues and pull requests (in the given order)
+// under each repository, with the totals of each repository. The
+// repositories with more items come first.
+func (w *work) groupedReport(issues, pulls []id, issueEntry, pullEntry func(id) string) string {
+	repos, groups := groupIds(issues, pulls, func(id id) string { return id.repo })
+
+	report := ""
+	for _, repo := range repos {
+		t := &repoTotals{issues: len(groups[repo][0]), pulls: len(groups[repo][1])}
+		for _, ids := range groups[repo] {
+			for _, id := range ids {
+				for _, a := range w.actions[id] {
+					if a.object == ObjectIssueComment || a.object == ObjectPRComment {
+						t.comments++
+					}
+				}
+			}
+		}
+		report += fmt.Sprintf("\n%s %s\n", msg("Repository:"), repo)
+		report += fmt.Sprintf("%s %d issues, %d pull requests, %d comments\n", msg("Totals:"), t.issues, t.pulls, t.comments)
+		report += itemsReport(groups[repo], issueEntry, pullEntry)
+	}
+	return report
+}
+
+// Workstreams
+
+// workstream returns the first label (sorted) of the item with the prefix,
+// or an empty string if none has it.
+func (w *work) workstream(id id, prefix string) string {
+	meta := w.getIssueOrPR(id)
+	if meta == nil {
+		return ""
+	}
+	labels := append([]string{}, meta.labels...)
+	sort.Strings(labels)
+	for _, label := range labels {
+		if strings.HasPrefix(label, prefix) {
+			return label
+		}
+	}
+	return ""
+}
+
+// workstreamJobs returns the jobs summarizing, together, the items of each
+// workstream (items sharing a label with the prefix), from the summaries of
+// the items. The summaries go to the returned map, keyed by label.
+func (w *work) workstreamJobs(issues, pulls []id, prefix string, results map[id]*string) (map[string]*string, []*job) {
+	labels, groups := groupIds(issues, pulls, func(id id) string { return w.workstream(id, prefix) })
+
+	summaries := make(map[string]*string)
+	var jobs []*job
+	for _, label := range labels {
+		instr := fmt.Sprintf("Workstream: %s\n-\n", label)
+		var updated = w.getIssueOrPR(append(groups[label][0], groups[label][1]...)[0]).updated
+		for _, ids := range groups[label] {
+			for _, id := range ids {
+				meta := w.getIssueOrPR(id)
+				instr += fmt.Sprintf("%s %s (%s) %s\nSummary: %s\n-\n", meta.repo, id, meta.url, meta.title, *results[id])
+				if meta.updated.After(updated) {
+					updated = meta.updated
+				}
+			}
+		}
+
+		summaries[label] = new(string)
+		jobs = append(jobs, &job{
+			repo:    prefix,
+			item:    label,
+			role:    workstreamSummaryString,
+			instr:   instr,
+			length:  maxAnswerTokens,
+			result:  summaries[label],
+			updated: updated,
+		})
+	}
+	return summaries, jobs
+}
+
+// workstreamsReport returns the issues and pull requests (in the given order)
+// under each workstream, with its summary, and then the ones in none.
+func (w *work) workstreamsReport(issues, pulls []id, prefix string, summaries map[string]*string, issueEntry, pullEntry func(id) string) string {
+	labels, groups := groupIds(issues, pulls, func(id id) string { return w.workstream(id, prefix) })
+	_, others := groupIds(issues, pulls, func(id id) string {
+		if w.workstream(id, prefix) == "" {
+			return "none"
+		}
+		return ""
+	})
+
+	report := ""
+	for _, label := range labels {
+		report += fmt.Sprintf("\n%s %s\n", msg("Workstream:"), label)
+		report += fmt.Sprintf("%s %d issues, %d pull requests\n", msg("Items:"), len(groups[label][0]), len(groups[label][1]))
+		if summary := summaries[label]; summary != nil && *summary != "" {
+			report += fmt.Sprintf("%s %s\n", msg("Summary:"), *summary)
+		}
+		report += itemsReport(groups[label], issueEntry, pullEntry)
+	}
+	if len(others) > 0 {
+		report += fmt.Sprintf("\n%s none\n", msg("Workstream:"))
+		report += itemsReport(others["none"], issueEntry, pullEntry)
+	}
+	return report
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupedReport(t *testing.T) {
+	a1, a2, b1 := newID("owner/a", 1), newID("owner/a", 2), newID("owner/b", 1)
+	w := &work{
+		actions: map[id][]*action{
+			a1: {{object: ObjectIssue}, {object: ObjectIssueComment}},
+			a2: {{object: ObjectPRComment}, {object: ObjectPRComment}},
+			b1: {{object: ObjectPR}},
+		},
+	}
+	entry := func(id id) string { return id.repo + id.String() + "\n" }
+
+	want := `
+Repository: owner/a
+Totals: 1 issues, 1 pull requests, 3 comments
+
+Issues:
+
+owner/a#1
+
+Pulls:
+
+owner/a#2
+
+Repository: owner/b
+Totals: 0 issues, 1 pull requests, 0 comments
+
+Pulls:
+
+owner/b#1
+`
+	if got := w.groupedReport([]id{a1}, []id{b1, a2}, entry, entry); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestValidGroupBy(t *testing.T) {
+	for groupBy, want := range map[string]bool{
+		"":            true,
+		"repo":        true,
+		"label:epic/": true,
+		"label:":      false,
+		"author":      false,
+	} {
+		if got := validGroupBy(groupBy); got != want {
+			t.Errorf("validGroupBy(%q) = %t, want %t", groupBy, got, want)
+		}
+	}
+	if got := labelPrefix("label:Epic/"); got != "epic/" {
+		t.Errorf("got prefix %q, want epic/", got)
+	}
+}
+
+func TestWorkstreams(t *testing.T) {
+	i1, p2, p3 := newID("owner/a", 1), newID("owner/a", 2), newID("owner/b", 3)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "owner/a", url: "u1", title: "Plan", labels: []string{"epic/sso", "bug"}}},
+		pulls: map[id]*metadata{
+			p2: {repo: "owner/a", url: "u2", title: "Login", labels: []string{"epic/sso"}},
+			p3: {repo: "owner/b", url: "u3", title: "Typo"},
+		},
+	}
+	results := map[id]*string{i1: new(string), p2: new(string), p3: new(string)}
+	*results[p2] = "added the login page"
+
+	summaries, jobs := w.workstreamJobs([]id{i1}, []id{p2, p3}, "epic/", results)
+	if len(jobs) != 1 || jobs[0].item != "epic/sso" {
+		t.Fatalf("got %d jobs, want one for epic/sso", len(jobs))
+	}
+	if want := "owner/a #2 (u2) Login\nSummary: added the login page\n"; !strings.Contains(jobs[0].instr, want) {
+		t.Errorf("prompt %q does not contain %q", jobs[0].instr, want)
+	}
+	*summaries["epic/sso"] = "SSO is half done"
+
+	entry := func(id id) string { return id.repo + id.String() + "\n" }
+	want := `
+Workstream: epic/sso
+Items: 1 issues, 1 pull requests
+Summary: SSO is half done
+
+Issues:
+
+owner/a#1
+
+Pulls:
+
+owner/a#2
+
+Workstream: none
+
+Pulls:
+
+owner/b#3
+`
+	if got := w.workstreamsReport([]id{i1}, []id{p2, p3}, "epic/", summaries, entry, entry); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Harvest Export
+
+const (
+	ExportHarvest    = "harvest"     // time entries pushed to the Harvest API
+	ExportHarvestCSV = "harvest-csv" // the Harvest time import CSV
+)
+
+const harvestAPI = "https://api.harvestapp.com/v2/"
+
+// harvestGroup is the external reference group of the time entries pushed,
+// telling them from the ones entered by hand.
+const harvestGroup = "ghtimecardator"
+
+// harvestTask is the Harvest project and task the work in a repository is
+// logged to: by name, for the CSV import, and by ID, for the API.
+type harvestTask struct {
+	Client    string `yaml:"client,omitempty"`
+	Project   string `yaml:"project,omitempty"`
+	Task      string `yaml:"task,omitempty"`
+	ProjectID int64  `yaml:"project_id,omitempty"`
+	TaskID    int64  `yaml:"task_id,omitempty"`
+}
+
+// harvestEntry is the time spent on a Harvest task on a day.
+type harvestEntry struct {
+	task  *harvestTask
+	day   string // YYYY-MM-DD
+	hours float64
+	notes string // the items
+	ref   string // external reference, not to push it twice
+}
+
+// validExport returns true for the known exports (or none).
+func validExport(export string) bool {
+	return export == "" || export == ExportHarvest || export == ExportHarvestCSV || export == ExportClockify
+}
+
+// harvestEntries returns the timesheet (estimated hours per day, rounded to
+// the increment) per Harvest task: the one of the most specific pattern
+// (owner/repo, owner/*, *) matching the repository. The repositories without
+// a task are returned apart.
+func (w *work) harvestEntries(ids []id, tasks map[string]*harvestTask, weights *effortWeights, increment time.Duration) ([]*harvestEntry, []string) {
+	patterns := make(map[string]string) // the project of the timesheet is the pattern
+	for pattern := range tasks {
+		patterns[pattern] = pattern
+	}
+
+	var mapped []id
+	unmapped := make(map[string]bool)
+	for _, id := range ids {
+		if repo := w.getIssueOrPR(id).repo; billingCode(patterns, repo) == unbilled {
+			unmapped[repo] = true
+		} else {
+			mapped = append(mapped, id)
+		}
+	}
+
+	var entries []*harvestEntry
+	for _, e := range w.timesheet(mapped, patterns, weights, increment) {
+		entries = append(entries, &harvestEntry{
+			task:  tasks[e.project],
+			day:   e.day,
+			hours: e.hours,
+			notes: strings.Join(e.items, ", "),
+			ref:   e.day + "/" + e.project,
+		})
+	}
+
+	repos := make([]string, 0, len(unmapped))
+	for repo := range unmapped {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	return entries, repos
+}
+
+// harvestCSV returns the entries in the Harvest time import format, for the
+// person (first and last name).
+func harvestCSV(entries []*harvestEntry, name string) (string, error) {
+	first, last, _ := strings.Cut(strings.TrimSpace(name), " ")
+
+	var buf bytes.Buffer
+	out := csv.NewWriter(&buf)
+	out.Write([]string{"Date", "Client", "Project", "Task", "Notes", "Hours", "First name", "Last name"})
+	for _, e := range entries {
+		out.Write([]string{e.day, e.task.Client, e.task.Project, e.task.Task, e.notes, fmt.Sprintf("%.2f", e.hours), first, last})
+	}
+	out.Flush()
+	return buf.String(), out.Error()
+}
+
+// harvestClient pushes time entries to the Harvest API (v2), with a personal
+// access token.
+type harvestClient struct {
+	url     string
+	account string
+	token   string
+	http    *http.Client
+}
+
+func newHarvestClient(account, token string) *harvestClient {
+	return &harvestClient{
+		url:     harvestAPI,
+		account: account,
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends the body (if any), as JSON, to the API path and decodes the JSON
+// answer into out (if any).
+func (c *harvestClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		data = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Harvest-Account-Id", c.account)
+	req.Header.Set("User-Agent", "ghtimecardator")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("harvest: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// pushed returns the external references of the time entries already pushed
+// between the days.
+func (c *harvestClient) pushed(ctx context.Context, from, to string) (map[string]bool, error) {
+	refs := make(map[string]bool)
+	query := url.Values{}
+	query.Set("from", from)
+	query.Set("to", to)
+	query.Set("per_page", "100")
+	for page := 1; page > 0; {
+		var answer struct {
+			TimeEntries []struct {
+				ExternalReference *struct {
+					ID      string `json:"id"`
+					GroupID string `json:"group_id"`
+				} `json:"external_reference"`
+			} `json:"time_entries"`
+			NextPage int `json:"next_page"`
+		}
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("time_entries?%s&page=%d", query.Encode(), page), nil, &answer); err != nil {
+			return nil, err
+		}
+		for _, e := range answer.TimeEntries {
+			if ref := e.ExternalReference; ref != nil && ref.GroupID == harvestGroup {
+				refs[ref.ID] = true
+			}
+		}
+		page = answer.NextPage
+	}
+	return refs, nil
+}
+
+// push creates a time entry per entry, but the ones already pushed (by their
+// external reference), and returns how many it created.
+func (c *harvestClient) push(ctx context.Context, entries []*harvestEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	refs, err := c.pushed(ctx, entries[0].day, entries[len(entries)-1].day)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, e := range entries {
+		if refs[e.ref] {
+			continue
+		}
+		if e.task.ProjectID == 0 || e.task.TaskID == 0 {
+			return created, fmt.Errorf("harvest: no project_id and task_id for %s", e.ref)
+		}
+		entry := map[string]interface{}{
+			"project_id": e.task.ProjectID,
+			"task_id":    e.task.TaskID,
+			"spent_date": e.day,
+			"hours":      e.hours,
+			"notes":      e.notes,
+			"external_reference": map[string]string{
+				"id":       e.ref,
+				"group_id": harvestGroup,
+			},
+		}
+		if err := c.do(ctx, http.MethodPost, "time_entries", entry, nil); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
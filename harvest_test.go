@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHarvest(t *testing.T) {
+	location = time.UTC
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	i1, p2 := newID("influxdata/influxdb", 1), newID("other/repo", 2)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "influxdata/influxdb"}},
+		pulls:  map[id]*metadata{p2: {repo: "other/repo"}},
+		actions: map[id][]*action{
+			i1: {{at: day, object: ObjectIssueComment}, {at: day.AddDate(0, 0, 1), object: ObjectIssueComment}},
+			p2: {{at: day, object: ObjectPRComment}},
+		},
+		responses: make(map[id][]time.Time),
+	}
+	tasks := map[string]*harvestTask{
+		"influxdata/*": {Client: "ClientA", Project: "ProjX", Task: "Development", ProjectID: 10, TaskID: 20},
+	}
+
+	entries, unmapped := w.harvestEntries([]id{i1, p2}, tasks, defaultEffort.withDefaults(), 30*time.Minute)
+	if len(unmapped) != 1 || unmapped[0] != "other/repo" {
+		t.Errorf("unmapped %v, want other/repo", unmapped)
+	}
+
+	want := `Date,Client,Project,Task,Notes,Hours,First name,Last name
+2024-03-04,ClientA,ProjX,Development,influxdata/influxdb#1,1.00,Rafael,David Tinoco
+2024-03-05,ClientA,ProjX,Development,influxdata/influxdb#1,1.00,Rafael,David Tinoco
+`
+	if got, err := harvestCSV(entries, "Rafael David Tinoco"); err != nil || got != want {
+		t.Errorf("got %q (%v), want %q", got, err, want)
+	}
+
+	var created []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Harvest-Account-Id") != "42" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("headers %v", r.Header)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("from") != "2024-03-04" || r.URL.Query().Get("to") != "2024-03-05" {
+				t.Errorf("listed %s", r.URL)
+			}
+			rw.Write([]byte(`{"time_entries": [
+				{"external_reference": {"id": "2024-03-04/influxdata/*", "group_id": "ghtimecardator"}},
+				{"external_reference": null}
+			], "next_page": null}`))
+		case http.MethodPost:
+			var entry map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&entry)
+			created = append(created, entry)
+			rw.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	c := newHarvestClient("42", "token")
+	c.url = server.URL + "/"
+	n, err := c.push(context.Background(), entries)
+	if err != nil || n != 1 {
+		t.Fatalf("created %d (%v), want 1", n, err)
+	}
+	if e := created[0]; e["spent_date"] != "2024-03-05" || e["project_id"] != 10.0 || e["task_id"] != 20.0 || e["hours"] != 1.0 {
+		t.Errorf("created %v", e)
+	}
+}
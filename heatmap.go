@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Activity Heatmap
+
+// heatmap counts the actions by day of the week and hour, in the report
+// timezone.
+type heatmap struct {
+	counts [7][24]int // by weekday (Sunday is 0) and hour
+	max    int
+	total  int
+}
+
+// Working hours, for the after hours count.
+const (
+	workStart = 9  // first working hour
+	workEnd   = 18 // first hour after work
+)
+
+// collectHeatmap returns the heatmap of the actions on the issues and pull
+// requests, and of the commits pushed.
+func (w *work) collectHeatmap() *heatmap {
+	h := &heatmap{}
+	add := func(at time.Time) {
+		if at.IsZero() {
+			return
+		}
+		at = at.In(location)
+		h.counts[at.Weekday()][at.Hour()]++
+		h.total++
+		if c := h.counts[at.Weekday()][at.Hour()]; c > h.max {
+			h.max = c
+		}
+	}
+	for _, actions := range w.actions {
+		for _, a := range actions {
+			add(a.at)
+		}
+	}
+	for _, bp := range w.pushes {
+		for _, c := range bp.commits {
+			add(c.at)
+		}
+	}
+	return h
+}
+
+// afterHours returns how many actions happened on weekends, or outside the
+// working hours.
+func (h *heatmap) afterHours() int {
+	count := 0
+	for day := range h.counts {
+		for hour, c := range h.counts[day] {
+			weekend := time.Weekday(day) == time.Saturday || time.Weekday(day) == time.Sunday
+			if weekend || hour < workStart || hour >= workEnd {
+				count += c
+			}
+		}
+	}
+	return count
+}
+
+// weekdays returns the days of the week, starting on the first day of the
+// week.
+func weekdays() []time.Weekday {
+	days := make([]time.Weekday, 7)
+	for i := range days {
+		days[i] = (weekStart + time.Weekday(i)) % 7
+	}
+	return days
+}
+
+// heatmapShades are the blocks from no activity to the busiest hour.
+var heatmapShades = []string{"·", "░", "▒", "▓", "█"}
+
+// String returns the heatmap as terminal blocks, a row per day and a column
+// per hour, and the after hours count.
+func (h *heatmap) String() string {
+	if h.total == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Activity by day and hour:"))
+	header := "     "
+	for hour := 0; hour < 24; hour += 3 {
+		header += fmt.Sprintf("%-3d", hour)
+	}
+	report += strings.TrimRight(header, " ") + "\n"
+	for _, day := range weekdays() {
+		row := ""
+		for _, c := range h.counts[day] {
+			steps := len(heatmapShades) - 1
+			row += heatmapShades[(c*steps+h.max-1)/h.max] // rounded up
+		}
+		report += fmt.Sprintf("%s  %s\n", day.String()[:3], row)
+	}
+
+	after := h.afterHours()
+	report += fmt.Sprintf("After hours (weekends, before %d:00 or after %d:00): %d of %d actions (%.0f%%)\n",
+		workStart, workEnd, after, h.total, 100*float64(after)/float64(h.total))
+	return report
+}
+
+// svg returns the heatmap as an SVG image, the busiest hour darkest.
+func (h *heatmap) svg() string {
+	const cell, left, top = 20, 40, 20
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="10">`+"\n",
+		left+24*cell+10, top+7*cell+10)
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%d</text>`+"\n", left+hour*cell, top-6, hour)
+	}
+	for row, day := range weekdays() {
+		y := top + row*cell
+		fmt.Fprintf(&b, `<text x="4" y="%d">%s</text>`+"\n", y+14, day.String()[:3])
+		for hour, c := range h.counts[day] {
+			opacity := 0.0
+			if h.max > 0 {
+				opacity = float64(c) / float64(h.max)
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="#ebedf0"/>`+"\n", left+hour*cell, y, cell-2, cell-2)
+			if c > 0 {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="#216e39" fill-opacity="%.2f"><title>%s %02d:00: %d</title></rect>`+"\n",
+					left+hour*cell, y, cell-2, cell-2, opacity, day, hour, c)
+			}
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// writeSVG writes the heatmap, as an SVG image, to the file.
+func (h *heatmap) writeSVG(path string) error {
+	return os.WriteFile(path, []byte(h.svg()), 0o644)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeatmap(t *testing.T) {
+	location = time.UTC
+	weekStart = time.Monday
+	mon := time.Date(2024, 3, 4, 10, 30, 0, 0, time.UTC) // a Monday
+	pull := newID("owner/repo", 1)
+	w := &work{
+		actions: map[id][]*action{pull: {
+			{at: mon},
+			{at: mon.Add(5 * time.Minute)},
+			{at: mon.Add(12 * time.Hour)}, // Monday 22:30
+			{at: mon.AddDate(0, 0, 5)},    // Saturday 10:30
+			{object: ObjectPR},            // no time
+		}},
+		pushes: map[string]*branchPushes{"owner/repo@main": {commits: []*pushCommit{{at: mon.Add(time.Hour)}}}},
+	}
+
+	h := w.collectHeatmap()
+	if h.total != 5 || h.max != 2 || h.counts[time.Monday][10] != 2 {
+		t.Fatalf("got %d actions, busiest hour %d", h.total, h.max)
+	}
+	if got := h.afterHours(); got != 2 {
+		t.Errorf("got %d after hours, want 2", got)
+	}
+
+	text := h.String()
+	lines := strings.Split(text, "\n")
+	if len(lines) < 11 || lines[4] != "Mon  ··········█▒··········▒·" || !strings.HasPrefix(lines[9], "Sat  ··········▒") {
+		t.Errorf("unexpected heatmap:\n%s", text)
+	}
+	if !strings.Contains(text, "2 of 5 actions (40%)") {
+		t.Errorf("no after hours count:\n%s", text)
+	}
+	if got := (&work{}).collectHeatmap().String(); got != "" {
+		t.Errorf("got %q, want no heatmap", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "heatmap.svg")
+	if err := h.writeSVG(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "<svg") || !strings.Contains(string(data), "<title>Monday 10:00: 2</title>") {
+		t.Errorf("unexpected SVG: %s", data)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Jira Delivery
+
+// jiraCommentLimit is the maximum length of a Jira comment.
+const jiraCommentLimit = 32767
+
+// jiraClient posts to the Jira REST API (v2, which takes wiki markup).
+type jiraClient struct {
+	url   string // https://example.atlassian.net
+	user  string
+	token string
+	http  *http.Client
+}
+
+func newJiraClient(url, user, token string) *jiraClient {
+	return &jiraClient{
+		url:   strings.TrimSuffix(url, "/"),
+		user:  user,
+		token: token,
+		http:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// post sends the body, as JSON, to the API path.
+func (c *jiraClient) post(path string, body interface{}) error {
+	return c.do(http.MethodPost, path, body, nil)
+}
+
+// do sends the body (if any), as JSON, to the API path and decodes the
+// response into out (if any).
+func (c *jiraClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.url+"/rest/api/2/"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.user, c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("jira: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// comment adds the markdown text to the issue, converted to wiki markup and
+// split in as many comments as needed.
+func (c *jiraClient) comment(issue, markdown string) error {
+	parts := splitField(markdownToJira(markdown), jiraCommentLimit)
+	for i, part := range parts {
+		if len(parts) > 1 {
+			part = fmt.Sprintf("(%d/%d)\n%s", i+1, len(parts), part)
+		}
+		if err := c.post("issue/"+issue+"/comment", map[string]string{"body": part}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdBullet  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdNumber  = regexp.MustCompile(`^(\s*)\d+[.)]\s+(.*)$`)
+	mdBold    = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalic  = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*?)\*([^*\w]|$)`)
+	mdCode    = regexp.MustCompile("`([^`]+)`")
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// markdownToJira converts the markdown the LLM writes (headings, lists,
+// emphasis, code and links) to Jira wiki markup.
+func markdownToJira(markdown string) string {
+	var out []string
+	inCode := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			lang := strings.TrimPrefix(strings.TrimSpace(line), "```")
+			switch {
+			case inCode:
+				out = append(out, "{code}")
+			case lang != "":
+				out = append(out, "{code:"+lang+"}")
+			default:
+				out = append(out, "{code}")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			line = fmt.Sprintf("h%d. %s", len(m[1]), m[2])
+		} else if m := mdBullet.FindStringSubmatch(line); m != nil {
+			line = strings.Repeat("*", len(m[1])/2+1) + " " + m[2]
+		} else if m := mdNumber.FindStringSubmatch(line); m != nil {
+			line = strings.Repeat("#", len(m[1])/2+1) + " " + m[2]
+		}
+
+		// inline code first, so it isn't touched by the rest
+		var codes []string
+		line = mdCode.ReplaceAllStringFunc(line, func(s string) string {
+			codes = append(codes, "{{"+mdCode.FindStringSubmatch(s)[1]+"}}")
+			return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+		})
+		line = mdItalic.ReplaceAllString(line, "${1}_${2}_${3}")
+		line = mdBold.ReplaceAllString(line, "*${1}${2}*")
+		line = mdLink.ReplaceAllString(line, "[${1}|${2}]")
+		for i, code := range codes {
+			line = strings.Replace(line, fmt.Sprintf("\x00%d\x00", i), code, 1)
+		}
+
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// splitField splits the text in parts of at most limit characters (leaving
+// room for a part header), at line boundaries when possible.
+func splitField(text string, limit int) []string {
+	limit -= 16 // "(nn/nn)\n"
+	if len(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	current := ""
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for len(line) > limit { // a single line longer than the limit
+			if current != "" {
+				parts = append(parts, current)
+				current = ""
+			}
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			parts = append(parts, line[:cut])
+			line = line[cut:]
+		}
+		if len(current)+len(line) > limit {
+			parts = append(parts, current)
+			current = ""
+		}
+		current += line
+	}
+	if current != "" {
+		parts = append(parts, current)
+	}
+
+	return parts
+}
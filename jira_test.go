@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMarkdownToJira(t *testing.T) {
+	tests := []struct {
+		name, markdown, want string
+	}{
+		{"heading", "# Title", "h1. Title"},
+		{"subheading", "### Section", "h3. Section"},
+		{"bullet", "- item", "* item"},
+		{"nested bullets", "- item\n  - sub\n    - subsub", "* item\n** sub\n*** subsub"},
+		{"numbered", "1. first\n   2. second", "# first\n## second"},
+		{"bold", "a **bold** word", "a *bold* word"},
+		{"bold underscores", "a __bold__ word", "a *bold* word"},
+		{"italic", "an *italic* word", "an _italic_ word"},
+		{"bold and italic", "**b** and *i*", "*b* and _i_"},
+		{"inline code", "run `go *test*`", "run {{go *test*}}"},
+		{"link", "see [docs](https://example.com)", "see [docs|https://example.com]"},
+		{"bullet with bold", "- **done**: it", "* *done*: it"},
+		{"code fence", "```\n**x**\n```", "{code}\n**x**\n{code}"},
+		{"code fence with language", "```go\n# not a heading\n```", "{code:go}\n# not a heading\n{code}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToJira(tt.markdown); got != tt.want {
+				t.Errorf("markdownToJira(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitField(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		parts int
+	}{
+		{"fits", "short text", 100, 1},
+		{"lines", strings.Repeat("0123456789\n", 10), 16 + 22, 5},
+		{"long line", strings.Repeat("a", 50), 16 + 20, 3},
+		{"multi-byte", strings.Repeat("çã€", 20), 16 + 10, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := splitField(tt.text, tt.limit)
+			if len(parts) != tt.parts {
+				t.Errorf("got %d parts, want %d", len(parts), tt.parts)
+			}
+			if joined := strings.Join(parts, ""); joined != tt.text {
+				t.Errorf("parts join to %q, want %q", joined, tt.text)
+			}
+			for i, part := range parts {
+				if len(part) > tt.limit-16 {
+					t.Errorf("part %d has %d bytes, over the limit", i, len(part))
+				}
+				if !utf8.ValidString(part) {
+					t.Errorf("part %d is not valid UTF-8: %q", i, part)
+				}
+			}
+		})
+	}
+}
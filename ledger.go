@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Unprocessed Items Ledger
+
+const (
+	SkipParseError  = "payload parse error"
+	SkipUnknownType = "unknown event type"
+	SkipUnhandled   = "event type not handled"
+	SkipBot         = "bot activity"
+)
+
+type failure struct {
+	item string // item (owner/repo#123, etc) the call was made for
+	err  error  // the error of the last attempt
+}
+
+type skipped struct {
+	eventType string    // PushEvent, ForkEvent, etc.
+	repo      string    // owner/repo the event belongs to
+	createdAt time.Time // when the event happened
+	reason    string    // why the event was left out of the timecard
+}
+
+// addSkipped records an event that won't be covered by the timecard.
+func (w *work) addSkipped(e *github.Event, reason string) {
+	w.skipped = append(w.skipped, &skipped{
+		eventType: e.GetType(),
+		repo:      e.GetRepo().GetName(),
+		createdAt: e.GetCreatedAt(),
+		reason:    reason,
+	})
+}
+
+// addFailures records the jobs that failed after all retries.
+func (w *work) addFailures(jobs []*job) {
+	for _, j := range jobs {
+		if j.err != nil {
+			w.failures = append(w.failures, &failure{item: j.repo + j.item, err: j.err})
+		}
+	}
+}
+
+// unprocessedLedger returns the list of skipped events, counted by reason,
+// and of the summaries that failed, so it is clear what the timecard does not
+// cover.
+func (w *work) unprocessedLedger() string {
+	var ledger string
+
+	if len(w.skipped) > 0 {
+		counts := make(map[string]int)
+		for _, s := range w.skipped {
+			counts[s.reason]++
+		}
+		reasons := make([]string, 0, len(counts))
+		for reason := range counts {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+
+		ledger += fmt.Sprintf("Unprocessed: %d events not covered by this timecard\n\n", len(w.skipped))
+		for _, reason := range reasons {
+			ledger += fmt.Sprintf("  %s: %d\n", reason, counts[reason])
+		}
+		ledger += "\n"
+		for _, s := range w.skipped {
+			ledger += fmt.Sprintf("  %s  %-30s %-40s (%s)\n",
+				s.createdAt.In(location).Format("2006-01-02 15:04"), s.eventType, s.repo, s.reason,
+			)
+		}
+	}
+
+	if len(w.failures) > 0 {
+		if ledger != "" {
+			ledger += "\n"
+		}
+		ledger += fmt.Sprintf("Failed: %d summaries failed after all retries\n\n", len(w.failures))
+		for _, f := range w.failures {
+			ledger += fmt.Sprintf("  %-12s %v\n", f.item, f.err)
+		}
+	}
+
+	return ledger
+}
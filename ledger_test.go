@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddFailures(t *testing.T) {
+	w := &work{}
+	w.addFailures([]*job{
+		{repo: "owner/repo", item: "#7", err: errors.New("quota")},
+		{repo: "other/repo", item: "#7", err: errors.New("timeout")},
+		{repo: "owner/repo", item: "#8"},
+	})
+
+	ledger := w.unprocessedLedger()
+	for _, want := range []string{"owner/repo#7 quota", "other/repo#7 timeout"} {
+		if !strings.Contains(ledger, want) {
+			t.Errorf("no %q in:\n%s", want, ledger)
+		}
+	}
+	if strings.Contains(ledger, "#8") {
+		t.Errorf("unexpected #8 in:\n%s", ledger)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Ledger Format
+
+const (
+	FormatTimecard = "timecard"
+	FormatLedger   = "ledger"
+)
+
+// ledgerCategories are the categories an item can be put in.
+var ledgerCategories = []string{"feature", "fix", "docs", "tests", "chore", "review", "discussion"}
+
+const maxLedgerTokens = 40 // category and a 10 words summary
+
+// period returns when the item was first and last touched, in the period.
+func (w *work) period(id id) (first, last time.Time) {
+	for _, a := range w.actions[id] {
+		if first.IsZero() || a.updated.Before(first) {
+			first = a.updated
+		}
+		if a.updated.After(last) {
+			last = a.updated
+		}
+	}
+	if first.IsZero() {
+		first = w.getIssueOrPR(id).updated
+		last = first
+	}
+	return first, last
+}
+
+// estimateHours is a rough estimate of the time spent on the item: the
+// significance of its actions, in hours (or the hours of its override).
+func (w *work) estimateHours(id id) float64 {
+	if hours, ok := w.overriddenHours(id); ok {
+		return hours
+	}
+	hours := 0.0
+	for _, a := range w.actions[id] {
+		hours += w.significance(id, a)
+	}
+	return hours
+}
+
+// ledgerSummary returns the job that condenses the summary of the item into a
+// category and a 10 words summary.
+func (w *work) ledgerSummary(id id, summary string, result *string) *job {
+	meta := w.getIssueOrPR(id)
+	_, updated := w.period(id)
+
+	instr := fmt.Sprintf("%s%s %s\n\n%s", meta.repo, id, meta.title, summary)
+	if size := meta.diffStatString(); size != "" {
+		instr = fmt.Sprintf("%s%s %s (%s)\n\n%s", meta.repo, id, meta.title, size, summary)
+	}
+
+	return &job{
+		repo:    meta.repo,
+		item:    id.String(),
+		role:    fmt.Sprintf(ledgerSummaryString, strings.Join(ledgerCategories, ", ")),
+		instr:   instr,
+		length:  maxLedgerTokens,
+		result:  result,
+		updated: updated,
+	}
+}
+
+// parseLedgerSummary splits the "category: summary" answer. Unknown categories
+// become "other".
+func parseLedgerSummary(answer string) (string, string) {
+	answer = strings.TrimSpace(strings.SplitN(answer, "\n", 2)[0])
+	category, summary, ok := strings.Cut(answer, ":")
+	if !ok {
+		return "other", answer
+	}
+	category = strings.ToLower(strings.TrimSpace(category))
+	for _, c := range ledgerCategories {
+		if c == category {
+			return category, strings.TrimSpace(summary)
+		}
+	}
+	return "other", strings.TrimSpace(summary)
+}
+
+// ledgerReport returns one tab separated line per item (dates, repository,
+// number, category, estimated hours, elapsed and waiting hours, and summary),
+// ready for a spreadsheet.
+func (w *work) ledgerReport(ids []id, results map[id]*string) string {
+	report := "dates\trepo\titem\tcategory\thours\telapsed\twaiting\tsummary\n"
+	for _, id := range ids {
+		meta := w.getIssueOrPR(id)
+		first, last := w.period(id)
+		first, last = first.In(location), last.In(location)
+		dates := first.Format("2006-01-02")
+		if last.Format("2006-01-02") != dates {
+			dates += ".." + last.Format("2006-01-02")
+		}
+		category, summary := parseLedgerSummary(*results[id])
+		category, summary = w.overridden(id, category, summary)
+		elapsed, waiting := w.timeSplit(id)
+		report += fmt.Sprintf("%s\t%s\t%s\t%s\t%.2f\t%.2f\t%.2f\t%s\n",
+			dates, meta.repo, id, category, w.estimateHours(id), elapsed.Hours(), waiting.Hours(), summary)
+	}
+	return report
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Mentions
+
+// mention is an issue or pull request of someone else where I was mentioned.
+type mention struct {
+	repo      string // owner/repo (lowercase)
+	number    int
+	title     string
+	url       string
+	pull      bool
+	by        string    // who mentioned me (first)
+	at        time.Time // when (first, in the period)
+	responded time.Time // when I responded after it (zero if I didn't)
+}
+
+// mentionPattern returns the pattern of an @mention of the user.
+func mentionPattern(user string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(^|[^\w@/])@` + regexp.QuoteMeta(user) + `\b`)
+}
+
+// collectMentions fetches the issues and pull requests of others where I was
+// mentioned between the begin and end dates, and whether I responded: by a
+// comment, or any action of mine on the item, after the mention.
+func collectMentions(ctx context.Context, gh *github.Client, w *work, filter *filters, begin, end time.Time) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	updated := begin.UTC().Format("2006-01-02T15:04:05Z") + ".." + end.UTC().Format("2006-01-02T15:04:05Z")
+	pattern := mentionPattern(w.user)
+	mine := func(login string) bool {
+		return strings.EqualFold(login, w.user)
+	}
+	inPeriod := func(at time.Time) bool {
+		return !at.Before(begin) && at.Before(end)
+	}
+
+	opt := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := gh.Search.Issues(ctx, fmt.Sprintf("mentions:%s updated:%s", w.user, updated), opt)
+		if err != nil {
+			fmt.Println("Error searching mentions:", err)
+			return
+		}
+		for _, item := range result.Issues {
+			if mine(item.GetUser().GetLogin()) {
+				continue // my own thread
+			}
+			repo := strings.TrimPrefix(item.GetRepositoryURL(), "https://api.github.com/repos/")
+			if !filter.allows(ctx, &github.Event{Repo: &github.Repository{Name: github.String(repo)}}) {
+				continue
+			}
+			owner, name, _ := strings.Cut(repo, "/")
+
+			m := &mention{
+				repo:   strings.ToLower(repo),
+				number: item.GetNumber(),
+				title:  item.GetTitle(),
+				url:    item.GetHTMLURL(),
+				pull:   item.IsPullRequest(),
+			}
+			if inPeriod(item.GetCreatedAt()) && pattern.MatchString(item.GetBody()) {
+				m.by, m.at = item.GetUser().GetLogin(), item.GetCreatedAt()
+			}
+
+			var comments []*github.IssueComment
+			copt := &github.IssueListCommentsOptions{Since: &begin, ListOptions: github.ListOptions{PerPage: 100}}
+			for {
+				page, resp, err := gh.Issues.ListComments(ctx, owner, name, item.GetNumber(), copt)
+				if err != nil {
+					fmt.Printf("Error fetching the comments of %s#%d: %v\n", repo, item.GetNumber(), err)
+					break
+				}
+				comments = append(comments, page...)
+				if resp.NextPage == 0 {
+					break
+				}
+				copt.Page = resp.NextPage
+			}
+			m.addComments(comments, pattern, w.user, end)
+			if m.at.IsZero() {
+				continue // mentioned before the period, or in a review
+			}
+			for _, a := range w.actions[newID(repo, m.number)] {
+				if a.at.After(m.at) && (m.responded.IsZero() || a.at.Before(m.responded)) {
+					m.responded = a.at
+				}
+			}
+			w.mentions = append(w.mentions, m)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	sort.SliceStable(w.mentions, func(i, j int) bool {
+		return w.mentions[i].at.Before(w.mentions[j].at)
+	})
+}
+
+// addComments finds, in the comments (oldest first, since the begin date),
+// the first mention of the user by others before the end date and the first
+// comment of the user after it.
+func (m *mention) addComments(comments []*github.IssueComment, pattern *regexp.Regexp, user string, end time.Time) {
+	for _, c := range comments {
+		at := c.GetCreatedAt()
+		if !at.Before(end) {
+			break
+		}
+		if strings.EqualFold(c.GetUser().GetLogin(), user) {
+			if !m.at.IsZero() && m.responded.IsZero() {
+				m.responded = at
+			}
+			continue
+		}
+		if m.at.IsZero() && pattern.MatchString(c.GetBody()) {
+			m.by, m.at = c.GetUser().GetLogin(), at
+		}
+	}
+}
+
+// mentionsReport returns where I was mentioned, and whether I responded.
+func (w *work) mentionsReport() string {
+	if len(w.mentions) == 0 {
+		return ""
+	}
+
+	responded := 0
+	report := fmt.Sprintf("\n%s\n\n", msg("Mentions:"))
+	for _, m := range w.mentions {
+		kind := "Issue"
+		if m.pull {
+			kind = "Pull request"
+		}
+		line := fmt.Sprintf("%s: %s#%d (%s) %s: mentioned %s by %s", kind, m.repo, m.number, m.url, m.title, m.at.In(location).Format("2006-01-02"), m.by)
+		if m.responded.IsZero() {
+			line += ", not responded"
+		} else {
+			responded++
+			line += fmt.Sprintf(", responded %s (after %s)", m.responded.In(location).Format("2006-01-02"), formatSpan(m.responded.Sub(m.at)))
+		}
+		report += line + "\n"
+	}
+	report += fmt.Sprintf("Mentioned: %d (responded: %d)\n", len(w.mentions), responded)
+
+	return report
+}
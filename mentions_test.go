@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestMentionPattern(t *testing.T) {
+	pattern := mentionPattern("me")
+	for text, want := range map[string]bool{
+		"@me can you look?":     true,
+		"cc @Me.":               true,
+		"(@me)":                 true,
+		"@meow":                 false,
+		"mail me@example.com":   false,
+		"see owner/@me":         false,
+		"no mention here, me.":  false,
+		"team @org/me-and-you ": false,
+	} {
+		if got := pattern.MatchString(text); got != want {
+			t.Errorf("%q: got %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestMentionComments(t *testing.T) {
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	comment := func(login, body string, hours int) *github.IssueComment {
+		created := at.Add(time.Duration(hours) * time.Hour)
+		return &github.IssueComment{User: &github.User{Login: github.String(login)}, Body: github.String(body), CreatedAt: &created}
+	}
+	comments := []*github.IssueComment{
+		comment("me", "earlier comment", 0),
+		comment("alice", "@me thoughts?", 1),
+		comment("bob", "@me ping", 2),
+		comment("me", "sure", 3),
+		comment("me", "more", 4),
+	}
+
+	m := &mention{}
+	m.addComments(comments, mentionPattern("me"), "me", at.Add(24*time.Hour))
+	if m.by != "alice" || !m.at.Equal(at.Add(time.Hour)) || !m.responded.Equal(at.Add(3*time.Hour)) {
+		t.Errorf("got %s at %v, responded %v; want alice at +1h, responded +3h", m.by, m.at, m.responded)
+	}
+
+	m = &mention{}
+	m.addComments(comments, mentionPattern("me"), "me", at.Add(3*time.Hour))
+	if !m.responded.IsZero() {
+		t.Errorf("got responded %v, want none before the end", m.responded)
+	}
+}
+
+func TestMentionsReport(t *testing.T) {
+	location = time.UTC
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	w := &work{mentions: []*mention{
+		{repo: "owner/repo", number: 1, title: "Bug", url: "https://github.com/owner/repo/issues/1", by: "alice", at: at, responded: at.Add(2 * time.Hour)},
+		{repo: "owner/repo", number: 2, title: "Fix", url: "https://github.com/owner/repo/pull/2", pull: true, by: "bob", at: at},
+	}}
+
+	report := w.mentionsReport()
+	for _, want := range []string{
+		"Issue: owner/repo#1 (https://github.com/owner/repo/issues/1) Bug: mentioned 2024-03-04 by alice, responded 2024-03-04 (after ",
+		"Pull request: owner/repo#2 (https://github.com/owner/repo/pull/2) Fix: mentioned 2024-03-04 by bob, not responded\n",
+		"Mentioned: 2 (responded: 1)\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q doesn't contain %q", report, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Merge Queues
+
+// mergeQueueBot is the merge queue account, the merge actor of queued pull
+// requests.
+const mergeQueueBot = "github-merge-queue[bot]"
+
+// syntheticEvent returns an event, as the events API would, for activity
+// found elsewhere (search, pull request merges).
+func syntheticEvent(kind, repo, user string, public bool, number int, at time.Time, payload interface{}) (*github.Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	msg := json.RawMessage(raw)
+	return &github.Event{
+		ID:         github.String(fmt.Sprintf("synthetic-%s-%s-%d-%d", kind, repo, number, at.Unix())),
+		Type:       github.String(kind),
+		Repo:       &github.Repository{Name: github.String(repo)},
+		Actor:      &github.User{Login: github.String(user)},
+		Public:     github.Bool(public),
+		CreatedAt:  &at,
+		RawPayload: &msg,
+	}, nil
+}
+
+// mergedBy describes who merged the pull request: a merge queue, auto-merge
+// or someone else.
+func mergedBy(pr *github.PullRequest) string {
+	login := pr.GetMergedBy().GetLogin()
+	switch {
+	case login == mergeQueueBot:
+		return "Merged by the merge queue"
+	case pr.GetAutoMerge() != nil:
+		return fmt.Sprintf("Merged by %s (auto-merge)", login)
+	default:
+		return fmt.Sprintf("Merged by %s", login)
+	}
+}
+
+// collectQueuedMerges adds the merges done on my behalf: pull requests merged
+// by a merge queue, auto-merge or a maintainer have someone else as the merge
+// event actor, so the merge never shows up in my events. The pull requests I
+// authored, merged in the period, are searched for, even if I did nothing
+// else on them in the period.
+func collectQueuedMerges(ctx context.Context, gh *github.Client, w *work, filter *filters, begin, end time.Time) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	query := fmt.Sprintf("author:%s is:pr is:merged merged:%s..%s", w.user,
+		begin.UTC().Format("2006-01-02T15:04:05Z"), end.UTC().Format("2006-01-02T15:04:05Z"))
+
+	opt := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := gh.Search.Issues(ctx, query, opt)
+		if err != nil {
+			fmt.Printf("Error searching merged pull requests: %v\n", err)
+			return
+		}
+
+		for _, issue := range result.Issues {
+			repo := strings.TrimPrefix(issue.GetRepositoryURL(), "https://api.github.com/repos/")
+			id := newID(repo, issue.GetNumber())
+			if w.isDone(id) {
+				continue // the merge, or close, is in my events
+			}
+
+			owner, name, _ := strings.Cut(repo, "/")
+			pr, _, err := gh.PullRequests.Get(ctx, owner, name, id.number)
+			if err != nil {
+				fmt.Printf("Error fetching %s%s: %v\n", repo, id, err)
+				continue
+			}
+			merged := pr.GetMergedAt()
+			if !pr.GetMerged() || merged.Before(begin) || !merged.Before(end) {
+				continue
+			}
+
+			e, err := syntheticEvent("PullRequestEvent", repo, w.user, !pr.GetBase().GetRepo().GetPrivate(), id.number, merged,
+				&github.PullRequestEvent{Action: github.String("closed"), Number: github.Int(id.number), PullRequest: pr})
+			if err != nil || !filter.allows(ctx, e) {
+				continue
+			}
+
+			// the description is summarized, the merge has nothing to summarize
+			w.addPullRequest(e, pr)
+			w.actions[id] = append(w.actions[id], &action{
+				action:  "merged",
+				object:  ObjectPR,
+				content: mergedBy(pr),
+				updated: merged,
+				at:      merged,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			return
+		}
+		opt.Page = resp.NextPage
+	}
+}
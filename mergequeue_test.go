@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestCollectQueuedMerges(t *testing.T) {
+	merged := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/issues":
+			rw.Write([]byte(`{"total_count": 1, "items": [{"number": 7, "repository_url": "https://api.github.com/repos/owner/repo"}]}`))
+		case "/repos/owner/repo/pulls/7":
+			rw.Write([]byte(`{"number": 7, "title": "Add the thing", "merged": true, "merged_at": "2024-03-05T10:00:00Z",
+				"merged_by": {"login": "github-merge-queue[bot]"}, "user": {"login": "me"}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(server.URL + "/")
+	w := &work{user: "me", pulls: make(map[id]*metadata), actions: make(map[id][]*action)}
+	filter := &filters{repos: make(map[string]bool), orgs: make(map[string]bool)}
+
+	collectQueuedMerges(context.Background(), gh, w, filter, merged.AddDate(0, 0, -1), merged.AddDate(0, 0, 1))
+
+	actions := w.actions[newID("owner/repo", 7)]
+	if len(actions) != 1 {
+		t.Fatalf("got %d actions, want the merge", len(actions))
+	}
+	if a := actions[0]; a.action != "merged" || a.content != "Merged by the merge queue" || !a.at.Equal(merged) {
+		t.Errorf("got %+v", a)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Message Catalog
+
+// locale is the language the report section headers and labels are shown,
+// and the timecard written, in. Empty is English.
+var locale string
+
+// localeNames are the languages with translations, by locale.
+var localeNames = map[string]string{
+	"de": "German",
+	"es": "Spanish",
+	"fr": "French",
+	"pt": "Portuguese",
+}
+
+// catalog holds the translations of the section headers and labels, keyed by
+// locale and then by the English text.
+var catalog = map[string]map[string]string{
+	"de": {
+		"Issues:":                   "Issues:",
+		"Pulls:":                    "Pull Requests:",
+		"Issue:":                    "Issue:",
+		"PR:":                       "PR:",
+		"Labels:":                   "Labels:",
+		"Milestone:":                "Meilenstein:",
+		"Size:":                     "Größe:",
+		"Attribution:":              "Zuschreibung:",
+		"Category:":                 "Kategorie:",
+		"Description:":              "Beschreibung:",
+		"Estimated effort:":         "Geschätzter Aufwand:",
+		"Repository:":               "Repository:",
+		"Totals:":                   "Summen:",
+		"Workstream:":               "Arbeitsstrang:",
+		"Items:":                    "Einträge:",
+		"Summary:":                  "Zusammenfassung:",
+		"Days:":                     "Tage:",
+		"Workdays:":                 "Arbeitstage:",
+		"Timeline:":                 "Zeitleiste:",
+		"Commits:":                  "Commits:",
+		"Releases:":                 "Releases:",
+		"Milestones:":               "Meilensteine:",
+		"Issue transitions:":        "Issue-Statuswechsel:",
+		"Branches and tags:":        "Branches und Tags:",
+		"Discussions:":              "Diskussionen:",
+		"Projects:":                 "Projekte:",
+		"CI:":                       "CI:",
+		"Security:":                 "Sicherheit:",
+		"Review requests:":          "Review-Anfragen:",
+		"Review queue:":             "Review-Warteschlange:",
+		"Assigned issues:":          "Zugewiesene Issues:",
+		"Suggestions applied:":      "Übernommene Vorschläge:",
+		"Credited commits:":         "Angerechnete Commits:",
+		"Mentions:":                 "Erwähnungen:",
+		"Needs attention:":          "Braucht Aufmerksamkeit:",
+		"Docs:":                     "Dokumentation:",
+		"Cross-posted comments:":    "Mehrfach gepostete Kommentare:",
+		"Dependency maintenance:":   "Abhängigkeitspflege:",
+		"Community:":                "Community:",
+		"Areas:":                    "Bereiche:",
+		"Activity by day and hour:": "Aktivität nach Tag und Stunde:",
+		"Commit signing:":           "Commit-Signaturen:",
+		"Planned vs done:":          "Geplant und erledigt:",
+		"Commitments made:":         "Zusagen:",
+		"Weights:":                  "Gewichtung:",
+		"Billing:":                  "Abrechnung:",
+	},
+	"es": {
+		"Issues:":                   "Issues:",
+		"Pulls:":                    "Pull requests:",
+		"Issue:":                    "Issue:",
+		"PR:":                       "PR:",
+		"Labels:":                   "Etiquetas:",
+		"Milestone:":                "Hito:",
+		"Size:":                     "Tamaño:",
+		"Attribution:":              "Atribución:",
+		"Category:":                 "Categoría:",
+		"Description:":              "Descripción:",
+		"Estimated effort:":         "Esfuerzo estimado:",
+		"Repository:":               "Repositorio:",
+		"Totals:":                   "Totales:",
+		"Workstream:":               "Línea de trabajo:",
+		"Items:":                    "Elementos:",
+		"Summary:":                  "Resumen:",
+		"Days:":                     "Días:",
+		"Workdays:":                 "Jornadas:",
+		"Timeline:":                 "Cronología:",
+		"Commits:":                  "Commits:",
+		"Releases:":                 "Versiones:",
+		"Milestones:":               "Hitos:",
+		"Issue transitions:":        "Cambios de estado de issues:",
+		"Branches and tags:":        "Ramas y etiquetas:",
+		"Discussions:":              "Discusiones:",
+		"Projects:":                 "Proyectos:",
+		"CI:":                       "CI:",
+		"Security:":                 "Seguridad:",
+		"Review requests:":          "Solicitudes de revisión:",
+		"Review queue:":             "Cola de revisiones:",
+		"Assigned issues:":          "Issues asignados:",
+		"Suggestions applied:":      "Sugerencias aplicadas:",
+		"Credited commits:":         "Commits acreditados:",
+		"Mentions:":                 "Menciones:",
+		"Needs attention:":          "Requiere atención:",
+		"Docs:":                     "Documentación:",
+		"Cross-posted comments:":    "Comentarios repetidos:",
+		"Dependency maintenance:":   "Mantenimiento de dependencias:",
+		"Community:":                "Comunidad:",
+		"Areas:":                    "Áreas:",
+		"Activity by day and hour:": "Actividad por día y hora:",
+		"Commit signing:":           "Firma de commits:",
+		"Planned vs done:":          "Planificado y hecho:",
+		"Commitments made:":         "Compromisos:",
+		"Weights:":                  "Pesos:",
+		"Billing:":                  "Facturación:",
+	},
+	"fr": {
+		"Issues:":                   "Tickets :",
+		"Pulls:":                    "Pull requests :",
+		"Issue:":                    "Ticket :",
+		"PR:":                       "PR :",
+		"Labels:":                   "Étiquettes :",
+		"Milestone:":                "Jalon :",
+		"Size:":                     "Taille :",
+		"Attribution:":              "Attribution :",
+		"Category:":                 "Catégorie :",
+		"Description:":              "Description :",
+		"Estimated effort:":         "Effort estimé :",
+		"Repository:":               "Dépôt :",
+		"Totals:":                   "Totaux :",
+		"Workstream:":               "Chantier :",
+		"Items:":                    "Éléments :",
+		"Summary:":                  "Résumé :",
+		"Days:":                     "Jours :",
+		"Workdays:":                 "Journées de travail :",
+		"Timeline:":                 "Chronologie :",
+		"Commits:":                  "Commits :",
+		"Releases:":                 "Versions :",
+		"Milestones:":               "Jalons :",
+		"Issue transitions:":        "Changements d'état des tickets :",
+		"Branches and tags:":        "Branches et tags :",
+		"Discussions:":              "Discussions :",
+		"Projects:":                 "Projets :",
+		"CI:":                       "CI :",
+		"Security:":                 "Sécurité :",
+		"Review requests:":          "Demandes de revue :",
+		"Review queue:":             "File de revues :",
+		"Assigned issues:":          "Tickets assignés :",
+		"Suggestions applied:":      "Suggestions appliquées :",
+		"Credited commits:":         "Commits crédités :",
+		"Mentions:":                 "Mentions :",
+		"Needs attention:":          "Requiert votre attention :",
+		"Docs:":                     "Documentation :",
+		"Cross-posted comments:":    "Commentaires répétés :",
+		"Dependency maintenance:":   "Maintenance des dépendances :",
+		"Community:":                "Communauté :",
+		"Areas:":                    "Domaines :",
+		"Activity by day and hour:": "Activité par jour et heure :",
+		"Commit signing:":           "Signature des commits :",
+		"Planned vs done:":          "Prévu et fait :",
+		"Commitments made:":         "Engagements pris :",
+		"Weights:":                  "Pondérations :",
+		"Billing:":                  "Facturation :",
+	},
+	"pt": {
+		"Issues:":                   "Issues:",
+		"Pulls:":                    "Pull requests:",
+		"Issue:":                    "Issue:",
+		"PR:":                       "PR:",
+		"Labels:":                   "Etiquetas:",
+		"Milestone:":                "Marco:",
+		"Size:":                     "Tamanho:",
+		"Attribution:":              "Atribuição:",
+		"Category:":                 "Categoria:",
+		"Description:":              "Descrição:",
+		"Estimated effort:":         "Esforço estimado:",
+		"Repository:":               "Repositório:",
+		"Totals:":                   "Totais:",
+		"Workstream:":               "Frente de trabalho:",
+		"Items:":                    "Itens:",
+		"Summary:":                  "Resumo:",
+		"Days:":                     "Dias:",
+		"Workdays:":                 "Dias de trabalho:",
+		"Timeline:":                 "Linha do tempo:",
+		"Commits:":                  "Commits:",
+		"Releases:":                 "Versões:",
+		"Milestones:":               "Marcos:",
+		"Issue transitions:":        "Mudanças de estado das issues:",
+		"Branches and tags:":        "Branches e tags:",
+		"Discussions:":              "Discussões:",
+		"Projects:":                 "Projetos:",
+		"CI:":                       "CI:",
+		"Security:":                 "Segurança:",
+		"Review requests:":          "Pedidos de revisão:",
+		"Review queue:":             "Fila de revisões:",
+		"Assigned issues:":          "Issues atribuídas:",
+		"Suggestions applied:":      "Sugestões aplicadas:",
+		"Credited commits:":         "Commits creditados:",
+		"Mentions:":                 "Menções:",
+		"Needs attention:":          "Requer atenção:",
+		"Docs:":                     "Documentação:",
+		"Cross-posted comments:":    "Comentários repetidos:",
+		"Dependency maintenance:":   "Manutenção de dependências:",
+		"Community:":                "Comunidade:",
+		"Areas:":                    "Áreas:",
+		"Activity by day and hour:": "Atividade por dia e hora:",
+		"Commit signing:":           "Assinatura de commits:",
+		"Planned vs done:":          "Planejado e feito:",
+		"Commitments made:":         "Compromissos:",
+		"Weights:":                  "Pesos:",
+		"Billing:":                  "Faturamento:",
+	},
+}
+
+// msg returns the text translated to the locale, or as it is if there is no
+// translation.
+func msg(text string) string {
+	if translated, ok := catalog[locale][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// validLocale returns true for English (empty or en) and the locales with
+// translations.
+func validLocale(l string) bool {
+	_, ok := catalog[l]
+	return l == "" || l == "en" || ok
+}
+
+// locales returns the locales with translations, sorted.
+func locales() string {
+	names := []string{"en"}
+	for l := range catalog {
+		names = append(names, l)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
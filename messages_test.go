@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestCatalog(t *testing.T) {
+	for l, messages := range catalog {
+		if localeNames[l] == "" {
+			t.Errorf("locale %s has no name", l)
+		}
+		for text := range catalog["pt"] {
+			if messages[text] == "" {
+				t.Errorf("locale %s has no translation of %q", l, text)
+			}
+		}
+		if len(messages) != len(catalog["pt"]) {
+			t.Errorf("locale %s has %d messages, want %d", l, len(messages), len(catalog["pt"]))
+		}
+	}
+}
+
+func TestMsg(t *testing.T) {
+	defer func() { locale = "" }()
+
+	locale = ""
+	if got := msg("Releases:"); got != "Releases:" {
+		t.Errorf("got %q in English", got)
+	}
+
+	locale = "pt"
+	if got := msg("Releases:"); got != "Versões:" {
+		t.Errorf("got %q, want Versões:", got)
+	}
+	if got := msg("Not in the catalog:"); got != "Not in the catalog:" {
+		t.Errorf("got %q, want it as it is", got)
+	}
+
+	for l, want := range map[string]bool{"": true, "en": true, "pt": true, "fr": true, "xx": false} {
+		if got := validLocale(l); got != want {
+			t.Errorf("validLocale(%q) = %t, want %t", l, got, want)
+		}
+	}
+	if got := locales(); got != "de, en, es, fr, pt" {
+		t.Errorf("got locales %q", got)
+	}
+}
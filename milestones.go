@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Milestones
+
+// milestone is a milestone I created, closed or edited.
+type milestone struct {
+	repo    string // owner/repo (lowercase)
+	title   string
+	url     string
+	state   string // open or closed
+	due     string // YYYY-MM-DD, empty if none
+	actions []string
+}
+
+// addMilestone records an action (created, closed, edited, ...) on a milestone.
+func (w *work) addMilestone(e *github.Event, m *github.Milestone, action string) {
+	url := m.GetHTMLURL()
+
+	ms, ok := w.milestones[url]
+	if !ok {
+		ms = &milestone{
+			repo:  strings.ToLower(e.GetRepo().GetName()),
+			title: m.GetTitle(),
+			url:   url,
+		}
+		w.milestones[url] = ms
+		w.milestoneOrder = append(w.milestoneOrder, url)
+	}
+	ms.state = m.GetState()
+	if m.DueOn != nil {
+		ms.due = m.DueOn.In(location).Format("2006-01-02")
+	}
+
+	ms.actions = append(ms.actions, action)
+}
+
+// milestonesReport returns the milestones I acted on, and the issues and pull
+// requests of each milestone I worked on.
+func (w *work) milestonesReport() string {
+	items := make(map[string][]string) // keyed by owner/repo and milestone title
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			if meta := place[id]; meta.milestone != "" {
+				key := meta.repo + " " + meta.milestone
+				items[key] = append(items[key], id.String())
+			}
+		}
+	}
+	if len(w.milestones) == 0 && len(items) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Milestones:"))
+	for _, url := range w.milestoneOrder {
+		ms := w.milestones[url]
+		line := fmt.Sprintf("Milestone: %s %s (%s) %s", ms.repo, ms.title, ms.url, ms.state)
+		if ms.due != "" {
+			line += ", due " + ms.due
+		}
+		report += line + "\n"
+		report += fmt.Sprintf("Actions: %s\n", strings.Join(ms.actions, ", "))
+		key := ms.repo + " " + ms.title
+		if len(items[key]) > 0 {
+			report += fmt.Sprintf("Items: %s\n", strings.Join(items[key], ", "))
+			delete(items, key)
+		}
+	}
+
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		repo, title, _ := strings.Cut(key, " ")
+		report += fmt.Sprintf("Milestone: %s %s\n", repo, title)
+		report += fmt.Sprintf("Items: %s\n", strings.Join(items[key], ", "))
+	}
+
+	return report
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestMilestonesReport(t *testing.T) {
+	location = time.UTC
+	due := time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC)
+	w := &work{
+		milestones: make(map[string]*milestone),
+		issues:     map[id]*metadata{newID("owner/repo", 1): {repo: "owner/repo", milestone: "v1.0"}},
+		pulls: map[id]*metadata{
+			newID("owner/repo", 2): {repo: "owner/repo", milestone: "v1.0"},
+			newID("owner/repo", 3): {repo: "owner/repo", milestone: "v2.0"},
+			newID("owner/repo", 4): {repo: "owner/repo"},
+		},
+	}
+	e := &github.Event{Repo: &github.Repository{Name: github.String("Owner/Repo")}}
+	m := &github.Milestone{
+		Title:   github.String("v1.0"),
+		HTMLURL: github.String("https://github.com/owner/repo/milestone/1"),
+		State:   github.String("open"),
+		DueOn:   &due,
+	}
+	w.addMilestone(e, m, "created")
+	m.State = github.String("closed")
+	w.addMilestone(e, m, "closed")
+
+	want := `
+Milestones:
+
+Milestone: owner/repo v1.0 (https://github.com/owner/repo/milestone/1) closed, due 2024-03-31
+Actions: created, closed
+Items: #1, #2
+Milestone: owner/repo v2.0
+Items: #3
+`
+	if got := w.milestonesReport(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := (&work{}).milestonesReport(); got != "" {
+		t.Errorf("got %q, want no section", got)
+	}
+}
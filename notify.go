@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Desktop Notifications
+
+// notifyEnabled is true if a desktop notification is wanted when the run ends.
+var notifyEnabled bool
+
+// notify shows a desktop notification, if wanted. Failing to is not an error
+// of the run.
+func notify(message string) {
+	if !notifyEnabled {
+		return
+	}
+	cmd := notifyCommand(runtime.GOOS, "ghtimecardator", message)
+	if cmd == nil {
+		fmt.Fprintln(os.Stderr, "Desktop notifications are not supported on", runtime.GOOS)
+		return
+	}
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error notifying:", err)
+	}
+}
+
+// notifyCommand returns the command showing a desktop notification on the
+// system: osascript on macOS, notify-send (libnotify) on Linux and the BSDs,
+// and a PowerShell toast on Windows. It is nil on other systems.
+func notifyCommand(goos, title, message string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, quote.Replace(message), quote.Replace(title))
+		return exec.Command("osascript", "-e", script)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return exec.Command("notify-send", title, message)
+	case "windows":
+		quote := strings.NewReplacer(`'`, `''`)
+		script := fmt.Sprintf(windowsToast, quote.Replace(title), quote.Replace(message))
+		return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	}
+	return nil
+}
+
+// windowsToast shows a toast (title and message) as Windows PowerShell.
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$toast = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $toast.GetElementsByTagName('text')
+$text[0].AppendChild($toast.CreateTextNode('%s')) > $null
+$text[1].AppendChild($toast.CreateTextNode('%s')) > $null
+$app = '{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($app).Show([Windows.UI.Notifications.ToastNotification]::new($toast))`
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	cmd := notifyCommand("darwin", "ghtimecardator", `The "timecard" is ready`)
+	if got, want := cmd.Args[2], `display notification "The \"timecard\" is ready" with title "ghtimecardator"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	cmd = notifyCommand("linux", "ghtimecardator", "The timecard is ready")
+	if got, want := strings.Join(cmd.Args, "|"), "notify-send|ghtimecardator|The timecard is ready"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	cmd = notifyCommand("windows", "ghtimecardator", "It's ready")
+	if script := cmd.Args[len(cmd.Args)-1]; !strings.Contains(script, "CreateTextNode('It''s ready')") {
+		t.Errorf("script %q doesn't quote the message", script)
+	}
+
+	if cmd := notifyCommand("plan9", "ghtimecardator", "ready"); cmd != nil {
+		t.Errorf("got %v, want no command", cmd.Args)
+	}
+}
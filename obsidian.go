@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Obsidian Delivery
+
+// DeliverObsidian delivers the timecard as a note in an Obsidian vault.
+const DeliverObsidian = "obsidian"
+
+// dailyNoteFormat is the name of the Obsidian daily notes (their default).
+const dailyNoteFormat = "2006-01-02"
+
+// validDeliver returns true for the known deliveries (or none).
+func validDeliver(deliver string) bool {
+	return deliver == "" || deliver == DeliverObsidian
+}
+
+// lastDay returns the last day of the period (the end is exclusive, and zero
+// for now).
+func lastDay(end time.Time) time.Time {
+	if end.IsZero() {
+		return startOfDay(time.Now())
+	}
+	return startOfDay(end.Add(-time.Nanosecond))
+}
+
+// obsidianNoteName returns the name of the note of the period.
+func obsidianNoteName(begin, end time.Time) string {
+	first, last := begin.In(location).Format(dailyNoteFormat), lastDay(end).Format(dailyNoteFormat)
+	if first == last {
+		return "Timecard " + first + ".md"
+	}
+	return "Timecard " + first + " to " + last + ".md"
+}
+
+// activeDays returns the days (daily note names) I acted on something.
+func (w *work) activeDays() []string {
+	seen := make(map[string]bool)
+	for _, actions := range w.actions {
+		for _, a := range actions {
+			if !a.at.IsZero() {
+				seen[a.at.In(location).Format(dailyNoteFormat)] = true
+			}
+		}
+	}
+
+	days := make([]string, 0, len(seen))
+	for day := range seen {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days
+}
+
+// obsidianNote returns the note of the period: the timecard with wiki-links
+// to a note per repository, issue and pull request, and backlinks to the
+// daily notes of the days I was active.
+func (w *work) obsidianNote(timecard, summaryType string, begin, end time.Time) string {
+	first, last := begin.In(location).Format(dailyNoteFormat), lastDay(end).Format(dailyNoteFormat)
+	note := fmt.Sprintf("---\nbegin: %s\nend: %s\nsummary: %s\nuser: %s\ntags: [timecard]\n---\n\n", first, last, summaryType, w.user)
+	note += strings.TrimSpace(timecard) + "\n"
+
+	if repos := w.activeRepos(); len(repos) > 0 {
+		note += "\n## Repositories\n\n"
+		for _, repo := range repos {
+			note += fmt.Sprintf("- [[%s]]\n", repo)
+		}
+	}
+	for _, section := range []struct {
+		header string
+		place  map[id]*metadata
+	}{{"Issues", w.issues}, {"Pull requests", w.pulls}} {
+		ids := w.sortedIds(section.place)
+		if len(ids) == 0 {
+			continue
+		}
+		note += fmt.Sprintf("\n## %s\n\n", section.header)
+		for _, id := range ids {
+			meta := section.place[id]
+			note += fmt.Sprintf("- [[%s/%d|%s#%d]] %s\n", meta.repo, id.number, meta.repo, id.number, meta.title)
+		}
+	}
+	if days := w.activeDays(); len(days) > 0 {
+		note += "\n## Days\n\n"
+		for _, day := range days {
+			note += fmt.Sprintf("- [[%s]]\n", day)
+		}
+	}
+
+	return note
+}
+
+// writeObsidianNote writes the note of the period to the vault folder, and
+// returns its path.
+func (w *work) writeObsidianNote(vault, timecard, summaryType string, begin, end time.Time) (string, error) {
+	if err := os.MkdirAll(vault, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(vault, obsidianNoteName(begin, end))
+	return path, os.WriteFile(path, []byte(w.obsidianNote(timecard, summaryType, begin, end)), 0o644)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestObsidianNote(t *testing.T) {
+	location = time.UTC
+	begin := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := begin.AddDate(0, 0, 7)
+
+	if got := obsidianNoteName(begin, begin.AddDate(0, 0, 1)); got != "Timecard 2024-03-04.md" {
+		t.Errorf("got %q for a day", got)
+	}
+
+	i1, p2 := newID("owner/repo", 1), newID("owner/repo", 2)
+	w := &work{
+		user:   "me",
+		issues: map[id]*metadata{i1: {repo: "owner/repo", title: "Crash on start"}},
+		pulls:  map[id]*metadata{p2: {repo: "owner/repo", title: "Fix the crash"}},
+		actions: map[id][]*action{
+			i1: {{at: begin.Add(9 * time.Hour)}},
+			p2: {{at: begin.Add(33 * time.Hour)}, {at: begin.Add(34 * time.Hour)}},
+		},
+	}
+
+	want := `---
+begin: 2024-03-04
+end: 2024-03-10
+summary: executive
+user: me
+tags: [timecard]
+---
+
+Fixed the crash on start.
+
+## Repositories
+
+- [[owner/repo]]
+
+## Issues
+
+- [[owner/repo/1|owner/repo#1]] Crash on start
+
+## Pull requests
+
+- [[owner/repo/2|owner/repo#2]] Fix the crash
+
+## Days
+
+- [[2024-03-04]]
+- [[2024-03-05]]
+`
+	if got := w.obsidianNote("Fixed the crash on start.\n\n", "executive", begin, end); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	vault := filepath.Join(t.TempDir(), "Work Logs")
+	path, err := w.writeObsidianNote(vault, "Fixed the crash on start.", "executive", begin, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(vault, "Timecard 2024-03-04 to 2024-03-10.md")); err != nil || string(data) != want {
+		t.Errorf("wrote %s: %q, %v", path, data, err)
+	}
+}
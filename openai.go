@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// OpenAI Client
+
+// headerTransport adds fixed headers to every request.
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newOpenAIClient returns an OpenAI chat client, for the model, that bills the given
+// organization and project (if set) and retries transient errors.
+func newOpenAIClient(model, token, organization, project string, retry *retryTransport) (*openai.Chat, error) {
+	opts := []openai.Option{
+		openai.WithModel(model),
+		openai.WithToken(token),
+	}
+	if organization != "" {
+		opts = append(opts, openai.WithOrganization(organization))
+	}
+
+	retry.base = http.DefaultTransport
+	if project != "" {
+		retry.base = &headerTransport{
+			headers: map[string]string{"OpenAI-Project": project},
+			base:    http.DefaultTransport,
+		}
+	}
+	opts = append(opts, openai.WithHTTPClient(&http.Client{Transport: retry}))
+
+	return openai.NewChat(opts...)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overrides
+
+// override is my correction of an issue or pull request, applied over what was
+// summarized and estimated, whatever the cache or the run.
+type override struct {
+	Category string   `yaml:"category"` // of the ledger, or of the changelog
+	Hours    *float64 `yaml:"hours"`
+	Summary  string   `yaml:"summary"`
+}
+
+// loadOverrides reads a YAML map of owner/repo#number to override.
+func loadOverrides(path string) (map[id]*override, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var byRef map[string]*override
+	if err := yaml.Unmarshal(data, &byRef); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	overrides := make(map[id]*override)
+	for ref, o := range byRef {
+		id, err := parseRef(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if o == nil {
+			continue
+		}
+		if o.Hours != nil && *o.Hours < 0 {
+			return nil, fmt.Errorf("%s: %s: invalid hours %v", path, ref, *o.Hours)
+		}
+		overrides[id] = o
+	}
+	return overrides, nil
+}
+
+// overrideSummaries replaces the summaries of the items with the ones of the
+// overrides.
+func (w *work) overrideSummaries(results map[id]*string) {
+	for id, o := range w.overrides {
+		if result := results[id]; result != nil && o.Summary != "" {
+			*result = o.Summary
+		}
+	}
+}
+
+// overridden returns the category and summary of the item, or the ones of its
+// override.
+func (w *work) overridden(id id, category, summary string) (string, string) {
+	if o := w.overrides[id]; o != nil {
+		if o.Category != "" {
+			category = o.Category
+		}
+		if o.Summary != "" {
+			summary = o.Summary
+		}
+	}
+	return category, summary
+}
+
+// overriddenHours returns the hours of the override of the item, if any.
+func (w *work) overriddenHours(id id) (float64, bool) {
+	if o := w.overrides[id]; o != nil && o.Hours != nil {
+		return *o.Hours, true
+	}
+	return 0, false
+}
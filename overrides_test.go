@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOverrides(t *testing.T) {
+	location = time.UTC
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	os.WriteFile(path, []byte(`
+owner/repo#1:
+  category: fix
+  hours: 2.5
+  summary: Fixed the crash on start
+Owner/Repo#2:
+  summary: Documented the flags
+`), 0o644)
+
+	overrides, err := loadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i1, p2 := newID("owner/repo", 1), newID("owner/repo", 2)
+	if len(overrides) != 2 || *overrides[i1].Hours != 2.5 || overrides[p2].Summary != "Documented the flags" {
+		t.Fatalf("got %v", overrides)
+	}
+
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "owner/repo"}},
+		pulls:  map[id]*metadata{p2: {repo: "owner/repo"}},
+		actions: map[id][]*action{
+			i1: {{at: day, updated: day, object: ObjectIssueComment}},
+			p2: {{at: day, updated: day, object: ObjectPRComment}},
+		},
+		responses: make(map[id][]time.Time),
+		scorer:    defaultScorer{},
+		overrides: overrides,
+	}
+
+	results := map[id]*string{i1: new(string), p2: new(string)}
+	*results[i1], *results[p2] = "Commented on the crash", "Reviewed the docs"
+	w.overrideSummaries(results)
+	if *results[i1] != "Fixed the crash on start" || *results[p2] != "Documented the flags" {
+		t.Errorf("got %q, %q", *results[i1], *results[p2])
+	}
+
+	if got := w.estimateEffort(i1, defaultEffort.withDefaults()); got != 2.5 {
+		t.Errorf("estimated %v, want the 2.5 overridden", got)
+	}
+	if got := w.estimateEffort(p2, defaultEffort.withDefaults()); got != 0.25 {
+		t.Errorf("estimated %v, want 0.25", got)
+	}
+
+	rows := map[id]*string{i1: new(string), p2: new(string)}
+	*rows[i1], *rows[p2] = "discussion: commented on the crash", "review: reviewed the docs"
+	report := w.ledgerReport([]id{i1, p2}, rows)
+	for _, want := range []string{
+		"2024-03-04\towner/repo\t#1\tfix\t2.50\t0.00\t0.00\tFixed the crash on start\n",
+		"\t#2\treview\t",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("ledger doesn't have %q:\n%s", want, report)
+		}
+	}
+
+	os.WriteFile(path, []byte("owner/repo: {hours: 1}\n"), 0o644)
+	if _, err := loadOverrides(path); err == nil {
+		t.Errorf("no error for an invalid reference")
+	}
+}
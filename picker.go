@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Interactive Repo/Org Picker
+
+// pickRepos lists the repositories, and organizations, with recent activity
+// (from the first page of events) and asks which ones to include. An empty
+// answer includes everything.
+func pickRepos(ctx context.Context, gh *github.Client, user string) (repos, orgs []string, err error) {
+	events, _, err := gh.Activity.ListEventsPerformedByUser(ctx, user, false, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repoCount := make(map[string]int)
+	orgCount := make(map[string]int)
+	for _, e := range events {
+		repo := strings.ToLower(e.GetRepo().GetName())
+		owner, _, _ := strings.Cut(repo, "/")
+		repoCount[repo]++
+		orgCount[owner]++
+	}
+	if len(repoCount) == 0 {
+		return nil, nil, nil
+	}
+
+	byCount := func(count map[string]int) []string {
+		names := make([]string, 0, len(count))
+		for name := range count {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if count[names[i]] != count[names[j]] {
+				return count[names[i]] > count[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		return names
+	}
+
+	var choices []string
+	isOrg := make(map[int]bool)
+
+	fmt.Println("Repositories with recent activity:")
+	for _, repo := range byCount(repoCount) {
+		choices = append(choices, repo)
+		fmt.Printf("  %2d) %s (%d events)\n", len(choices), repo, repoCount[repo])
+	}
+	fmt.Println("Organizations:")
+	for _, org := range byCount(orgCount) {
+		choices = append(choices, org)
+		isOrg[len(choices)] = true
+		fmt.Printf("  %2d) %s (%d events)\n", len(choices), org, orgCount[org])
+	}
+	fmt.Print("Include (comma separated numbers, empty for all): ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, field := range strings.Split(answer, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(choices) {
+			return nil, nil, fmt.Errorf("invalid choice %q", field)
+		}
+		if isOrg[n] {
+			orgs = append(orgs, choices[n-1])
+		} else {
+			repos = append(repos, choices[n-1])
+		}
+	}
+
+	return repos, orgs, nil
+}
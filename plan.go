@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"gopkg.in/yaml.v3"
+)
+
+// Planned vs Actual Work
+
+const (
+	PlanDone       = "done"
+	PlanInProgress = "in progress"
+	PlanNotStarted = "not started"
+	PlanUntracked  = "untracked"
+)
+
+// planItem is something I intended to work on: an issue or pull request
+// (ref: owner/repo#123) and/or a free form goal (title).
+type planItem struct {
+	Ref   string `yaml:"ref"`
+	Title string `yaml:"title"`
+}
+
+// parseRef parses an owner/repo#123 reference.
+func parseRef(ref string) (id, error) {
+	repo, num, ok := strings.Cut(ref, "#")
+	if !ok || !strings.Contains(repo, "/") {
+		return id{}, fmt.Errorf("invalid reference %q (want owner/repo#number)", ref)
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return id{}, fmt.Errorf("invalid reference %q: %w", ref, err)
+	}
+	return newID(repo, n), nil
+}
+
+// loadPlan reads a YAML list of plan items.
+func loadPlan(path string) ([]*planItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan []*planItem
+	if err := yaml.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, p := range plan {
+		if p.Ref != "" {
+			if _, err := parseRef(p.Ref); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// milestonePlan returns the issues and pull requests of a milestone, given as
+// owner/repo:title (or owner/repo:number), as plan items.
+func milestonePlan(ctx context.Context, gh *github.Client, milestone string) ([]*planItem, error) {
+	repo, wanted, ok := strings.Cut(milestone, ":")
+	owner, name, ok2 := strings.Cut(repo, "/")
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("invalid milestone %q (want owner/repo:title)", milestone)
+	}
+
+	milestones, _, err := gh.Issues.ListMilestones(ctx, owner, name, &github.MilestoneListOptions{
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	number := 0
+	for _, m := range milestones {
+		if m.GetTitle() == wanted || strconv.Itoa(m.GetNumber()) == wanted {
+			number = m.GetNumber()
+			break
+		}
+	}
+	if number == 0 {
+		return nil, fmt.Errorf("milestone %q not found in %s", wanted, repo)
+	}
+
+	var plan []*planItem
+	opt := &github.IssueListByRepoOptions{
+		Milestone:   strconv.Itoa(number),
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := gh.Issues.ListByRepo(ctx, owner, name, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			plan = append(plan, &planItem{
+				Ref:   fmt.Sprintf("%s#%d", repo, issue.GetNumber()),
+				Title: issue.GetTitle(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return plan, nil
+}
+
+// planStatus returns what happened, in the period, to a planned item.
+func (w *work) planStatus(p *planItem) (string, *metadata) {
+	if p.Ref == "" {
+		return PlanUntracked, nil
+	}
+	num, _ := parseRef(p.Ref)
+
+	meta := w.getIssueOrPR(num)
+	if meta == nil {
+		return PlanNotStarted, nil
+	}
+	if w.isDone(num) {
+		return PlanDone, meta
+	}
+	return PlanInProgress, meta
+}
+
+// planReport returns the planned vs done comparison. Work that was done but
+// not planned is listed as unplanned.
+func (w *work) planReport(plan []*planItem) string {
+	if len(plan) == 0 {
+		return ""
+	}
+
+	planned := make(map[*metadata]bool)
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Planned vs done:"))
+	for _, p := range plan {
+		status, meta := w.planStatus(p)
+		title := p.Title
+		if meta != nil {
+			planned[meta] = true
+			if title == "" {
+				title = meta.title
+			}
+		}
+		ref := p.Ref
+		if ref == "" {
+			ref = "-"
+		}
+		report += fmt.Sprintf("  %-12s %-40s %s\n", status, ref, title)
+	}
+
+	var unplanned []string
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			if meta := place[id]; !planned[meta] {
+				unplanned = append(unplanned, fmt.Sprintf("%s%s %s", meta.repo, id, meta.title))
+			}
+		}
+	}
+	if len(unplanned) > 0 {
+		report += fmt.Sprintf("\n  unplanned: %d items\n", len(unplanned))
+		for _, u := range unplanned {
+			report += fmt.Sprintf("    %s\n", u)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Concurrent Summarization
+
+// job is a single LLM call whose answer is stored in result.
+type job struct {
+	repo    string        // owner/repo of the item
+	item    string        // item the tokens are accounted to
+	role    string        // system prompt
+	instr   string        // user prompt
+	length  int           // maximum answer length
+	result  *string       // where the answer goes
+	updated time.Time     // when the summarized content was last updated
+	event   *github.Event // event the job comes from (nil for item summaries)
+	model   string        // model that answered (empty: the primary model)
+	err     error         // why the job failed (after all retries)
+}
+
+// pool runs jobs with a bounded number of workers, within a budget.
+type pool struct {
+	workers int
+	budget  *budget
+}
+
+// run executes all jobs and returns the ones skipped because the budget was
+// exceeded. Each answer goes to its job result, so the order of the results
+// does not depend on the order the jobs finish.
+func (p *pool) run(jobs []*job) []*job {
+	var mu sync.Mutex
+	var skipped []*job
+
+	queue := make(chan *job)
+	wg := sync.WaitGroup{}
+
+	workers := p.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				if p.budget.exceeded(tokenUsage) {
+					mu.Lock()
+					skipped = append(skipped, j)
+					mu.Unlock()
+					continue
+				}
+				if answer, ok := summaries.get(j); ok {
+					*j.result = answer
+					db.saveSummary(j, answer)
+					continue
+				}
+				// items with many actions may not fit the context window
+				answer, err := summarize(j.repo+j.item, j.role, j.instr, j.length)
+				if err != nil {
+					j.err = err
+					continue
+				}
+				*j.result = answer
+				// the model in use after the call: a fallback, if any
+				// happened, answered
+				if m := models.model(); m.name != openAIModel {
+					j.model = m.name
+				}
+				summaries.put(j, answer)
+				db.saveSummary(j, answer)
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+
+	return skipped
+}
+
+// rateLimiter keeps the calls, and tokens, under the per-minute limits.
+type rateLimiter struct {
+	rpm int // requests per minute, 0 means no limit
+	tpm int // tokens per minute, 0 means no limit
+
+	mu     sync.Mutex
+	window []rateEntry // calls made in the last minute
+}
+
+type rateEntry struct {
+	at     time.Time
+	tokens int
+}
+
+// wait blocks until a call using the given tokens fits in the limits.
+func (r *rateLimiter) wait(tokens int) {
+	if r.rpm == 0 && r.tpm == 0 {
+		return
+	}
+
+	for {
+		r.mu.Lock()
+
+		now := time.Now()
+		for len(r.window) > 0 && now.Sub(r.window[0].at) >= time.Minute {
+			r.window = r.window[1:]
+		}
+
+		used := 0
+		for _, e := range r.window {
+			used += e.tokens
+		}
+
+		fitsRequests := r.rpm == 0 || len(r.window) < r.rpm
+		fitsTokens := r.tpm == 0 || used+tokens <= r.tpm || len(r.window) == 0
+
+		if fitsRequests && fitsTokens {
+			r.window = append(r.window, rateEntry{at: now, tokens: tokens})
+			r.mu.Unlock()
+			return
+		}
+
+		// wait for the oldest call to leave the window
+		sleep := time.Minute - now.Sub(r.window[0].at)
+		r.mu.Unlock()
+
+		time.Sleep(sleep)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Cache Warm-Up
+
+// prefetchSummaries summarizes the completed items into the cache. Open items
+// are left alone: they will change before the report is generated. The
+// descriptions and comments are expected to be summarized already.
+func prefetchSummaries(w *work, p *pool) {
+	var jobs []*job
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			if w.isDone(id) {
+				jobs = append(jobs, w.actionSummary(id, new(string)))
+			}
+		}
+	}
+	p.run(jobs)
+	w.addFailures(jobs)
+
+	fmt.Printf("Prefetched: %d descriptions and comments, %d completed items\n", len(w.pending), len(jobs))
+
+	if ledger := w.unprocessedLedger(); ledger != "" {
+		fmt.Println(ledger)
+	}
+	if fallbacks := models.report(); fallbacks != "" {
+		fmt.Println(fallbacks)
+	}
+	if summary := tokenUsage.summary(); summary != "" {
+		fmt.Println(summary)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Projects (v2)
+
+const projectsQuery = `
+query {
+  viewer {
+    projectsV2(first: 50) { nodes { ...project } }
+    organizations(first: 50) {
+      nodes { projectsV2(first: 50) { nodes { ...project } } }
+    }
+  }
+}
+
+fragment project on ProjectV2 {
+  id title url updatedAt
+}`
+
+const projectItemsQuery = `
+query($project: ID!, $after: String) {
+  node(id: $project) {
+    ... on ProjectV2 {
+      items(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          createdAt
+          creator { login }
+          content {
+            ... on Issue { number title url repository { nameWithOwner } }
+            ... on PullRequest { number title url repository { nameWithOwner } }
+            ... on DraftIssue { title }
+          }
+          fieldValues(first: 20) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue {
+                name updatedAt
+                creator { login }
+                field { ... on ProjectV2SingleSelectField { name } }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type projectNode struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type projectItemNode struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Creator   struct {
+		Login string `json:"login"`
+	} `json:"creator"`
+	Content struct {
+		Number     int    `json:"number"`
+		Title      string `json:"title"`
+		URL        string `json:"url"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	} `json:"content"`
+	FieldValues struct {
+		Nodes []struct {
+			Name      string    `json:"name"`
+			UpdatedAt time.Time `json:"updatedAt"`
+			Creator   struct {
+				Login string `json:"login"`
+			} `json:"creator"`
+			Field struct {
+				Name string `json:"name"`
+			} `json:"field"`
+		} `json:"nodes"`
+	} `json:"fieldValues"`
+}
+
+// projectChange is something I did on a project board.
+type projectChange struct {
+	what string // added, or Field: value
+	item string // owner/repo#number title (or the draft title)
+	when time.Time
+}
+
+type project struct {
+	title   string
+	url     string
+	changes []*projectChange
+}
+
+// title returns how the item is shown in the report.
+func (n *projectItemNode) title() string {
+	c := n.Content
+	if c.Repository.NameWithOwner == "" {
+		return "draft: " + c.Title
+	}
+	return fmt.Sprintf("%s#%d (%s) %s", strings.ToLower(c.Repository.NameWithOwner), c.Number, c.URL, c.Title)
+}
+
+// collectProjects adds what the user did, between the begin and end dates, on
+// the project boards (v2) of the user and their organizations: items added,
+// and single select fields (status, column, priority, ...) set. Only the
+// last change of a field is known, so earlier changes of the period are
+// missing.
+func collectProjects(ctx context.Context, gh *github.Client, w *work, begin, end time.Time) error {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	inPeriod := func(at time.Time) bool {
+		return !at.Before(begin) && at.Before(end)
+	}
+	mine := func(login string) bool {
+		return strings.EqualFold(login, w.user)
+	}
+
+	var data struct {
+		Viewer struct {
+			ProjectsV2 struct {
+				Nodes []projectNode `json:"nodes"`
+			} `json:"projectsV2"`
+			Organizations struct {
+				Nodes []struct {
+					ProjectsV2 struct {
+						Nodes []projectNode `json:"nodes"`
+					} `json:"projectsV2"`
+				} `json:"nodes"`
+			} `json:"organizations"`
+		} `json:"viewer"`
+	}
+	if err := graphQL(ctx, gh, projectsQuery, nil, &data); err != nil {
+		return err
+	}
+	projects := data.Viewer.ProjectsV2.Nodes
+	for _, org := range data.Viewer.Organizations.Nodes {
+		projects = append(projects, org.ProjectsV2.Nodes...)
+	}
+
+	for _, p := range projects {
+		if p.UpdatedAt.Before(begin) {
+			continue // nothing changed in the period
+		}
+
+		board := &project{title: p.Title, url: p.URL}
+		vars := map[string]interface{}{"project": p.ID}
+		for more := true; more; {
+			var items struct {
+				Node struct {
+					Items struct {
+						PageInfo pageInfo          `json:"pageInfo"`
+						Nodes    []projectItemNode `json:"nodes"`
+					} `json:"items"`
+				} `json:"node"`
+			}
+			if err := graphQL(ctx, gh, projectItemsQuery, vars, &items); err != nil {
+				return fmt.Errorf("%s: %w", p.Title, err)
+			}
+
+			for _, n := range items.Node.Items.Nodes {
+				if mine(n.Creator.Login) && inPeriod(n.CreatedAt) {
+					board.changes = append(board.changes, &projectChange{what: "added", item: n.title(), when: n.CreatedAt})
+				}
+				for _, v := range n.FieldValues.Nodes {
+					if v.Field.Name == "" || !mine(v.Creator.Login) || !inPeriod(v.UpdatedAt) {
+						continue
+					}
+					what := fmt.Sprintf("%s: %s", v.Field.Name, v.Name)
+					board.changes = append(board.changes, &projectChange{what: what, item: n.title(), when: v.UpdatedAt})
+				}
+			}
+
+			more = nextPage(vars, "after", items.Node.Items.PageInfo)
+		}
+
+		if len(board.changes) > 0 {
+			sort.SliceStable(board.changes, func(i, j int) bool {
+				return board.changes[i].when.Before(board.changes[j].when)
+			})
+			w.projects = append(w.projects, board)
+		}
+	}
+
+	return nil
+}
+
+// projectsReport returns the project boards section of the report.
+func (w *work) projectsReport() string {
+	if len(w.projects) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Projects:"))
+	for _, p := range w.projects {
+		report += fmt.Sprintf("Project: %s (%s)\n", p.title, p.url)
+		for _, c := range p.changes {
+			report += fmt.Sprintf("  %s %s: %s\n", c.when.In(location).Format("2006-01-02"), c.what, c.item)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectsReport(t *testing.T) {
+	location = time.UTC
+
+	var items []projectItemNode
+	err := json.Unmarshal([]byte(`[
+		{"content": {"number": 7, "title": "Fix the thing", "url": "https://github.com/owner/repo/issues/7",
+		             "repository": {"nameWithOwner": "Owner/Repo"}}},
+		{"content": {"title": "Write the blog post"}}
+	]`), &items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	w := &work{projects: []*project{{
+		title: "Roadmap",
+		url:   "https://github.com/orgs/owner/projects/1",
+		changes: []*projectChange{
+			{what: "added", item: items[0].title(), when: at},
+			{what: "Status: In progress", item: items[1].title(), when: at},
+		},
+	}}}
+
+	report := w.projectsReport()
+	for _, want := range []string{
+		"Project: Roadmap (https://github.com/orgs/owner/projects/1)\n",
+		"  2024-03-04 added: owner/repo#7 (https://github.com/owner/repo/issues/7) Fix the thing\n",
+		"  2024-03-04 Status: In progress: draft: Write the blog post\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Prompt Variants
+
+// promptVariant replaces some of the prompts, to compare the summaries they
+// produce with the default ones. Empty prompts are left as they are.
+type promptVariant struct {
+	Description string `yaml:"description,omitempty"`
+	Chunk       string `yaml:"chunk,omitempty"`
+	Action      string `yaml:"action,omitempty"`
+	Timecard    string `yaml:"timecard,omitempty"`
+	Ledger      string `yaml:"ledger,omitempty"`
+}
+
+// useVariant replaces the prompts with the ones of the named variant.
+func useVariant(variants map[string]*promptVariant, name string) error {
+	v, ok := variants[name]
+	if !ok {
+		names := make([]string, 0, len(variants))
+		for n := range variants {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("prompt variant %q not found (configured: %s)", name, strings.Join(names, ", "))
+	}
+
+	for _, p := range []struct {
+		prompt  *string
+		replace string
+	}{
+		{&descriptionSummaryString, v.Description},
+		{&chunkSummaryString, v.Chunk},
+		{&actionSummaryString, v.Action},
+		{&timecardSummaryString, v.Timecard},
+		{&ledgerSummaryString, v.Ledger},
+	} {
+		if p.replace != "" {
+			*p.prompt = p.replace
+		}
+	}
+
+	return nil
+}
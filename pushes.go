@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Pushes
+
+// pushCommit is a commit pushed by me.
+type pushCommit struct {
+	sha     string
+	message string    // first line of the commit message
+	at      time.Time // when it was pushed
+}
+
+// branchPushes are the commits pushed to a branch.
+type branchPushes struct {
+	repo    string // owner/repo (lowercase)
+	branch  string // empty if unknown
+	commits []*pushCommit
+	more    int // commits pushed, but not listed in the events
+}
+
+// addPush adds the commits of a push. Only the distinct commits (new to the
+// repository) count, not the ones already pushed elsewhere, or authored by
+// someone else (the GitHub noreply address of another user).
+func (w *work) addPush(e *github.Event, push *github.PushEvent) {
+	repo := strings.ToLower(e.GetRepo().GetName())
+	branch := strings.TrimPrefix(push.GetRef(), "refs/heads/")
+
+	key := repo + "@" + branch
+	bp, ok := w.pushes[key]
+	if !ok {
+		bp = &branchPushes{repo: repo, branch: branch}
+		w.pushes[key] = bp
+	}
+
+	listed := 0
+	for _, c := range push.Commits {
+		listed++
+		if !c.GetDistinct() || !w.authored(c.GetAuthor().GetEmail()) {
+			continue
+		}
+		message, _, _ := strings.Cut(c.GetMessage(), "\n")
+		bp.commits = append(bp.commits, &pushCommit{sha: c.GetSHA(), message: message, at: e.GetCreatedAt()})
+	}
+
+	// the events list up to 20 commits per push
+	distinct := push.GetDistinctSize()
+	if push.DistinctSize == nil {
+		distinct = push.GetSize()
+	}
+	if distinct > listed {
+		bp.more += distinct - listed
+	}
+}
+
+// correlatePushes moves the commits pushed that belong to my pull requests
+// (pushed to their branches or, looking the commits up, merged by them) to
+// the actions of the pull requests, so they aren't reported twice. Offline
+// (gh is nil), only the branches are matched.
+func (w *work) correlatePushes(ctx context.Context, gh *github.Client) {
+	heads := make(map[string]id) // owner/repo:branch to pull request
+	for id, meta := range w.pulls {
+		if meta.head != "" {
+			heads[meta.head] = id
+		}
+	}
+
+	keys := make([]string, 0, len(w.pushes))
+	for key := range w.pushes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		bp := w.pushes[key]
+		head, isHead := heads[bp.repo+":"+strings.ToLower(bp.branch)]
+		if isHead {
+			bp.more = 0 // the unlisted commits are in the pull request too
+		}
+		var kept []*pushCommit
+		for _, c := range bp.commits {
+			pull, ok := head, isHead
+			if !ok && gh != nil {
+				pull, ok = w.commitPull(ctx, gh, bp.repo, c.sha)
+			}
+			if !ok {
+				kept = append(kept, c)
+				continue
+			}
+			w.actions[pull] = append(w.actions[pull], &action{
+				action:  "pushed",
+				object:  ObjectCommit,
+				content: c.message,
+				updated: c.at,
+				at:      c.at,
+			})
+		}
+		bp.commits = kept
+	}
+}
+
+// commitPull returns the pull request of mine, in the work, with the commit.
+func (w *work) commitPull(ctx context.Context, gh *github.Client, repo, sha string) (id, bool) {
+	owner, name, _ := strings.Cut(repo, "/")
+	pulls, _, err := gh.PullRequests.ListPullRequestsWithCommit(ctx, owner, name, sha, nil)
+	if err != nil {
+		fmt.Printf("Error fetching the pull requests of %s@%s: %v\n", repo, sha, err)
+		return id{}, false
+	}
+	for _, pr := range pulls {
+		base := pr.GetBase().GetRepo().GetFullName()
+		if base == "" {
+			base = repo
+		}
+		pull := newID(base, pr.GetNumber())
+		if _, ok := w.pulls[pull]; ok {
+			return pull, true
+		}
+	}
+	return id{}, false
+}
+
+// authored returns false if the commit email is the GitHub noreply address of
+// another user.
+func (w *work) authored(email string) bool {
+	local, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	if domain != "users.noreply.github.com" {
+		return true
+	}
+	if _, login, ok := strings.Cut(local, "+"); ok {
+		local = login
+	}
+	return strings.EqualFold(local, w.user)
+}
+
+// pushesReport returns the commits pushed, per repository and branch.
+func (w *work) pushesReport() string {
+	if len(w.pushes) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(w.pushes))
+	for key, bp := range w.pushes {
+		if len(bp.commits) > 0 || bp.more > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Commits:"))
+	for _, key := range keys {
+		bp := w.pushes[key]
+		where := bp.repo
+		if bp.branch != "" {
+			where += " (" + bp.branch + ")"
+		}
+		report += fmt.Sprintf("Branch: %s, %d commits\n", where, len(bp.commits)+bp.more)
+		for _, c := range bp.commits {
+			sha := c.sha
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			report += fmt.Sprintf("  %s %s\n", sha, c.message)
+		}
+		if bp.more > 0 {
+			report += fmt.Sprintf("  (and %d more)\n", bp.more)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestAddPush(t *testing.T) {
+	w := &work{user: "me", pushes: make(map[string]*branchPushes)}
+	e := &github.Event{Repo: &github.Repository{Name: github.String("Owner/Repo")}}
+	commit := func(sha, email, message string, distinct bool) *github.HeadCommit {
+		return &github.HeadCommit{
+			SHA:      github.String(sha),
+			Message:  github.String(message),
+			Author:   &github.CommitAuthor{Email: github.String(email)},
+			Distinct: github.Bool(distinct),
+		}
+	}
+
+	w.addPush(e, &github.PushEvent{
+		Ref:          github.String("refs/heads/main"),
+		Size:         github.Int(25),
+		DistinctSize: github.Int(24),
+		Commits: []*github.HeadCommit{
+			commit("0123456789", "me@example.com", "fix: the thing\n\nlong description", true),
+			commit("abcdef0123", "123+Me@users.noreply.github.com", "docs: the thing", true),
+			commit("1111111111", "other@users.noreply.github.com", "someone else's", true),
+			commit("2222222222", "me@example.com", "already pushed", false),
+		},
+	})
+
+	bp := w.pushes["owner/repo@main"]
+	if bp == nil {
+		t.Fatal("no pushes to owner/repo@main")
+	}
+	if len(bp.commits) != 2 {
+		t.Fatalf("got %d commits, want 2", len(bp.commits))
+	}
+	if bp.commits[0].message != "fix: the thing" {
+		t.Errorf("got message %q, want the first line", bp.commits[0].message)
+	}
+	if bp.more != 20 {
+		t.Errorf("got %d more commits, want 20", bp.more)
+	}
+}
+
+func TestCorrelatePushes(t *testing.T) {
+	pull := newID("owner/repo", 7)
+	w := &work{
+		pulls:   map[id]*metadata{pull: {eventId: pull, repo: "owner/repo", head: "me/repo:feature"}},
+		actions: make(map[id][]*action),
+		pushes: map[string]*branchPushes{
+			"me/repo@feature": {repo: "me/repo", branch: "Feature", commits: []*pushCommit{{sha: "0123456789", message: "add the feature"}}, more: 3},
+			"owner/repo@main": {repo: "owner/repo", branch: "main", commits: []*pushCommit{{sha: "abcdef0123", message: "fix the build"}}},
+		},
+	}
+
+	w.correlatePushes(context.Background(), nil)
+
+	if bp := w.pushes["me/repo@feature"]; len(bp.commits) != 0 || bp.more != 0 {
+		t.Errorf("got %d commits (and %d more) on the pull request branch, want none", len(bp.commits), bp.more)
+	}
+	if bp := w.pushes["owner/repo@main"]; len(bp.commits) != 1 {
+		t.Errorf("got %d commits on main, want 1", len(bp.commits))
+	}
+	actions := w.actions[pull]
+	if len(actions) != 1 || actions[0].action != "pushed" || actions[0].object != ObjectCommit || actions[0].content != "add the feature" {
+		t.Errorf("got actions %+v, want the commit pushed", actions)
+	}
+}
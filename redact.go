@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Local Redaction
+
+// redactor strips personal data and secrets, with a local model, before
+// anything is sent to the remote LLM. Methods on a nil redactor do nothing.
+type redactor struct {
+	model string
+	url   string // Ollama server URL
+	http  *http.Client
+}
+
+// redactChunkTokens is how much text is redacted per call, well within the
+// context window of the local model (redactContext).
+const (
+	redactChunkTokens = 1024
+	redactContext     = 4096
+)
+
+// secretPatterns catch the obvious secrets even if the local model misses
+// them.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36,}`),   // GitHub tokens
+	regexp.MustCompile(`github_pat_[A-Za-z0-9_]{22,}`), // GitHub fine-grained tokens
+	regexp.MustCompile(`sk-[A-Za-z0-9_-]{20,}`),        // OpenAI keys
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),             // AWS access keys
+	regexp.MustCompile(`xox[abprs]-[A-Za-z0-9-]{10,}`), // Slack tokens
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// newRedactor returns a redactor using the Ollama model at the server URL.
+func newRedactor(model, serverURL string) *redactor {
+	return &redactor{
+		model: model,
+		url:   strings.TrimSuffix(serverURL, "/"),
+		http:  &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// redact returns the text without personal data and secrets. The text is
+// redacted in chunks that fit the local model context. If the local model
+// fails, or its answer looks truncated, nothing is returned: nothing
+// unredacted, or partially redacted, is sent.
+func (r *redactor) redact(text string) (string, error) {
+	if r == nil {
+		return text, nil
+	}
+
+	for _, p := range secretPatterns {
+		text = p.ReplaceAllString(text, "[SECRET]")
+	}
+	text = emailPattern.ReplaceAllString(text, "[REDACTED]")
+
+	var redacted strings.Builder
+	for _, chunk := range splitChunks(openAIModel, text, redactChunkTokens) {
+		answer, err := r.generate(chunk)
+		if err != nil {
+			return "", err
+		}
+		redacted.WriteString(answer)
+		if !strings.HasSuffix(answer, "\n") && strings.HasSuffix(chunk, "\n") {
+			redacted.WriteString("\n")
+		}
+	}
+
+	return redacted.String(), nil
+}
+
+// generate redacts a single chunk with the local model.
+func (r *redactor) generate(chunk string) (string, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"model":  r.model,
+		"system": redactString,
+		"prompt": chunk,
+		"stream": false,
+		"options": map[string]interface{}{
+			"temperature": 0,
+			"num_ctx":     redactContext,
+			"num_predict": 2 * redactChunkTokens,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.http.Post(r.url+"/api/generate", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ollama: %s", resp.Status)
+	}
+
+	answer := struct {
+		Response   string `json:"response"`
+		Done       bool   `json:"done"`
+		DoneReason string `json:"done_reason"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return "", err
+	}
+
+	// redaction replaces words, it doesn't drop them: a much shorter answer
+	// means the input, or the answer, was cut
+	if !answer.Done || answer.DoneReason == "length" || len(answer.Response) < len(chunk)/2 {
+		return "", fmt.Errorf("ollama: redacted text looks truncated (%d of %d bytes)", len(answer.Response), len(chunk))
+	}
+
+	return answer.Response, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Branches and Tags
+
+type gitRef struct {
+	repo    string   // owner/repo (lowercase)
+	kind    string   // branch, tag or repository
+	name    string   // branch or tag name (empty for repositories)
+	actions []string // created, deleted
+}
+
+// addRef records a branch or tag (or repository) created or deleted.
+func (w *work) addRef(e *github.Event, kind, name, action string) {
+	repo := strings.ToLower(e.GetRepo().GetName())
+	key := repo + ":" + kind + ":" + name
+
+	ref, ok := w.refs[key]
+	if !ok {
+		ref = &gitRef{repo: repo, kind: kind, name: name}
+		w.refs[key] = ref
+		w.refOrder = append(w.refOrder, key)
+	}
+
+	ref.actions = append(ref.actions, action)
+}
+
+// branchPull returns the pull request opened from the branch, if any.
+func (w *work) branchPull(ref *gitRef) *metadata {
+	head := ref.repo + ":" + strings.ToLower(ref.name)
+	for _, id := range w.sortedIds(w.pulls) {
+		if w.pulls[id].head == head {
+			return w.pulls[id]
+		}
+	}
+	return nil
+}
+
+// refsReport returns the branches and tags section of the report, grouped by
+// repository, with the pull requests opened from the branches.
+func (w *work) refsReport() string {
+	if len(w.refs) == 0 {
+		return ""
+	}
+
+	var repos []string
+	byRepo := make(map[string][]*gitRef)
+	for _, key := range w.refOrder {
+		ref := w.refs[key]
+		if _, ok := byRepo[ref.repo]; !ok {
+			repos = append(repos, ref.repo)
+		}
+		byRepo[ref.repo] = append(byRepo[ref.repo], ref)
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Branches and tags:"))
+	for _, repo := range repos {
+		report += fmt.Sprintf("Repository: %s\n", repo)
+		for _, ref := range byRepo[repo] {
+			line := fmt.Sprintf("  %s %s: %s", ref.kind, ref.name, strings.Join(ref.actions, ", "))
+			if ref.kind == "repository" {
+				line = fmt.Sprintf("  repository: %s", strings.Join(ref.actions, ", "))
+			}
+			if ref.kind == "branch" {
+				if pr := w.branchPull(ref); pr != nil {
+					line += fmt.Sprintf(" (PR %s%s %s)", pr.repo, pr.eventId, pr.title)
+				}
+			}
+			report += line + "\n"
+		}
+	}
+
+	return report
+}
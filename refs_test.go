@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestRefsReport(t *testing.T) {
+	w := &work{user: "me", pulls: make(map[id]*metadata), refs: make(map[string]*gitRef)}
+	fork := &github.Event{Repo: &github.Repository{Name: github.String("Me/Repo")}}
+	upstream := &github.Event{Repo: &github.Repository{Name: github.String("owner/repo")}}
+
+	w.addRef(fork, "branch", "Feature-X", "created")
+	w.addPullRequest(upstream, &github.PullRequest{
+		Number: github.Int(12),
+		Title:  github.String("Add feature X"),
+		Head: &github.PullRequestBranch{
+			Ref:  github.String("Feature-X"),
+			Repo: &github.Repository{FullName: github.String("me/repo")},
+		},
+	})
+	w.addRef(fork, "branch", "Feature-X", "deleted")
+	w.addRef(upstream, "tag", "v1.0.0", "created")
+
+	report := w.refsReport()
+	for _, want := range []string{
+		"Repository: me/repo\n  branch Feature-X: created, deleted (PR owner/repo#12 Add feature X)\n",
+		"Repository: owner/repo\n  tag v1.0.0: created\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Releases
+
+type release struct {
+	repo    string   // owner/repo (lowercase)
+	tag     string   // tag name
+	name    string   // release title
+	url     string   // release URL
+	notes   string   // release notes (summarized)
+	actions []string // published, edited, ...
+}
+
+// addRelease records a release cut (published, edited, ...) in a repository,
+// summarizing its notes.
+func (w *work) addRelease(e *github.Event, r *github.RepositoryRelease, action string) {
+	url := r.GetHTMLURL()
+
+	rel, ok := w.releases[url]
+	if !ok {
+		rel = &release{
+			repo:  strings.ToLower(e.GetRepo().GetName()),
+			tag:   r.GetTagName(),
+			name:  r.GetName(),
+			url:   url,
+			notes: r.GetBody(),
+		}
+		w.releases[url] = rel
+		w.releaseOrder = append(w.releaseOrder, url)
+		if strings.TrimSpace(rel.notes) != "" {
+			j := descriptionSummary(e, id{}, r.GetPublishedAt().Time, &rel.notes)
+			j.item = "@" + rel.tag // accounted as owner/repo@tag
+			w.pending = append(w.pending, j)
+		}
+	}
+
+	rel.actions = append(rel.actions, action)
+}
+
+// releasesReport returns the releases section of the report.
+func (w *work) releasesReport() string {
+	if len(w.releases) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Releases:"))
+	for _, url := range w.releaseOrder {
+		rel := w.releases[url]
+		report += fmt.Sprintf("Release: %s %s (%s) %s\n", rel.repo, rel.tag, rel.url, rel.name)
+		report += fmt.Sprintf("Actions: %s\n", strings.Join(rel.actions, ", "))
+		if rel.notes != "" {
+			report += fmt.Sprintf("Notes: %s\n", rel.notes)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestAddRelease(t *testing.T) {
+	w := &work{releases: make(map[string]*release)}
+	e := &github.Event{Repo: &github.Repository{Name: github.String("Owner/Repo")}}
+	r := &github.RepositoryRelease{
+		TagName: github.String("v1.0.0"),
+		Name:    github.String("First release"),
+		HTMLURL: github.String("https://github.com/owner/repo/releases/tag/v1.0.0"),
+		Body:    github.String("## What's changed\n..."),
+	}
+
+	w.addRelease(e, r, "published")
+	w.addRelease(e, r, "edited")
+
+	if len(w.releases) != 1 {
+		t.Fatalf("got %d releases, want 1", len(w.releases))
+	}
+	if len(w.pending) != 1 || w.pending[0].item != "@v1.0.0" {
+		t.Fatalf("got %d jobs, want the release notes summary", len(w.pending))
+	}
+
+	report := w.releasesReport()
+	for _, want := range []string{
+		"Release: owner/repo v1.0.0 (https://github.com/owner/repo/releases/tag/v1.0.0) First release",
+		"Actions: published, edited",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
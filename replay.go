@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Offline Replay
+
+// eventsFile holds the fetched events, and what they were fetched for, so the
+// report can be generated elsewhere (fetch --output, report --from-file).
+type eventsFile struct {
+	User   string          `json:"user"`
+	Date   string          `json:"date"`
+	Begin  time.Time       `json:"begin"`
+	End    time.Time       `json:"end"`
+	Events []*github.Event `json:"events"`
+}
+
+// writeEventsFile saves the fetched events.
+func writeEventsFile(path string, f *eventsFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// readEventsFile loads previously fetched events.
+func readEventsFile(path string) (*eventsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &eventsFile{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.User == "" || f.Begin.IsZero() {
+		return nil, fmt.Errorf("%s: not an events file", path)
+	}
+	return f, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry with Exponential Backoff
+
+// retryTransport retries requests that failed with a transient error (429,
+// 5xx or a network error) with exponential backoff and jitter, honoring the
+// Retry-After header when the server sends one.
+type retryTransport struct {
+	attempts int           // total attempts, including the first one
+	delay    time.Duration // delay before the first retry
+	maxDelay time.Duration // maximum delay between retries
+	base     http.RoundTripper
+}
+
+// retryable returns true if the request may succeed if tried again.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter returns the delay asked by the server, if any.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when), true
+	}
+	return 0, false
+}
+
+// backoff returns the delay before the given retry (starting at 0), with
+// full jitter.
+func (t *retryTransport) backoff(retry int) time.Duration {
+	delay := t.delay << retry
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+
+	attempts := t.attempts
+	if attempts < 1 {
+		attempts = 1 // always make the call
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+
+			delay, ok := retryAfter(resp)
+			if !ok {
+				delay = t.backoff(attempt - 1)
+			}
+			if resp != nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(delay):
+			}
+		}
+
+		resp, err = t.base.RoundTrip(req)
+		if !retryable(resp, err) {
+			break
+		}
+	}
+
+	return resp, err
+}
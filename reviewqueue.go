@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+)
+
+// Review Queue
+
+// queueDay is the size of my review queue at the end of a day, and how it
+// changed that day.
+type queueDay struct {
+	day  time.Time // midnight, in the report timezone
+	size int       // requests pending at the end of the day
+	in   int       // requests received that day
+	out  int       // requests answered, or removed, that day
+}
+
+// left returns when the request left my queue: my review, or its removal
+// (zero if it is still pending).
+func (r *reviewRequest) left() time.Time {
+	switch {
+	case !r.answered.IsZero():
+		return r.answered
+	case r.outcome == "request removed":
+		return r.removed
+	}
+	return time.Time{}
+}
+
+// reviewQueue returns the size of my review queue at the end of each day of
+// the period (up to today), from the review requests received in it.
+func (w *work) reviewQueue(begin, end time.Time) []*queueDay {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var days []*queueDay
+	for day := startOfDay(begin); day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		d := &queueDay{day: day}
+		for _, r := range w.reviewRequests {
+			left := r.left()
+			if !r.requested.Before(day) && r.requested.Before(next) {
+				d.in++
+			}
+			if !left.IsZero() && !left.Before(day) && left.Before(next) {
+				d.out++
+			}
+			if r.requested.Before(next) && (left.IsZero() || !left.Before(next)) {
+				d.size++
+			}
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// keepingUp tells whether the queue shrank, or grew, over the period.
+func keepingUp(days []*queueDay) string {
+	in, out := 0, 0
+	for _, d := range days {
+		in += d.in
+		out += d.out
+	}
+	last := days[len(days)-1].size
+	verdict := "keeping up"
+	if last > 0 && out < in {
+		verdict = "falling behind"
+	}
+	return fmt.Sprintf("%d requested, %d answered or removed, %d pending at the end: %s", in, out, last, verdict)
+}
+
+// reviewQueueReport returns the review queue day by day, as a bar chart.
+func reviewQueueReport(days []*queueDay) string {
+	if len(days) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Review queue:"))
+	for _, d := range days {
+		report += fmt.Sprintf("%s %-20s %2d (+%d -%d)\n", d.day.Format("Mon 2006-01-02"), strings.Repeat("█", min(d.size, 20)), d.size, d.in, d.out)
+	}
+	report += keepingUp(days) + "\n"
+	return report
+}
+
+// reviewQueueHTML returns the review queue day by day as an HTML page.
+func reviewQueueHTML(days []*queueDay) string {
+	largest := 1
+	for _, d := range days {
+		largest = max(largest, d.size)
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Review queue</title>\n")
+	b.WriteString("<style>body{font-family:sans-serif}td{padding:2px 6px}.bar{background:#216e39;height:14px}</style>\n</head>\n<body>\n")
+	b.WriteString("<h1>Review queue</h1>\n<table>\n<tr><th>Day</th><th>Pending</th><th></th><th>Requested</th><th>Done</th></tr>\n")
+	for _, d := range days {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%d</td><td><div class=\"bar\" style=\"width:%dpx\"></div></td><td>+%d</td><td>-%d</td></tr>\n",
+			d.day.Format("Mon 2006-01-02"), d.size, d.size*300/largest, d.in, d.out)
+	}
+	fmt.Fprintf(&b, "</table>\n<p>%s</p>\n</body>\n</html>\n", html.EscapeString(keepingUp(days)))
+	return b.String()
+}
+
+// writeReviewQueueHTML writes the review queue, as an HTML page, to the file.
+func writeReviewQueueHTML(path string, days []*queueDay) error {
+	return os.WriteFile(path, []byte(reviewQueueHTML(days)), 0o644)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewQueue(t *testing.T) {
+	location = time.UTC
+	mon := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	hour := func(days, hours int) time.Time { return mon.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour) }
+	w := &work{reviewRequests: []*reviewRequest{
+		{requested: hour(0, 9), outcome: "approved", answered: hour(0, 15)},
+		{requested: hour(0, 10), outcome: "reviewed", answered: hour(2, 11)},
+		{requested: hour(1, 10), outcome: "request removed", removed: hour(1, 12)},
+		{requested: hour(2, 16), outcome: "pending"},
+	}}
+
+	days := w.reviewQueue(mon, mon.AddDate(0, 0, 3))
+	var got []string
+	for _, d := range days {
+		got = append(got, fmt.Sprintf("%s %d %d %d", d.day.Format("Mon"), d.size, d.in, d.out))
+	}
+	if want := "Mon 1 2 1|Tue 1 1 1|Wed 1 1 1"; strings.Join(got, "|") != want {
+		t.Errorf("got %q, want %q", strings.Join(got, "|"), want)
+	}
+
+	want := `
+Review queue:
+
+Mon 2024-03-04 █                     1 (+2 -1)
+Tue 2024-03-05 █                     1 (+1 -1)
+Wed 2024-03-06 █                     1 (+1 -1)
+4 requested, 3 answered or removed, 1 pending at the end: falling behind
+`
+	if got := reviewQueueReport(days); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if page := reviewQueueHTML(days); !strings.Contains(page, "<td>Wed 2024-03-06</td><td>1</td>") || !strings.Contains(page, "falling behind") {
+		t.Errorf("unexpected page: %s", page)
+	}
+	if got := reviewQueueReport(nil); got != "" {
+		t.Errorf("got %q, want no chart", got)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Review Requests
+
+// reviewRequest is a review of a pull request requested from me.
+type reviewRequest struct {
+	repo      string // owner/repo (lowercase)
+	number    int
+	title     string
+	url       string
+	requester string    // who asked for the review
+	requested time.Time // when
+	outcome   string    // approved, changes requested, reviewed, pending or request removed
+	answered  time.Time // when I reviewed (zero if I didn't)
+	removed   time.Time // when the request was removed (zero if it wasn't)
+}
+
+// collectReviewRequests fetches the pull requests whose review was requested
+// from me between the begin and end dates, with what came of it. Requests I
+// answered are no longer "requested" for the search API, so the pull requests
+// I reviewed are searched too.
+func collectReviewRequests(ctx context.Context, gh *github.Client, w *work, filter *filters, begin, end time.Time) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	updated := begin.UTC().Format("2006-01-02T15:04:05Z") + ".." + end.UTC().Format("2006-01-02T15:04:05Z")
+
+	pending := make(map[string]bool) // URLs of the pull requests still requested from me
+	pulls := make(map[string]*github.Issue)
+	var order []string
+	for _, query := range []string{"review-requested:", "reviewed-by:"} {
+		opt := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			result, resp, err := gh.Search.Issues(ctx, fmt.Sprintf("is:pr %s%s updated:%s", query, w.user, updated), opt)
+			if err != nil {
+				fmt.Println("Error searching review requests:", err)
+				break
+			}
+			for _, pr := range result.Issues {
+				url := pr.GetHTMLURL()
+				if query == "review-requested:" {
+					pending[url] = true
+				}
+				if _, ok := pulls[url]; !ok {
+					pulls[url] = pr
+					order = append(order, url)
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+
+	for _, url := range order {
+		pr := pulls[url]
+		repo := strings.TrimPrefix(pr.GetRepositoryURL(), "https://api.github.com/repos/")
+		e := &github.Event{Repo: &github.Repository{Name: github.String(repo)}}
+		if !filter.allows(ctx, e) {
+			continue
+		}
+		owner, name, _ := strings.Cut(repo, "/")
+
+		requester, requested, removed, err := lastReviewRequest(ctx, gh, owner, name, pr.GetNumber(), w.user)
+		if err != nil {
+			fmt.Printf("Error fetching the timeline of %s#%d: %v\n", repo, pr.GetNumber(), err)
+			continue
+		}
+		if requested.IsZero() || requested.Before(begin) || !requested.Before(end) {
+			continue // not requested in the period (or only from a team)
+		}
+
+		var reviews []*github.PullRequestReview
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			page, resp, err := gh.PullRequests.ListReviews(ctx, owner, name, pr.GetNumber(), opt)
+			if err != nil {
+				fmt.Printf("Error fetching reviews of %s#%d: %v\n", repo, pr.GetNumber(), err)
+				break
+			}
+			reviews = append(reviews, page...)
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+
+		outcome, answered := reviewOutcome(requested, reviews, w.user, pending[url])
+		w.reviewRequests = append(w.reviewRequests, &reviewRequest{
+			repo:      strings.ToLower(repo),
+			number:    pr.GetNumber(),
+			title:     pr.GetTitle(),
+			url:       url,
+			requester: requester,
+			requested: requested,
+			outcome:   outcome,
+			answered:  answered,
+			removed:   removed,
+		})
+	}
+
+	sort.SliceStable(w.reviewRequests, func(i, j int) bool {
+		return w.reviewRequests[i].requested.Before(w.reviewRequests[j].requested)
+	})
+}
+
+// lastReviewRequest returns who requested a review from the user last, when,
+// and when the request was removed (zero if it wasn't), from the timeline of
+// the pull request.
+func lastReviewRequest(ctx context.Context, gh *github.Client, owner, name string, number int, user string) (string, time.Time, time.Time, error) {
+	var requester string
+	var requested, removed time.Time
+
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		timeline, resp, err := gh.Issues.ListIssueTimeline(ctx, owner, name, number, opt)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, err
+		}
+		for _, t := range timeline {
+			if !strings.EqualFold(t.GetReviewer().GetLogin(), user) {
+				continue
+			}
+			switch t.GetEvent() {
+			case "review_requested":
+				requester, requested, removed = t.GetRequester().GetLogin(), t.GetCreatedAt(), time.Time{}
+			case "review_request_removed":
+				removed = t.GetCreatedAt()
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return requester, requested, removed, nil
+}
+
+// reviewOutcome returns what came of a review request: the state of my last
+// review after the request, or whether it is still pending.
+func reviewOutcome(requested time.Time, reviews []*github.PullRequestReview, user string, pending bool) (string, time.Time) {
+	var last *github.PullRequestReview
+	for _, r := range reviews {
+		if !strings.EqualFold(r.GetUser().GetLogin(), user) || r.GetSubmittedAt().Before(requested) {
+			continue
+		}
+		if last == nil || r.GetSubmittedAt().After(last.GetSubmittedAt()) {
+			last = r
+		}
+	}
+
+	switch {
+	case last == nil && pending:
+		return "pending", time.Time{}
+	case last == nil:
+		return "request removed", time.Time{}
+	case last.GetState() == "APPROVED":
+		return "approved", last.GetSubmittedAt()
+	case last.GetState() == "CHANGES_REQUESTED":
+		return "changes requested", last.GetSubmittedAt()
+	default:
+		return "reviewed", last.GetSubmittedAt()
+	}
+}
+
+// reviewRequestsReport returns the review requests received, with their
+// outcomes, and how many there were of each.
+func (w *work) reviewRequestsReport() string {
+	if len(w.reviewRequests) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	report := fmt.Sprintf("\n%s\n\n", msg("Review requests:"))
+	for _, r := range w.reviewRequests {
+		counts[r.outcome]++
+		line := fmt.Sprintf("%s#%d (%s) %s: requested %s", r.repo, r.number, r.url, r.title, r.requested.In(location).Format("2006-01-02"))
+		if r.requester != "" {
+			line += " by " + r.requester
+		}
+		line += ", " + r.outcome
+		if !r.answered.IsZero() {
+			line += fmt.Sprintf(" %s (after %s)", r.answered.In(location).Format("2006-01-02"), formatSpan(r.answered.Sub(r.requested)))
+		}
+		report += line + "\n"
+	}
+
+	var outcomes []string
+	for _, outcome := range []string{"approved", "changes requested", "reviewed", "pending", "request removed"} {
+		if counts[outcome] > 0 {
+			outcomes = append(outcomes, fmt.Sprintf("%s: %d", outcome, counts[outcome]))
+		}
+	}
+	report += fmt.Sprintf("Requested: %d (%s)\n", len(w.reviewRequests), strings.Join(outcomes, ", "))
+
+	return report
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestReviewOutcome(t *testing.T) {
+	requested := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	review := func(login, state string, at time.Time) *github.PullRequestReview {
+		return &github.PullRequestReview{User: &github.User{Login: github.String(login)}, State: github.String(state), SubmittedAt: &at}
+	}
+	reviews := []*github.PullRequestReview{
+		review("me", "APPROVED", requested.Add(-time.Hour)), // before the request
+		review("other", "APPROVED", requested.Add(time.Hour)),
+		review("Me", "COMMENTED", requested.Add(2*time.Hour)),
+		review("me", "CHANGES_REQUESTED", requested.Add(3*time.Hour)),
+	}
+
+	if outcome, at := reviewOutcome(requested, reviews, "me", false); outcome != "changes requested" || !at.Equal(requested.Add(3*time.Hour)) {
+		t.Errorf("got %s at %v, want changes requested 3h after the request", outcome, at)
+	}
+	if outcome, _ := reviewOutcome(requested, reviews[:2], "me", true); outcome != "pending" {
+		t.Errorf("got %s, want pending", outcome)
+	}
+	if outcome, _ := reviewOutcome(requested, nil, "me", false); outcome != "request removed" {
+		t.Errorf("got %s, want request removed", outcome)
+	}
+}
+
+func TestReviewRequestsReport(t *testing.T) {
+	location = time.UTC
+	requested := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	w := &work{reviewRequests: []*reviewRequest{
+		{repo: "owner/repo", number: 1, title: "Fix", url: "https://github.com/owner/repo/pull/1", requester: "alice",
+			requested: requested, outcome: "approved", answered: requested.Add(26 * time.Hour)},
+		{repo: "owner/repo", number: 2, title: "Feature", url: "https://github.com/owner/repo/pull/2",
+			requested: requested, outcome: "pending"},
+	}}
+
+	report := w.reviewRequestsReport()
+	for _, want := range []string{
+		"owner/repo#1 (https://github.com/owner/repo/pull/1) Fix: requested 2024-03-04 by alice, approved 2024-03-05 (after 1d2h)\n",
+		"owner/repo#2 (https://github.com/owner/repo/pull/2) Feature: requested 2024-03-04, pending\n",
+		"Requested: 2 (approved: 1, pending: 1)\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Resumable Runs
+
+// runState is what is needed to pick up an interrupted run: its arguments,
+// the fetched events and (in the summaries directory) the summaries already
+// done.
+type runState struct {
+	ID      string    `json:"id"`
+	Date    string    `json:"date"`  // date argument (today, this-week, ...)
+	Begin   time.Time `json:"begin"` // begin date, as computed by the first attempt
+	End     time.Time `json:"end"`   // end date (zero: no end)
+	Type    string    `json:"type"`  // summary type
+	Repos   []string  `json:"repos"` // wanted repositories
+	Orgs    []string  `json:"orgs"`  // wanted organizations
+	Started time.Time `json:"started"`
+	Fetched bool      `json:"fetched"`
+
+	SinceLastRun bool `json:"since_last_run"` // record the run as the last one
+
+	dir string
+}
+
+// runsDir returns the directory holding the runs.
+func runsDir() string {
+	return filepath.Join(configDir(), "runs")
+}
+
+// newRun creates the directory of a new run.
+func newRun(date string, begin, end time.Time, summaryType string, repos, orgs []string) (*runState, error) {
+	now := time.Now()
+	id := now.Format("20060102-150405")
+	run := &runState{
+		ID:      id,
+		Started: now,
+		Date:    date,
+		Begin:   begin,
+		End:     end,
+		Type:    summaryType,
+		Repos:   repos,
+		Orgs:    orgs,
+		dir:     filepath.Join(runsDir(), id),
+	}
+	return run, run.save()
+}
+
+// loadRun reads the state of a previous run.
+func loadRun(id string) (*runState, error) {
+	dir := filepath.Join(runsDir(), id)
+	data, err := os.ReadFile(filepath.Join(dir, "run.json"))
+	if err != nil {
+		return nil, fmt.Errorf("run %s: %w", id, err)
+	}
+	run := &runState{dir: dir}
+	if err := json.Unmarshal(data, run); err != nil {
+		return nil, fmt.Errorf("run %s: %w", id, err)
+	}
+	return run, nil
+}
+
+// save writes the run state.
+func (r *runState) save() error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(r.dir, "run.json"), data)
+}
+
+// summariesDir returns the directory of the summaries done in the run.
+func (r *runState) summariesDir() string {
+	return filepath.Join(r.dir, "summaries")
+}
+
+// saveEvents stores the fetched events, so they aren't fetched again.
+func (r *runState) saveEvents(events []*github.Event) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(r.dir, "events.json"), data); err != nil {
+		return err
+	}
+	r.Fetched = true
+	return r.save()
+}
+
+// loadEvents returns the events fetched by the run.
+func (r *runState) loadEvents() ([]*github.Event, error) {
+	data, err := os.ReadFile(filepath.Join(r.dir, "events.json"))
+	if err != nil {
+		return nil, err
+	}
+	var events []*github.Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// remove deletes the run, once it finished (or wasn't needed).
+func (r *runState) remove() {
+	if r == nil {
+		return
+	}
+	os.RemoveAll(r.dir)
+}
+
+// finish removes the run, once it succeeded, and records it as the last run
+// if asked to.
+func (r *runState) finish() {
+	if r == nil {
+		return
+	}
+	if r.SinceLastRun {
+		if err := saveLastRun(r.Started); err != nil {
+			fmt.Println("Error saving last run:", err)
+		}
+	}
+	r.remove()
+}
+
+// lastRunPath returns the file keeping when the last run started.
+func lastRunPath() string {
+	return filepath.Join(configDir(), "last-run")
+}
+
+// loadLastRun returns when the last run started. Without a last run, the
+// first one covers today.
+func loadLastRun() (time.Time, error) {
+	data, err := os.ReadFile(lastRunPath())
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No previous run, reporting today.")
+		return startOfDay(time.Now()), nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+}
+
+// saveLastRun records when the last run started: the events after that are
+// left for the next run.
+func saveLastRun(t time.Time) error {
+	return writeFileAtomic(lastRunPath(), []byte(t.Format(time.RFC3339)+"\n"))
+}
+
+// activeRun is the run in progress, if any.
+var activeRun *runState
+
+// exit ends the program telling how to resume the active run (and, unless
+// interrupted, notifying the failure).
+func exit(code int) {
+	if activeRun != nil {
+		fmt.Printf("Run %s stopped, continue it with: --resume %s\n", activeRun.ID, activeRun.ID)
+	}
+	if code == 130 {
+		runUsage.record("interrupted")
+	} else {
+		runUsage.record("failed")
+		notify("The run failed")
+	}
+	os.Exit(code)
+}
+
+// exitOnInterrupt exits, telling how to resume the active run, on Ctrl-C.
+func exitOnInterrupt() {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		fmt.Println()
+		exit(130)
+	}()
+}
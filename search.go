@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Search API Fallback
+
+// searchEvents reconstructs the activity of the user, between the begin and
+// end dates, from the search API: the events API only keeps the last 90 days
+// (and 300 events). Issues and pull requests the user was involved in give
+// the opened, closed and merged events, their comments and reviews give the
+// rest. The events are returned newest first, like the events API does.
+func searchEvents(ctx context.Context, gh *github.Client, user string, begin, end time.Time) ([]*github.Event, error) {
+	query := fmt.Sprintf("involves:%s updated:%s..%s", user,
+		begin.UTC().Format("2006-01-02T15:04:05Z"), end.UTC().Format("2006-01-02T15:04:05Z"))
+
+	// repository visibility, fetched once per repository
+	visibility := make(map[string]bool)
+	isPublic := func(repo string) (bool, error) {
+		if public, ok := visibility[repo]; ok {
+			return public, nil
+		}
+		owner, name, _ := strings.Cut(repo, "/")
+		r, _, err := gh.Repositories.Get(ctx, owner, name)
+		if err != nil {
+			return false, err
+		}
+		visibility[repo] = !r.GetPrivate()
+		return visibility[repo], nil
+	}
+
+	var events []*github.Event
+	add := func(kind, repo string, number int, at time.Time, payload interface{}) error {
+		if at.Before(begin) || !at.Before(end) {
+			return nil
+		}
+		public, err := isPublic(repo)
+		if err != nil {
+			return err
+		}
+		e, err := syntheticEvent(kind, repo, user, public, number, at, payload)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+		return nil
+	}
+
+	opt := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := gh.Search.Issues(ctx, query, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range result.Issues {
+			if err := searchItemEvents(ctx, gh, user, begin, issue, add); err != nil {
+				return nil, err
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetCreatedAt().After(events[j].GetCreatedAt())
+	})
+
+	return events, nil
+}
+
+// searchItemEvents adds the events of the user on a single issue, or pull
+// request, found by the search.
+func searchItemEvents(ctx context.Context, gh *github.Client, user string, begin time.Time, issue *github.Issue,
+	add func(kind, repo string, number int, at time.Time, payload interface{}) error) error {
+
+	repo := strings.TrimPrefix(issue.GetRepositoryURL(), "https://api.github.com/repos/")
+	owner, name, _ := strings.Cut(repo, "/")
+	number := issue.GetNumber()
+	author := strings.EqualFold(issue.GetUser().GetLogin(), user)
+
+	if issue.IsPullRequest() {
+		pr, _, err := gh.PullRequests.Get(ctx, owner, name, number)
+		if err != nil {
+			return err
+		}
+		if author {
+			err := add("PullRequestEvent", repo, number, pr.GetCreatedAt(),
+				&github.PullRequestEvent{Action: github.String("opened"), Number: &number, PullRequest: pr})
+			if err != nil {
+				return err
+			}
+		}
+		if pr.GetMerged() && (author || strings.EqualFold(pr.GetMergedBy().GetLogin(), user)) {
+			err := add("PullRequestEvent", repo, number, pr.GetMergedAt(),
+				&github.PullRequestEvent{Action: github.String("closed"), Number: &number, PullRequest: pr})
+			if err != nil {
+				return err
+			}
+		}
+
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			reviews, resp, err := gh.PullRequests.ListReviews(ctx, owner, name, number, opt)
+			if err != nil {
+				return err
+			}
+			for _, review := range reviews {
+				if strings.EqualFold(review.GetUser().GetLogin(), user) {
+					err := add("PullRequestReviewEvent", repo, number, review.GetSubmittedAt(),
+						&github.PullRequestReviewEvent{Action: github.String("created"), Review: review, PullRequest: pr})
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	} else if author {
+		err := add("IssuesEvent", repo, number, issue.GetCreatedAt(),
+			&github.IssuesEvent{Action: github.String("opened"), Issue: issue})
+		if err != nil {
+			return err
+		}
+		if issue.GetState() == "closed" {
+			err := add("IssuesEvent", repo, number, issue.GetClosedAt(),
+				&github.IssuesEvent{Action: github.String("closed"), Issue: issue})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	opt := &github.IssueListCommentsOptions{
+		Since:       &begin,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		comments, resp, err := gh.Issues.ListComments(ctx, owner, name, number, opt)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if strings.EqualFold(comment.GetUser().GetLogin(), user) {
+				err := add("IssueCommentEvent", repo, number, comment.GetCreatedAt(),
+					&github.IssueCommentEvent{Action: github.String("created"), Issue: issue, Comment: comment})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return nil
+}
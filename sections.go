@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Section Regeneration
+
+// sectionHeading matches a markdown heading, or a line in bold, that starts a
+// section of the timecard.
+var sectionHeading = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$|^\*\*(.+?)\*\*:?$`)
+
+// headingLevel returns the level of the heading line (bold lines are below all
+// the headings), or 0 if it isn't one.
+func headingLevel(line string) (int, string) {
+	m := sectionHeading.FindStringSubmatch(strings.TrimSpace(line))
+	switch {
+	case m == nil:
+		return 0, ""
+	case m[1] != "":
+		return len(m[1]), m[2]
+	default:
+		return 7, m[3]
+	}
+}
+
+// findSection returns the lines of the section of the timecard whose heading
+// has the name (case insensitive, e.g. technical, or owner/repo): from its
+// heading to the next heading of the same or higher level.
+func findSection(timecard, name string) (begin, end int, err error) {
+	lines := strings.Split(timecard, "\n")
+	var found []string
+	begin, end = -1, len(lines)
+	level := 0
+	for i, line := range lines {
+		l, title := headingLevel(line)
+		if l == 0 {
+			continue
+		}
+		if begin >= 0 && end == len(lines) && l <= level {
+			end = i
+		}
+		if strings.Contains(strings.ToLower(title), strings.ToLower(name)) {
+			found = append(found, title)
+			if begin < 0 {
+				begin, level = i, l
+			}
+		}
+	}
+
+	switch {
+	case len(found) == 0:
+		return 0, 0, fmt.Errorf("no %q section in the timecard", name)
+	case len(found) > 1:
+		return 0, 0, fmt.Errorf("several %q sections in the timecard: %s", name, strings.Join(found, "; "))
+	}
+	for end > begin+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end-- // the blank lines before the next section stay
+	}
+	return begin, end, nil
+}
+
+// replaceSection returns the timecard with the section (its lines) replaced.
+func replaceSection(timecard string, begin, end int, section string) string {
+	lines := strings.Split(timecard, "\n")
+	return strings.Join(append(append(lines[:begin:begin], strings.Split(strings.TrimSpace(section), "\n")...), lines[end:]...), "\n")
+}
+
+// regenerateSection rewrites only the section of the timecard, from the
+// report, with the prompts of the timecard, and returns the timecard with it.
+func regenerateSection(role, report, timecard, name string) (string, error) {
+	begin, end, err := findSection(timecard, name)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(timecard, "\n")
+	heading := strings.TrimSpace(lines[begin])
+
+	role += fmt.Sprintf(sectionSummaryString, heading, timecard)
+	section, err := summarize("timecard", role, report, maxTimecardTokens)
+	if err != nil {
+		return "", err
+	}
+	return replaceSection(timecard, begin, end, section), nil
+}
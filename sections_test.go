@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	timecard := `## Executive summary
+
+Fixed the crash on start.
+
+## 🛠 Technical summary
+
+**influxdata/influxdb**
+- Fixed the crash (#2)
+
+**influxdata/telegraf**
+- Reviewed the docs (#3)
+
+## Next steps
+
+- Release`
+
+	for _, c := range []struct {
+		name       string
+		begin, end int
+		err        string
+	}{
+		{"technical", 4, 11, ""},
+		{"INFLUXDATA/INFLUXDB", 6, 8, ""},
+		{"influxdata/telegraf", 9, 11, ""},
+		{"next steps", 12, 15, ""},
+		{"influxdata", 0, 0, "several"},
+		{"security", 0, 0, "no"},
+	} {
+		begin, end, err := findSection(timecard, c.name)
+		if c.err != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+				t.Errorf("%s: got %v, want a %q error", c.name, err, c.err)
+			}
+			continue
+		}
+		if err != nil || begin != c.begin || end != c.end {
+			t.Errorf("%s: got %d-%d (%v), want %d-%d", c.name, begin, end, err, c.begin, c.end)
+		}
+	}
+
+	begin, end, _ := findSection(timecard, "influxdata/influxdb")
+	want := strings.Replace(timecard, "- Fixed the crash (#2)", "- Fixed the crash on start (#2)\n- Added a regression test", 1)
+	if got := replaceSection(timecard, begin, end, "**influxdata/influxdb**\n- Fixed the crash on start (#2)\n- Added a regression test\n"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Security
+
+// securityItem is something I did about the security of a repository: an
+// alert triaged (dismissed, resolved) or an advisory drafted or published.
+type securityItem struct {
+	repo     string // owner/repo (lowercase)
+	kind     string // Dependabot alert, code scanning alert or advisory
+	action   string // created, dismissed, resolved, drafted, published
+	title    string // package, rule or advisory summary
+	severity string
+	reason   string // why it was dismissed
+	url      string
+	at       time.Time
+}
+
+// dependabotAlert is a Dependabot alert (go-github doesn't have them).
+type dependabotAlert struct {
+	HTMLURL          string `json:"html_url"`
+	SecurityAdvisory struct {
+		GHSAID   string `json:"ghsa_id"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"security_advisory"`
+	Dependency struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+	} `json:"dependency"`
+	DismissedBy     *github.User `json:"dismissed_by"`
+	DismissedAt     *time.Time   `json:"dismissed_at"`
+	DismissedReason string       `json:"dismissed_reason"`
+}
+
+// repositoryAdvisory is a security advisory of a repository (go-github
+// doesn't have them).
+type repositoryAdvisory struct {
+	GHSAID      string       `json:"ghsa_id"`
+	Summary     string       `json:"summary"`
+	Severity    string       `json:"severity"`
+	HTMLURL     string       `json:"html_url"`
+	Author      *github.User `json:"author"`
+	Publisher   *github.User `json:"publisher"`
+	CreatedAt   time.Time    `json:"created_at"`
+	PublishedAt *time.Time   `json:"published_at"`
+}
+
+// addSecurityAlert adds a vulnerability alert event (created, dismissed or
+// resolved).
+func (w *work) addSecurityAlert(e *github.Event, v *github.RepositoryVulnerabilityAlertEvent) {
+	alert := v.GetAlert()
+	action := map[string]string{"create": "created", "dismiss": "dismissed", "resolve": "resolved"}[v.GetAction()]
+	if action == "" {
+		action = v.GetAction()
+	}
+	w.addSecurityItem(&securityItem{
+		repo:     strings.ToLower(e.GetRepo().GetName()),
+		kind:     "Dependabot alert",
+		action:   action,
+		title:    strings.TrimSpace(alert.GetAffectedPackageName() + " " + alert.GetGitHubSecurityAdvisoryID()),
+		severity: alert.GetSeverity(),
+		reason:   alert.GetDismissReason(),
+		url:      alert.GetExternalReference(),
+		at:       e.GetCreatedAt(),
+	})
+}
+
+// addSecurityItem adds the item, unless it is already there (from the events
+// and the API both).
+func (w *work) addSecurityItem(item *securityItem) {
+	for _, other := range w.security {
+		if other.repo == item.repo && other.kind == item.kind && other.action == item.action && other.title == item.title {
+			return
+		}
+	}
+	w.security = append(w.security, item)
+}
+
+// collectSecurity fetches, for the repositories I was active in, the alerts
+// (Dependabot and code scanning) I dismissed between the begin and end dates,
+// and the security advisories I drafted or published. Repositories without
+// the feature, or access to it, are skipped.
+func collectSecurity(ctx context.Context, gh *github.Client, w *work, begin, end time.Time) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	inPeriod := func(at *time.Time) bool {
+		return at != nil && !at.Before(begin) && at.Before(end)
+	}
+	mine := func(u *github.User) bool {
+		return strings.EqualFold(u.GetLogin(), w.user)
+	}
+
+	for _, repo := range w.activeRepos() {
+		owner, name, _ := strings.Cut(repo, "/")
+
+		var alerts []*dependabotAlert
+		path := fmt.Sprintf("repos/%s/%s/dependabot/alerts?state=dismissed&sort=updated&per_page=100", owner, name)
+		if err := getAll(ctx, gh, path, &alerts); err != nil {
+			if !noAccess(err) {
+				fmt.Printf("Error fetching the Dependabot alerts of %s: %v\n", repo, err)
+			}
+		}
+		for _, a := range alerts {
+			if !mine(a.DismissedBy) || !inPeriod(a.DismissedAt) {
+				continue
+			}
+			w.addSecurityItem(&securityItem{
+				repo:     repo,
+				kind:     "Dependabot alert",
+				action:   "dismissed",
+				title:    strings.TrimSpace(a.Dependency.Package.Name + " " + a.SecurityAdvisory.GHSAID),
+				severity: a.SecurityAdvisory.Severity,
+				reason:   a.DismissedReason,
+				url:      a.HTMLURL,
+				at:       *a.DismissedAt,
+			})
+		}
+
+		opt := &github.AlertListOptions{State: "dismissed", ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			page, resp, err := gh.CodeScanning.ListAlertsForRepo(ctx, owner, name, opt)
+			if err != nil {
+				if !noAccess(err) {
+					fmt.Printf("Error fetching the code scanning alerts of %s: %v\n", repo, err)
+				}
+				break
+			}
+			for _, a := range page {
+				at := a.GetDismissedAt().Time
+				if !mine(a.DismissedBy) || !inPeriod(&at) {
+					continue
+				}
+				w.addSecurityItem(&securityItem{
+					repo:     repo,
+					kind:     "code scanning alert",
+					action:   "dismissed",
+					title:    a.GetRuleDescription(),
+					severity: a.GetRuleSeverity(),
+					reason:   a.GetDismissedReason(),
+					url:      a.GetHTMLURL(),
+					at:       at,
+				})
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+
+		var advisories []*repositoryAdvisory
+		path = fmt.Sprintf("repos/%s/%s/security-advisories?per_page=100", owner, name)
+		if err := getAll(ctx, gh, path, &advisories); err != nil {
+			if !noAccess(err) {
+				fmt.Printf("Error fetching the security advisories of %s: %v\n", repo, err)
+			}
+		}
+		for _, a := range advisories {
+			item := &securityItem{repo: repo, kind: "advisory", title: strings.TrimSpace(a.GHSAID + " " + a.Summary), severity: a.Severity, url: a.HTMLURL}
+			if mine(a.Publisher) && inPeriod(a.PublishedAt) {
+				published := *item
+				published.action, published.at = "published", *a.PublishedAt
+				w.addSecurityItem(&published)
+			}
+			if mine(a.Author) && inPeriod(&a.CreatedAt) {
+				item.action, item.at = "drafted", a.CreatedAt
+				w.addSecurityItem(item)
+			}
+		}
+	}
+
+	sort.SliceStable(w.security, func(i, j int) bool {
+		return w.security[i].at.Before(w.security[j].at)
+	})
+}
+
+// getAll fetches all the pages of a list the API returns as an array.
+func getAll[T any](ctx context.Context, gh *github.Client, path string, all *[]T) error {
+	for page := 1; page > 0; {
+		req, err := gh.NewRequest("GET", fmt.Sprintf("%s&page=%d", path, page), nil)
+		if err != nil {
+			return err
+		}
+		var items []T
+		resp, err := gh.Do(ctx, req, &items)
+		if err != nil {
+			return err
+		}
+		*all = append(*all, items...)
+		page = resp.NextPage
+	}
+	return nil
+}
+
+// noAccess returns true for the errors of a feature disabled, or not
+// accessible, in the repository.
+func noAccess(err error) bool {
+	var resp *github.ErrorResponse
+	if !errors.As(err, &resp) || resp.Response == nil {
+		return false
+	}
+	switch resp.Response.StatusCode {
+	case http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// securityReport returns the security section of the report.
+func (w *work) securityReport() string {
+	if len(w.security) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Security:"))
+	for _, s := range w.security {
+		line := fmt.Sprintf("%s %s: %s %s %s", s.at.In(location).Format("2006-01-02"), s.repo, s.kind, s.action, s.title)
+		if s.severity != "" {
+			line += fmt.Sprintf(" (%s)", strings.ToLower(s.severity))
+		}
+		if s.reason != "" {
+			line += ", " + s.reason
+		}
+		if s.url != "" {
+			line += fmt.Sprintf(" (%s)", s.url)
+		}
+		report += line + "\n"
+	}
+
+	return report
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestSecurityAlertEvent(t *testing.T) {
+	location = time.UTC
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	w := &work{}
+	e := &github.Event{Repo: &github.Repository{Name: github.String("Owner/Repo")}, CreatedAt: &at}
+	event := &github.RepositoryVulnerabilityAlertEvent{
+		Action: github.String("dismiss"),
+		Alert: &github.RepositoryVulnerabilityAlert{
+			AffectedPackageName:      github.String("golang.org/x/net"),
+			GitHubSecurityAdvisoryID: github.String("GHSA-xxxx"),
+			Severity:                 github.String("HIGH"),
+			DismissReason:            github.String("tolerable_risk"),
+		},
+	}
+
+	w.addSecurityAlert(e, event)
+	w.addSecurityAlert(e, event) // the same, from the API
+
+	if len(w.security) != 1 {
+		t.Fatalf("got %d security items, want 1", len(w.security))
+	}
+	want := "2024-03-04 owner/repo: Dependabot alert dismissed golang.org/x/net GHSA-xxxx (high), tolerable_risk\n"
+	if report := w.securityReport(); !strings.Contains(report, want) {
+		t.Errorf("report %q doesn't contain %q", report, want)
+	}
+}
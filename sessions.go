@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Work Sessions
+
+// minSession is the shortest session exported: a single action took some
+// time too.
+const minSession = 15 * time.Minute
+
+// session is a stretch of activity without gaps longer than the session gap.
+type session struct {
+	start time.Time
+	end   time.Time
+	items []string // owner/repo#number (title), or owner/repo@branch, in order
+	repo  string   // owner/repo most of the activity was in
+}
+
+// sessions clusters the actions on the issues and pull requests, and the
+// commits pushed, into work sessions: a gap longer than the given one starts
+// a new session.
+func (w *work) sessions(gap time.Duration) []*session {
+	type activity struct {
+		at   time.Time
+		item string
+		repo string
+	}
+	var all []activity
+	for id, actions := range w.actions {
+		meta := w.getIssueOrPR(id)
+		if meta == nil {
+			continue
+		}
+		for _, a := range actions {
+			if !a.at.IsZero() {
+				all = append(all, activity{a.at, fmt.Sprintf("%s%s (%s)", meta.repo, id, meta.title), meta.repo})
+			}
+		}
+	}
+	for _, bp := range w.pushes {
+		for _, c := range bp.commits {
+			all = append(all, activity{c.at, bp.repo + "@" + bp.branch, bp.repo})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if !all[i].at.Equal(all[j].at) {
+			return all[i].at.Before(all[j].at)
+		}
+		return all[i].item < all[j].item
+	})
+
+	var sessions []*session
+	var current *session
+	seen := make(map[string]bool)
+	repos := make(map[string]int) // actions per repository, in the session
+	for _, a := range all {
+		if current == nil || a.at.Sub(current.end) > gap {
+			current = &session{start: a.at}
+			sessions = append(sessions, current)
+			seen = make(map[string]bool)
+			repos = make(map[string]int)
+		}
+		current.end = a.at
+		repos[a.repo]++
+		if n := repos[a.repo]; n > repos[current.repo] || n == repos[current.repo] && a.repo < current.repo {
+			current.repo = a.repo
+		}
+		if !seen[a.item] {
+			seen[a.item] = true
+			current.items = append(current.items, a.item)
+		}
+	}
+	for _, s := range sessions {
+		if s.end.Sub(s.start) < minSession {
+			s.end = s.start.Add(minSession)
+		}
+	}
+	return sessions
+}
+
+// icsEscape escapes the text of an iCalendar property value.
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// icsLine returns the content line folded at 75 octets, ended in CRLF.
+func icsLine(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 { // don't split a UTF-8 character
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // after the leading space
+	}
+	b.WriteString(line + "\r\n")
+	return b.String()
+}
+
+// sessionsICS returns the sessions as an iCalendar file, an event per session.
+func sessionsICS(sessions []*session, user string, now time.Time) string {
+	const stamp = "20060102T150405Z"
+
+	var b strings.Builder
+	b.WriteString(icsLine("BEGIN:VCALENDAR"))
+	b.WriteString(icsLine("VERSION:2.0"))
+	b.WriteString(icsLine("PRODID:-//ghtimecardator//work sessions//EN"))
+	for _, s := range sessions {
+		summary := fmt.Sprintf("GitHub: %d items", len(s.items))
+		if len(s.items) == 1 {
+			summary = "GitHub: " + s.items[0]
+		}
+		b.WriteString(icsLine("BEGIN:VEVENT"))
+		b.WriteString(icsLine(fmt.Sprintf("UID:%s-%s@ghtimecardator", s.start.UTC().Format(stamp), user)))
+		b.WriteString(icsLine("DTSTAMP:" + now.UTC().Format(stamp)))
+		b.WriteString(icsLine("DTSTART:" + s.start.UTC().Format(stamp)))
+		b.WriteString(icsLine("DTEND:" + s.end.UTC().Format(stamp)))
+		b.WriteString(icsLine("SUMMARY:" + icsEscape(summary)))
+		b.WriteString(icsLine("DESCRIPTION:" + icsEscape(strings.Join(s.items, "\n"))))
+		b.WriteString(icsLine("TRANSP:TRANSPARENT"))
+		b.WriteString(icsLine("END:VEVENT"))
+	}
+	b.WriteString(icsLine("END:VCALENDAR"))
+	return b.String()
+}
+
+// writeSessionsICS writes the work sessions, as an iCalendar file, to the
+// file.
+func (w *work) writeSessionsICS(path string, gap time.Duration) (int, error) {
+	sessions := w.sessions(gap)
+	return len(sessions), os.WriteFile(path, []byte(sessionsICS(sessions, w.user, time.Now())), 0o644)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	at := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	pull, issue := newID("owner/repo", 1), newID("owner/repo", 2)
+	w := &work{
+		user:   "me",
+		issues: map[id]*metadata{issue: {repo: "owner/repo", title: "Crash"}},
+		pulls:  map[id]*metadata{pull: {repo: "owner/repo", title: "Fix, at last"}},
+		actions: map[id][]*action{
+			pull:  {{at: at}, {at: at.Add(40 * time.Minute)}, {at: at.Add(5 * time.Hour)}},
+			issue: {{at: at.Add(20 * time.Minute)}},
+		},
+		pushes: map[string]*branchPushes{"owner/repo@fix": {repo: "owner/repo", branch: "fix", commits: []*pushCommit{{at: at.Add(90 * time.Minute)}}}},
+	}
+
+	sessions := w.sessions(time.Hour)
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	if s := sessions[0]; !s.end.Equal(at.Add(90*time.Minute)) || strings.Join(s.items, "|") != "owner/repo#1 (Fix, at last)|owner/repo#2 (Crash)|owner/repo@fix" {
+		t.Errorf("got session %v-%v %q", s.start, s.end, s.items)
+	}
+	if s := sessions[1]; !s.end.Equal(s.start.Add(minSession)) {
+		t.Errorf("got a %v session, want the minimum", s.end.Sub(s.start))
+	}
+
+	ics := sessionsICS(sessions, w.user, at)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"DTSTART:20240304T090000Z\r\nDTEND:20240304T103000Z\r\nSUMMARY:GitHub: 3 items\r\n",
+		"DESCRIPTION:owner/repo#1 (Fix\\, at last)\\nowner/repo#2 (Crash)\\nowner/repo@",
+		"SUMMARY:GitHub: owner/repo#1 (Fix\\, at last)\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(strings.ReplaceAll(ics, "\r\n ", ""), want) {
+			t.Errorf("no %q in:\n%s", want, ics)
+		}
+	}
+	folded := icsLine("DESCRIPTION:" + strings.Repeat("x", 200))
+	if strings.ReplaceAll(folded, "\r\n ", "") != "DESCRIPTION:"+strings.Repeat("x", 200)+"\r\n" {
+		t.Errorf("folded badly: %q", folded)
+	}
+	for _, line := range strings.Split(ics+folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"golang.org/x/oauth2"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// First-Run Setup
+
+// firstRun returns true when there is nothing to run with: no arguments, no
+// configuration and no credentials in the environment.
+func firstRun(cfg *config) bool {
+	if len(os.Args) > 1 || cfg.found || !isTerminal() {
+		return false
+	}
+	for _, key := range []string{"GITHUB_USER", "GITHUB_TOKEN", "OPENAI_TOKEN"} {
+		if os.Getenv(key) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setup asks for what a run needs (credentials, default repository and
+// summary type) and saves it, as the default profile, in the configuration
+// file.
+func setup(path string) (*config, error) {
+	in := bufio.NewReader(os.Stdin)
+
+	fmt.Println("Welcome to ghtimecardator! Let's set it up (the answers are saved in " + path + ").")
+	fmt.Println()
+
+	prof := &profile{}
+
+	fmt.Println("A GitHub token (https://github.com/settings/tokens) with the repo and read:org scopes.")
+	token, err := askSecret("GitHub token: ")
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	gh := github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	user, _, err := gh.Users.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("checking the GitHub token: %w", err)
+	}
+	prof.GitHubUser, prof.GitHubToken = user.GetLogin(), token
+	fmt.Printf("Hello, %s.\n\n", prof.GitHubUser)
+
+	fmt.Println("An OpenAI API key (https://platform.openai.com/api-keys), to summarize the work.")
+	prof.OpenAIToken, err = askSecret("OpenAI token: ")
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println()
+
+	for {
+		prof.Repo, err = ask(in, "Default repository (owner/repo, empty for all): ")
+		if err != nil {
+			return nil, err
+		}
+		prof.Repo = strings.ToLower(prof.Repo)
+		if prof.Repo == "" || strings.Contains(prof.Repo, "/") {
+			break
+		}
+		fmt.Println("Invalid owner/repo:", prof.Repo)
+	}
+
+	for {
+		prof.Summary, err = ask(in, "Default summary type (executive, technical, detailed or changelog, empty for executive): ")
+		if err != nil {
+			return nil, err
+		}
+		prof.Summary = strings.ToLower(prof.Summary)
+		if prof.Summary == "" {
+			prof.Summary = "executive"
+		}
+		if validSummaryType(prof.Summary) {
+			break
+		}
+		fmt.Println("Invalid summary type:", prof.Summary)
+	}
+
+	cfg := &config{
+		Profile:  "default",
+		Profiles: map[string]*profile{"default": prof},
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil { // it has the tokens
+		return nil, err
+	}
+	cfg.found = true
+
+	fmt.Printf("\nSaved. Now a test run, over today (next time: ghtimecardator [flags] [date]).\n\n")
+
+	return cfg, nil
+}
+
+// ask asks a question on the terminal, returning the trimmed answer.
+func ask(in *bufio.Reader, question string) (string, error) {
+	fmt.Print(question)
+	answer, err := in.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// askSecret asks for a secret on the terminal, without echoing it.
+func askSecret(question string) (string, error) {
+	for {
+		fmt.Print(question)
+		secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		if s := strings.TrimSpace(string(secret)); s != "" {
+			return s, nil
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Action Significance
+
+// scorer assigns significance to an action on an item: actions scoring 0 are
+// left out, the others count for that many hours, and the items with the most
+// significant actions come first in the report.
+type scorer interface {
+	score(meta *metadata, a *action) float64
+}
+
+// defaultScorer scores opening a pull request above opening an issue, which
+// is above a comment.
+type defaultScorer struct{}
+
+func (defaultScorer) score(meta *metadata, a *action) float64 {
+	switch {
+	case a.action == "opened" && a.object == ObjectPR:
+		return 2
+	case a.action == "opened":
+		return 0.5
+	case a.object == ObjectPRComment:
+		return 0.5
+	default:
+		return 0.25
+	}
+}
+
+// significanceRule scores the actions it matches. Empty fields match
+// anything, the others are matched case insensitively, with * as a wildcard.
+type significanceRule struct {
+	Action string  `yaml:"action,omitempty"` // opened, closed, created, ...
+	Object string  `yaml:"object,omitempty"` // issue, pull request, issue comment, ...
+	Repo   string  `yaml:"repo,omitempty"`   // owner/repo
+	Label  string  `yaml:"label,omitempty"`  // any of the item labels
+	Author *bool   `yaml:"author,omitempty"` // I'm the author of the item
+	Score  float64 `yaml:"score"`
+}
+
+// matches returns true if the rule applies to the action on the item.
+func (r *significanceRule) matches(meta *metadata, a *action) bool {
+	match := func(pattern, text string) bool {
+		return pattern == "" || wildcardMatch(strings.ToLower(pattern), strings.ToLower(text))
+	}
+	if !match(r.Action, a.action) || !match(r.Object, a.object) || !match(r.Repo, meta.repo) {
+		return false
+	}
+	if r.Author != nil && *r.Author != meta.author {
+		return false
+	}
+	if r.Label != "" {
+		for _, label := range meta.labels {
+			if match(r.Label, label) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// policyScorer scores the actions with the first rule that matches them,
+// falling back to the default scores.
+type policyScorer struct {
+	rules    []*significanceRule
+	fallback scorer
+}
+
+func newPolicyScorer(rules []*significanceRule) (*policyScorer, error) {
+	for i, r := range rules {
+		if r.Score < 0 {
+			return nil, fmt.Errorf("significance rule %d: negative score %g", i+1, r.Score)
+		}
+	}
+	return &policyScorer{rules: rules, fallback: defaultScorer{}}, nil
+}
+
+func (p *policyScorer) score(meta *metadata, a *action) float64 {
+	for _, r := range p.rules {
+		if r.matches(meta, a) {
+			return r.Score
+		}
+	}
+	return p.fallback.score(meta, a)
+}
+
+// significance returns the significance of the action on the item.
+func (w *work) significance(id id, a *action) float64 {
+	s := w.scorer
+	if s == nil {
+		s = defaultScorer{}
+	}
+	meta := w.getIssueOrPR(id)
+	if meta == nil {
+		meta = &metadata{eventId: id, repo: id.repo}
+	}
+	return s.score(meta, a)
+}
+
+// dropInsignificant leaves out the actions scoring 0, and the items left
+// without actions.
+func (w *work) dropInsignificant() {
+	drop := make(map[*string]bool) // results of the jobs no longer needed
+	emptied := make(map[id]bool)
+
+	for id, actions := range w.actions {
+		kept := actions[:0]
+		for _, a := range actions {
+			if w.significance(id, a) > 0 {
+				kept = append(kept, a)
+			} else {
+				drop[&a.content] = true
+			}
+		}
+		if len(kept) == 0 {
+			emptied[id] = true
+			continue
+		}
+		w.actions[id] = kept
+	}
+
+	pending := w.pending[:0]
+	for _, j := range w.pending {
+		if !drop[j.result] {
+			pending = append(pending, j)
+		}
+	}
+	w.pending = pending
+
+	w.removeItems(emptied)
+}
+
+// rankedIds returns the ids of the issues or pull requests, the most
+// significant (summing up their actions) first.
+func (w *work) rankedIds(place map[id]*metadata) []id {
+	ids := w.sortedIds(place)
+	total := make(map[id]float64, len(ids))
+	for _, id := range ids {
+		for _, a := range w.actions[id] {
+			total[id] += w.significance(id, a)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return total[ids[i]] > total[ids[j]]
+	})
+	return ids
+}
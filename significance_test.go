@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestPolicyScorer(t *testing.T) {
+	var rules []*significanceRule
+	err := yaml.Unmarshal([]byte(`
+- repo: "owner/mirror-*"
+  score: 0
+- object: "*comment"
+  author: false
+  score: 1
+- label: security
+  score: 3
+`), &rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := newPolicyScorer(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mine := &metadata{repo: "owner/repo", author: true, labels: []string{"security"}}
+	theirs := &metadata{repo: "owner/repo"}
+	mirror := &metadata{repo: "owner/mirror-linux", author: true}
+
+	tests := []struct {
+		meta *metadata
+		a    *action
+		want float64
+	}{
+		{mirror, &action{action: "opened", object: ObjectPR}, 0},
+		{theirs, &action{action: "created", object: ObjectPRComment}, 1},
+		{mine, &action{action: "created", object: ObjectIssueComment}, 3},
+		{theirs, &action{action: "opened", object: ObjectPR}, 2}, // default
+	}
+	for _, tt := range tests {
+		if got := p.score(tt.meta, tt.a); got != tt.want {
+			t.Errorf("score(%s, %s %s) = %g, want %g", tt.meta.repo, tt.a.action, tt.a.object, got, tt.want)
+		}
+	}
+
+	if _, err := newPolicyScorer([]*significanceRule{{Score: -1}}); err == nil {
+		t.Error("negative score didn't fail")
+	}
+}
+
+func TestDropInsignificant(t *testing.T) {
+	w := &work{
+		issues:  make(map[id]*metadata),
+		pulls:   make(map[id]*metadata),
+		actions: make(map[id][]*action),
+		scorer:  &policyScorer{rules: []*significanceRule{{Object: ObjectIssueComment, Score: 0}}, fallback: defaultScorer{}},
+	}
+	noise, pr := newID("owner/repo", 1), newID("owner/repo", 2)
+	w.issues[noise] = &metadata{eventId: noise, repo: "owner/repo"}
+	w.pulls[pr] = &metadata{eventId: pr, repo: "owner/repo"}
+	w.actions[noise] = []*action{{action: "created", object: ObjectIssueComment}}
+	w.actions[pr] = []*action{
+		{action: "opened", object: ObjectPR},
+		{action: "created", object: ObjectIssueComment},
+	}
+
+	w.dropInsignificant()
+
+	if _, ok := w.issues[noise]; ok {
+		t.Error("the issue with only insignificant actions wasn't dropped")
+	}
+	if len(w.actions[pr]) != 1 || w.estimateHours(pr) != 2 {
+		t.Errorf("got %d actions, %gh, want 1 action, 2h", len(w.actions[pr]), w.estimateHours(pr))
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Commit Signing
+
+// signingStat counts my commits, in the period, by signature verification.
+type signingStat struct {
+	commits  int
+	verified int
+	reasons  map[string]int // why commits aren't verified (unsigned, unknown_key, ...)
+	repos    map[string]*signingStat
+}
+
+func newSigningStat() *signingStat {
+	return &signingStat{reasons: make(map[string]int)}
+}
+
+// add counts a commit.
+func (s *signingStat) add(verified bool, reason string) {
+	s.commits++
+	if verified {
+		s.verified++
+	} else {
+		s.reasons[reason]++
+	}
+}
+
+// collectSigning counts the commits authored by the user, between the begin
+// and end dates, allowed by the filters, that are signed (GPG, SSH or S/MIME)
+// and verified by GitHub.
+func collectSigning(ctx context.Context, gh *github.Client, w *work, filter *filters, begin, end time.Time) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	query := fmt.Sprintf("author:%s committer-date:%s..%s", w.user,
+		begin.UTC().Format("2006-01-02T15:04:05Z"), end.UTC().Format("2006-01-02T15:04:05Z"))
+
+	stat := newSigningStat()
+	stat.repos = make(map[string]*signingStat)
+
+	opt := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := gh.Search.Commits(ctx, query, opt)
+		if err != nil {
+			fmt.Println("Error searching commits:", err)
+			return
+		}
+
+		for _, c := range result.Commits {
+			repo := c.GetRepository()
+			e := &github.Event{
+				Repo:   &github.Repository{Name: github.String(repo.GetFullName())},
+				Public: github.Bool(!repo.GetPrivate()),
+			}
+			if !filter.allows(ctx, e) {
+				continue
+			}
+
+			verification := c.GetCommit().GetVerification()
+			if verification == nil { // not always in the search results
+				owner, name, _ := strings.Cut(repo.GetFullName(), "/")
+				commit, _, err := gh.Git.GetCommit(ctx, owner, name, c.GetSHA())
+				if err != nil {
+					fmt.Printf("Error fetching commit %s of %s: %v\n", c.GetSHA(), repo.GetFullName(), err)
+					continue
+				}
+				verification = commit.GetVerification()
+			}
+
+			name := strings.ToLower(repo.GetFullName())
+			if stat.repos[name] == nil {
+				stat.repos[name] = newSigningStat()
+			}
+			stat.add(verification.GetVerified(), verification.GetReason())
+			stat.repos[name].add(verification.GetVerified(), verification.GetReason())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	w.signing = stat
+}
+
+// signingReport returns how many of my commits were signed and verified,
+// overall and per repository.
+func (w *work) signingReport() string {
+	if w.signing == nil {
+		return ""
+	}
+	s := w.signing
+
+	percent := func(s *signingStat) float64 {
+		if s.commits == 0 {
+			return 0
+		}
+		return 100 * float64(s.verified) / float64(s.commits)
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Commit signing:"))
+	report += fmt.Sprintf("  %-40s %5.1f%% (%d of %d commits verified)\n", "all", percent(s), s.verified, s.commits)
+
+	names := make([]string, 0, len(s.repos))
+	for name := range s.repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := s.repos[name]
+		report += fmt.Sprintf("  %-40s %5.1f%% (%d of %d commits verified)\n", name, percent(r), r.verified, r.commits)
+	}
+
+	if len(s.reasons) > 0 {
+		reasons := make([]string, 0, len(s.reasons))
+		for reason, count := range s.reasons {
+			reasons = append(reasons, fmt.Sprintf("%s: %d", reason, count))
+		}
+		sort.Strings(reasons)
+		report += fmt.Sprintf("  Not verified: %s\n", strings.Join(reasons, ", "))
+	}
+
+	return report
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Event Sink
+
+// normalizedEvent is the flat form of an event, for external pipelines.
+type normalizedEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Repo      string    `json:"repo"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	Public    bool      `json:"public"`
+	Action    string    `json:"action,omitempty"`
+	Number    int       `json:"number,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	URL       string    `json:"url,omitempty"`
+}
+
+// normalizeEvent flattens the event, and its payload, when it is known.
+func normalizeEvent(e *github.Event) *normalizedEvent {
+	n := &normalizedEvent{
+		ID:        e.GetID(),
+		Type:      e.GetType(),
+		Repo:      e.GetRepo().GetName(),
+		Actor:     e.GetActor().GetLogin(),
+		CreatedAt: e.GetCreatedAt(),
+		Public:    e.GetPublic(),
+	}
+
+	pay, err := e.ParsePayload()
+	if err != nil {
+		return n
+	}
+	switch v := pay.(type) {
+	case *github.IssuesEvent:
+		n.Action, n.Number = v.GetAction(), v.GetIssue().GetNumber()
+		n.Title, n.URL = v.GetIssue().GetTitle(), v.GetIssue().GetHTMLURL()
+	case *github.PullRequestEvent:
+		n.Action, n.Number = v.GetAction(), v.GetPullRequest().GetNumber()
+		n.Title, n.URL = v.GetPullRequest().GetTitle(), v.GetPullRequest().GetHTMLURL()
+		if n.Action == "closed" && v.GetPullRequest().GetMerged() {
+			n.Action = "merged"
+		}
+	case *github.IssueCommentEvent:
+		n.Action, n.Number = v.GetAction(), v.GetIssue().GetNumber()
+		n.Title, n.URL = v.GetIssue().GetTitle(), v.GetComment().GetHTMLURL()
+	case *github.PullRequestReviewEvent:
+		n.Action, n.Number = v.GetAction(), v.GetPullRequest().GetNumber()
+		n.Title, n.URL = v.GetPullRequest().GetTitle(), v.GetReview().GetHTMLURL()
+	case *github.PullRequestReviewCommentEvent:
+		n.Action, n.Number = v.GetAction(), v.GetPullRequest().GetNumber()
+		n.Title, n.URL = v.GetPullRequest().GetTitle(), v.GetComment().GetHTMLURL()
+	}
+	return n
+}
+
+// eventSink writes the normalized events as NDJSON, one per line.
+type eventSink struct {
+	out    io.WriteCloser
+	enc    *json.Encoder
+	stdout bool
+}
+
+// newEventSink opens the sink file. With "-" the events go to stdout, and
+// everything else (estimate, prompts, report) goes to stderr, so stdout is
+// valid NDJSON.
+func newEventSink(path string) (*eventSink, error) {
+	if path == "-" {
+		out := os.Stdout
+		os.Stdout = os.Stderr
+		return &eventSink{out: out, enc: json.NewEncoder(out), stdout: true}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &eventSink{out: f, enc: json.NewEncoder(f)}, nil
+}
+
+// emit writes the event. Methods on a nil sink do nothing.
+func (s *eventSink) emit(e *github.Event) error {
+	if s == nil {
+		return nil
+	}
+	return s.enc.Encode(normalizeEvent(e))
+}
+
+// close closes the sink file.
+func (s *eventSink) close() {
+	if s == nil || s.stdout {
+		return
+	}
+	s.out.Close()
+}
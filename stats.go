@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/google/go-github/v41/github"
+)
+
+// Stats Only
+
+// dayStats are the contributions of a day.
+type dayStats struct {
+	commits, issues, pulls, reviews int
+}
+
+func (d *dayStats) add(other *dayStats) {
+	d.commits += other.commits
+	d.issues += other.issues
+	d.pulls += other.pulls
+	d.reviews += other.reviews
+}
+
+// fetchStats returns the contributions of the user, between the begin and end
+// dates, allowed by the filters, counted per day (YYYY-MM-DD). They come from
+// the contributions collection alone, which takes a few GraphQL calls instead
+// of going through the events.
+func fetchStats(ctx context.Context, gh *github.Client, user string, begin, end time.Time, filter *filters, s *spinner.Spinner) (map[string]*dayStats, error) {
+	events, err := fetchContributions(ctx, gh, user, begin, end, filter, s)
+	if err != nil {
+		return nil, err
+	}
+	return countStats(events), nil
+}
+
+// countStats counts the commits, issues and pull requests opened, and reviews,
+// of the events per day.
+func countStats(events []*github.Event) map[string]*dayStats {
+	days := make(map[string]*dayStats)
+	for _, e := range events {
+		payload, err := e.ParsePayload()
+		if err != nil {
+			continue
+		}
+		day := e.GetCreatedAt().In(location).Format("2006-01-02")
+		if days[day] == nil {
+			days[day] = &dayStats{}
+		}
+		d := days[day]
+
+		switch v := payload.(type) {
+		case *github.PushEvent:
+			d.commits += v.GetSize()
+		case *github.IssuesEvent:
+			if v.GetAction() == "opened" {
+				d.issues++
+			}
+		case *github.PullRequestEvent:
+			if v.GetAction() == "opened" {
+				d.pulls++
+			}
+		case *github.PullRequestReviewEvent:
+			d.reviews++
+		}
+	}
+	return days
+}
+
+// statsReport returns the contributions per day, and in total, as a table.
+func statsReport(days map[string]*dayStats) string {
+	names := make([]string, 0, len(days))
+	for day := range days {
+		names = append(names, day)
+	}
+	sort.Strings(names)
+
+	report := fmt.Sprintf("%-10s %8s %8s %8s %8s\n", "Day", "Commits", "Issues", "PRs", "Reviews")
+	total := &dayStats{}
+	for _, day := range names {
+		d := days[day]
+		report += fmt.Sprintf("%-10s %8d %8d %8d %8d\n", day, d.commits, d.issues, d.pulls, d.reviews)
+		total.add(d)
+	}
+	report += fmt.Sprintf("%-10s %8d %8d %8d %8d\n", "Total", total.commits, total.issues, total.pulls, total.reviews)
+
+	return report
+}
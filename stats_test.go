@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestStats(t *testing.T) {
+	location = time.UTC
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	event := func(kind string, at time.Time, payload interface{}) *github.Event {
+		e, err := syntheticEvent(kind, "owner/repo", "me", true, 1, at, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	events := []*github.Event{
+		event("PushEvent", day(4), &github.PushEvent{Size: github.Int(3)}),
+		event("IssuesEvent", day(4), &github.IssuesEvent{Action: github.String("opened")}),
+		event("PullRequestEvent", day(5), &github.PullRequestEvent{Action: github.String("opened")}),
+		event("PullRequestEvent", day(5), &github.PullRequestEvent{Action: github.String("closed")}),
+		event("PullRequestReviewEvent", day(5), &github.PullRequestReviewEvent{Action: github.String("created")}),
+	}
+
+	report := statsReport(countStats(events))
+	for _, want := range []string{
+		"2024-03-04        3        1        0        0\n",
+		"2024-03-05        0        0        1        1\n",
+		"Total             3        1        1        1\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report doesn't have %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	_ "modernc.org/sqlite"
+)
+
+// SQLite Store
+
+// store records everything a run fetches and generates (events, issues and
+// pull requests, actions, summaries and timecards), so there is a history to
+// build on. Methods on a nil store do nothing, so the store can be disabled.
+type store struct {
+	db *sql.DB
+}
+
+const storeSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id         TEXT PRIMARY KEY,
+	type       TEXT NOT NULL,
+	repo       TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	payload    TEXT NOT NULL,
+	fetched_at TIMESTAMP NOT NULL
+);
+CREATE TABLE IF NOT EXISTS items (
+	repo       TEXT NOT NULL,
+	number     INTEGER NOT NULL,
+	kind       TEXT NOT NULL,
+	url        TEXT NOT NULL,
+	title      TEXT NOT NULL,
+	author     BOOLEAN NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (repo, number)
+);
+CREATE TABLE IF NOT EXISTS actions (
+	repo       TEXT NOT NULL,
+	number     INTEGER NOT NULL,
+	action     TEXT NOT NULL,
+	object     TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (repo, number, action, object, updated_at)
+);
+CREATE TABLE IF NOT EXISTS summaries (
+	repo        TEXT NOT NULL,
+	item        TEXT NOT NULL,
+	updated_at  TIMESTAMP NOT NULL,
+	prompt_hash TEXT NOT NULL,
+	summary     TEXT NOT NULL,
+	created_at  TIMESTAMP NOT NULL,
+	PRIMARY KEY (repo, item, updated_at, prompt_hash)
+);
+CREATE TABLE IF NOT EXISTS timecards (
+	created_at TIMESTAMP NOT NULL,
+	begin      TIMESTAMP NOT NULL,
+	type       TEXT NOT NULL,
+	timecard   TEXT NOT NULL,
+	variant    TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS runs (
+	started_at  TIMESTAMP NOT NULL,
+	finished_at TIMESTAMP NOT NULL,
+	type        TEXT NOT NULL,
+	format      TEXT NOT NULL,
+	events      INTEGER NOT NULL,
+	items       INTEGER NOT NULL,
+	calls       INTEGER NOT NULL,
+	tokens      INTEGER NOT NULL,
+	dollars     REAL NOT NULL,
+	status      TEXT NOT NULL
+);
+`
+
+// storeMigrations bring databases created by older versions up to date. They
+// fail, harmlessly, when already applied.
+var storeMigrations = []string{
+	`ALTER TABLE timecards ADD COLUMN variant TEXT NOT NULL DEFAULT ''`,
+}
+
+// openStore opens, creating if needed, the database at path.
+func openStore(path string) (*store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(storeSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	for _, migration := range storeMigrations {
+		db.Exec(migration)
+	}
+	return &store{db: db}, nil
+}
+
+// close closes the database.
+func (s *store) close() {
+	if s == nil {
+		return
+	}
+	s.db.Close()
+}
+
+// saveEvents records the fetched events.
+func (s *store) saveEvents(events []*github.Event) error {
+	if s == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT OR REPLACE INTO events VALUES (?, ?, ?, ?, ?, ?)`,
+			e.GetID(), e.GetType(), e.GetRepo().GetName(), e.GetCreatedAt(), string(payload), now)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// saveWork records the issues, pull requests and actions of the work.
+func (s *store) saveWork(w *work) error {
+	if s == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for kind, place := range map[string]map[id]*metadata{"issue": w.issues, "pull": w.pulls} {
+		for id, meta := range place {
+			_, err := tx.Exec(`INSERT OR REPLACE INTO items VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				meta.repo, id.number, kind, meta.url, meta.title, meta.author, meta.updated)
+			if err != nil {
+				return err
+			}
+			for _, a := range w.actions[id] {
+				_, err := tx.Exec(`INSERT OR IGNORE INTO actions VALUES (?, ?, ?, ?, ?)`,
+					meta.repo, id.number, a.action, a.object, a.updated)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// saveSummary records the summary generated by the job.
+func (s *store) saveSummary(j *job, summary string) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO summaries VALUES (?, ?, ?, ?, ?, ?)`,
+		j.repo, j.item, j.updated, promptHash(j), summary, time.Now())
+	return err
+}
+
+// saveTimecard records the generated timecard, and the prompt variant that
+// produced it (empty for the default prompts).
+func (s *store) saveTimecard(begin time.Time, summaryType, variant, timecard string) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.db.Exec(`INSERT INTO timecards (created_at, begin, type, timecard, variant) VALUES (?, ?, ?, ?, ?)`,
+		time.Now(), begin, summaryType, timecard, variant)
+	return err
+}
+
+// loadTimecard returns the latest timecard recorded for the period, summary
+// type and prompt variant (empty if there is none).
+func (s *store) loadTimecard(begin time.Time, summaryType, variant string) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+	var timecard string
+	err := s.db.QueryRow(`SELECT timecard FROM timecards WHERE begin = ? AND type = ? AND variant = ? ORDER BY created_at DESC LIMIT 1`,
+		begin, summaryType, variant).Scan(&timecard)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return timecard, err
+}
+
+// saveRun records a run, for the usage statistics.
+func (s *store) saveRun(r *runRecord) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.db.Exec(`INSERT INTO runs VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.started, r.finished, r.summaryType, r.format, r.events, r.items, r.calls, r.tokens, r.dollars, r.status)
+	return err
+}
+
+// loadRuns returns the recorded runs, oldest first.
+func (s *store) loadRuns() ([]*runRecord, error) {
+	rows, err := s.db.Query(`SELECT started_at, finished_at, type, format, events, items, calls, tokens, dollars, status FROM runs ORDER BY started_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*runRecord
+	for rows.Next() {
+		r := &runRecord{}
+		err := rows.Scan(&r.started, &r.finished, &r.summaryType, &r.format, &r.events, &r.items, &r.calls, &r.tokens, &r.dollars, &r.status)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Applied Suggestions
+
+// webFlow is who commits the suggestions applied from the GitHub web UI.
+const webFlow = "web-flow"
+
+// suggestion is a commit applying review suggestions to a pull request.
+type suggestion struct {
+	pull    id
+	sha     string
+	message string
+	by      []string // who suggested (the co-authors)
+	at      time.Time
+}
+
+// suggestionStat is the impact of the code reviews: my suggestions applied by
+// the authors, and the suggestions of others I applied to my pull requests.
+type suggestionStat struct {
+	given    []*suggestion // mine, applied by the authors
+	received []*suggestion // of others, applied by me
+}
+
+// coAuthors returns the co-authors (Name <email>) of a commit message.
+func coAuthors(message string) []string {
+	return trailers(message, "Co-authored-by")
+}
+
+// isMe returns true if the co-author (Name <email>) is the user: by the
+// GitHub noreply email, the public email or the name.
+func isMe(coAuthor string, user *github.User) bool {
+	name, email, _ := strings.Cut(coAuthor, "<")
+	name = strings.TrimSpace(name)
+	email = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(email), ">"))
+
+	login := strings.ToLower(user.GetLogin())
+	switch {
+	case email == login+"@users.noreply.github.com":
+		return true
+	case strings.HasSuffix(email, "+"+login+"@users.noreply.github.com"):
+		return true
+	case user.GetEmail() != "" && strings.EqualFold(email, user.GetEmail()):
+		return true
+	}
+	return user.GetName() != "" && strings.EqualFold(name, user.GetName())
+}
+
+// collectSuggestions fetches, from the commits of the pull requests I worked
+// on, the review suggestions applied between the begin and end dates: on the
+// pull requests of others, the ones I suggested; on mine, the ones I applied.
+// A commit applying a batch of suggestions counts once.
+func collectSuggestions(ctx context.Context, gh *github.Client, w *work, begin, end time.Time) error {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	user, _, err := gh.Users.Get(ctx, w.user)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range w.sortedIds(w.pulls) {
+		meta := w.pulls[id]
+		owner, name, _ := strings.Cut(id.repo, "/")
+
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			commits, resp, err := gh.PullRequests.ListCommits(ctx, owner, name, id.number, opt)
+			if err != nil {
+				fmt.Printf("Error fetching the commits of %s#%d: %v\n", id.repo, id.number, err)
+				break
+			}
+			for _, c := range commits {
+				if s := suggestionOf(id, meta.author, c, user); s != nil && !s.at.Before(begin) && s.at.Before(end) {
+					if meta.author {
+						w.suggestions.received = append(w.suggestions.received, s)
+					} else {
+						w.suggestions.given = append(w.suggestions.given, s)
+					}
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+
+	return nil
+}
+
+// suggestionOf returns the commit as applied suggestions, if it is one that
+// counts: committed from the web UI with co-authors and, on my pull requests,
+// applied by me from others or, on the pull requests of others, suggested by me.
+func suggestionOf(pull id, author bool, c *github.RepositoryCommit, user *github.User) *suggestion {
+	if c.GetCommitter().GetLogin() != webFlow {
+		return nil
+	}
+	authors := coAuthors(c.GetCommit().GetMessage())
+	if len(authors) == 0 {
+		return nil
+	}
+
+	var by []string
+	mine := false
+	for _, a := range authors {
+		if isMe(a, user) {
+			mine = true
+			continue
+		}
+		name, _, _ := strings.Cut(a, "<")
+		by = append(by, strings.TrimSpace(name))
+	}
+	applier := strings.EqualFold(c.GetAuthor().GetLogin(), user.GetLogin())
+	if author && (!applier || len(by) == 0) || !author && !mine {
+		return nil
+	}
+	if !author {
+		by = nil
+	}
+
+	message, _, _ := strings.Cut(c.GetCommit().GetMessage(), "\n")
+	return &suggestion{
+		pull:    pull,
+		sha:     c.GetSHA(),
+		message: message,
+		by:      by,
+		at:      c.GetCommit().GetCommitter().GetDate(),
+	}
+}
+
+// suggestionsReport returns the review suggestions applied, per pull request.
+func (w *work) suggestionsReport() string {
+	s := w.suggestions
+	if len(s.given) == 0 && len(s.received) == 0 {
+		return ""
+	}
+
+	line := func(sg *suggestion) string {
+		meta := w.pulls[sg.pull]
+		sha := sg.sha
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		text := fmt.Sprintf("  %s %s%s (%s) %s: %s %s", sg.at.In(location).Format("2006-01-02"), sg.pull.repo, sg.pull, meta.url, meta.title, sha, sg.message)
+		if len(sg.by) > 0 {
+			text += " (suggested by " + strings.Join(sg.by, ", ") + ")"
+		}
+		return text + "\n"
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Suggestions applied:"))
+	report += fmt.Sprintf("Mine, applied by the authors: %d\n", len(s.given))
+	for _, sg := range s.given {
+		report += line(sg)
+	}
+	report += fmt.Sprintf("Of others, applied to my pull requests: %d\n", len(s.received))
+	for _, sg := range s.received {
+		report += line(sg)
+	}
+
+	return report
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestSuggestionOf(t *testing.T) {
+	me := &github.User{Login: github.String("me"), Name: github.String("My Name")}
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	commit := func(author, committer, message string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			SHA:       github.String("0123456789abcdef"),
+			Author:    &github.User{Login: github.String(author)},
+			Committer: &github.User{Login: github.String(committer)},
+			Commit: &github.Commit{
+				Message:   github.String(message),
+				Committer: &github.CommitAuthor{Date: &at},
+			},
+		}
+	}
+	pull := newID("owner/repo", 1)
+
+	// Mine, applied by the author of the pull request.
+	c := commit("other", webFlow, "Apply suggestions from code review\n\nCo-authored-by: Someone <123+me@users.noreply.github.com>")
+	if s := suggestionOf(pull, false, c, me); s == nil || s.message != "Apply suggestions from code review" || len(s.by) != 0 {
+		t.Errorf("got %+v, want my suggestion applied", s)
+	}
+	// Of others, applied by me to my pull request.
+	c = commit("me", webFlow, "Update main.go\n\nco-authored-by: Alice <alice@example.com>\nCo-authored-by: my name <me@example.com>")
+	if s := suggestionOf(pull, true, c, me); s == nil || strings.Join(s.by, ",") != "Alice" {
+		t.Errorf("got %+v, want Alice's suggestion applied", s)
+	}
+	// Not suggested by me, not from the web UI, not applied by me.
+	for _, tc := range []struct {
+		c      *github.RepositoryCommit
+		author bool
+	}{
+		{commit("other", webFlow, "Apply suggestions\n\nCo-authored-by: Alice <alice@example.com>"), false},
+		{commit("other", "other", "Fix\n\nCo-authored-by: My Name <me@example.com>"), false},
+		{commit("other", webFlow, "Apply\n\nCo-authored-by: Alice <alice@example.com>"), true},
+		{commit("me", webFlow, "Fix typo"), true},
+	} {
+		if s := suggestionOf(pull, tc.author, tc.c, me); s != nil {
+			t.Errorf("got %+v, want nil for %q", s, tc.c.GetCommit().GetMessage())
+		}
+	}
+}
+
+func TestSuggestionsReport(t *testing.T) {
+	location = time.UTC
+	pull := newID("owner/repo", 1)
+	at := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	w := &work{
+		pulls: map[id]*metadata{pull: {url: "https://github.com/owner/repo/pull/1", title: "Fix"}},
+		suggestions: suggestionStat{
+			received: []*suggestion{{pull: pull, sha: "0123456789", message: "Update main.go", by: []string{"Alice"}, at: at}},
+		},
+	}
+
+	report := w.suggestionsReport()
+	for _, want := range []string{
+		"Mine, applied by the authors: 0\n",
+		"Of others, applied to my pull requests: 1\n",
+		"  2024-03-04 owner/repo#1 (https://github.com/owner/repo/pull/1) Fix: 0123456 Update main.go (suggested by Alice)\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q doesn't contain %q", report, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Tempo Worklogs
+
+const (
+	FormatTempo     = "tempo"      // CSV, for the Tempo Timesheets importer
+	FormatTempoJSON = "tempo-json" // JSON, as the Tempo worklogs API takes them
+)
+
+// tempoWorklog is the time spent on an item on a day, logged to a Jira issue.
+type tempoWorklog struct {
+	IssueKey         string `json:"issueKey"`
+	StartDate        string `json:"startDate"` // YYYY-MM-DD
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Description      string `json:"description"`
+}
+
+// tempoIssue returns the Jira issue the work on the repository is logged to:
+// the one of the most specific pattern (owner/repo, owner/*, *) matching it.
+func tempoIssue(issues map[string]string, repo string) string {
+	patterns := make([]string, 0, len(issues))
+	for pattern := range issues {
+		patterns = append(patterns, pattern)
+	}
+	best, _ := bestPattern(patterns, repo)
+	return issues[best]
+}
+
+// tempoWorklogs returns a worklog per item and day, with the estimated hours
+// (by the effort weights) of the item split in its days by how many of its
+// actions were on each day, described by the (ledger) summary of the item. The
+// work is logged to the Jira issues the item mentions (split evenly among
+// them) or else to the one of its repository. The repositories without a
+// Jira issue are returned apart.
+func (w *work) tempoWorklogs(ids []id, results map[id]*string, issues map[string]string, weights *effortWeights) ([]*tempoWorklog, []string) {
+	var worklogs []*tempoWorklog
+	unmapped := make(map[string]bool)
+
+	for _, id := range ids {
+		meta := w.getIssueOrPR(id)
+		keys := w.jiraKeys(id)
+		if len(keys) == 0 {
+			if key := tempoIssue(issues, meta.repo); key != "" {
+				keys = []string{key}
+			}
+		}
+		if len(keys) == 0 {
+			unmapped[meta.repo] = true
+			continue
+		}
+
+		actions := make(map[string]int)
+		for _, a := range w.actions[id] {
+			at := a.at
+			if at.IsZero() {
+				at = a.updated
+			}
+			actions[at.In(location).Format("2006-01-02")]++
+		}
+		effort := w.estimateEffort(id, weights)
+		hours := make(map[string]float64)
+		for day, count := range actions {
+			hours[day] = effort * float64(count) / float64(len(w.actions[id]))
+		}
+		days := make([]string, 0, len(hours))
+		for day := range hours {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+
+		category, summary := parseLedgerSummary(*results[id])
+		_, summary = w.overridden(id, category, summary)
+		description := fmt.Sprintf("%s%s %s: %s", meta.repo, id, meta.title, summary)
+		for _, day := range days {
+			for _, key := range keys {
+				worklogs = append(worklogs, &tempoWorklog{
+					IssueKey:         key,
+					StartDate:        day,
+					TimeSpentSeconds: int(math.Round(hours[day] * 3600 / float64(len(keys)))),
+					Description:      description,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(worklogs, func(i, j int) bool {
+		return worklogs[i].StartDate < worklogs[j].StartDate
+	})
+
+	var repos []string
+	for repo := range unmapped {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	return worklogs, repos
+}
+
+// tempoReport returns the worklogs as CSV (issue key, date, time spent in
+// seconds and description) or, in the tempo-json format, as JSON.
+func tempoReport(worklogs []*tempoWorklog, format string) (string, error) {
+	if format == FormatTempoJSON {
+		if worklogs == nil {
+			worklogs = []*tempoWorklog{}
+		}
+		data, err := json.MarshalIndent(worklogs, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	}
+
+	var buf bytes.Buffer
+	out := csv.NewWriter(&buf)
+	out.Write([]string{"Issue Key", "Date", "Time Spent (seconds)", "Description"})
+	for _, wl := range worklogs {
+		out.Write([]string{wl.IssueKey, wl.StartDate, strconv.Itoa(wl.TimeSpentSeconds), wl.Description})
+	}
+	out.Flush()
+	return buf.String(), out.Error()
+}
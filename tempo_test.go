@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTempoIssue(t *testing.T) {
+	issues := map[string]string{
+		"*":                   "OPS-1",
+		"aquasecurity/*":      "TRC-10",
+		"aquasecurity/btfhub": "BTF-2",
+	}
+	tests := map[string]string{
+		"aquasecurity/btfhub": "BTF-2",
+		"aquasecurity/tracee": "TRC-10",
+		"rafaeldtinoco/blog":  "OPS-1",
+	}
+	for repo, want := range tests {
+		if got := tempoIssue(issues, repo); got != want {
+			t.Errorf("tempoIssue(%s) = %s, want %s", repo, got, want)
+		}
+	}
+	if got := tempoIssue(map[string]string{"aquasecurity/*": "TRC-10"}, "other/repo"); got != "" {
+		t.Errorf("tempoIssue(other/repo) = %s, want none", got)
+	}
+}
+
+func TestTempoWorklogs(t *testing.T) {
+	location = time.UTC
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	pr, other := newID("owner/repo", 7), newID("other/repo", 1)
+	w := &work{
+		issues: map[id]*metadata{other: {eventId: other, repo: "other/repo"}},
+		pulls:  map[id]*metadata{pr: {eventId: pr, repo: "owner/repo", title: "Add the thing"}},
+		actions: map[id][]*action{
+			pr: {
+				{action: "opened", object: ObjectPR, at: day(4)},
+				{action: "created", object: ObjectPRComment, at: day(5)},
+				{action: "created", object: ObjectIssueComment, at: day(5)},
+			},
+			other: {{action: "opened", object: ObjectIssue, at: day(4)}},
+		},
+	}
+	summary := "feature: adds the thing"
+	results := map[id]*string{pr: &summary, other: &summary}
+
+	// 1.75h (a day long span and two comments), a third of it on the 4th
+	worklogs, unmapped := w.tempoWorklogs([]id{other, pr}, results, map[string]string{"owner/*": "OPS-7"}, defaultEffort.withDefaults())
+	if len(unmapped) != 1 || unmapped[0] != "other/repo" {
+		t.Errorf("got unmapped %v, want other/repo", unmapped)
+	}
+
+	report, err := tempoReport(worklogs, FormatTempo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Issue Key,Date,Time Spent (seconds),Description\n" +
+		"OPS-7,2024-03-04,2100,owner/repo#7 Add the thing: adds the thing\n" +
+		"OPS-7,2024-03-05,4200,owner/repo#7 Add the thing: adds the thing\n"
+	if report != want {
+		t.Errorf("got:\n%s\nwant:\n%s", report, want)
+	}
+
+	report, err = tempoReport(worklogs, FormatTempoJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report, `"issueKey": "OPS-7"`) || !strings.Contains(report, `"timeSpentSeconds": 4200`) {
+		t.Errorf("unexpected JSON:\n%s", report)
+	}
+}
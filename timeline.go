@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeline
+
+// timelineVerb returns what the action was, as told in the timeline.
+func timelineVerb(a *action) string {
+	switch a.object {
+	case ObjectIssueComment:
+		return "commented on"
+	case ObjectPRComment:
+		return "reviewed"
+	case ObjectCommit:
+		return "pushed to"
+	}
+	return a.action // opened, closed, merged, reopened, edited, ...
+}
+
+// timelineReport returns, for each day with activity, what I did on each
+// issue and pull request that day: the verbs in the order they first
+// happened, each with its items.
+func (w *work) timelineReport() string {
+	type entry struct {
+		at   time.Time
+		verb string
+		item string
+	}
+	var entries []entry
+	for _, place := range []map[id]*metadata{w.issues, w.pulls} {
+		for _, id := range w.sortedIds(place) {
+			meta := place[id]
+			for _, a := range w.actions[id] {
+				if a.at.IsZero() {
+					continue
+				}
+				item := fmt.Sprintf("%s%s (%s)", meta.repo, id, meta.title)
+				entries = append(entries, entry{at: a.at, verb: timelineVerb(a), item: item})
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Timeline:"))
+	for len(entries) > 0 {
+		day := startOfDay(entries[0].at)
+		var verbs []string
+		items := make(map[string][]string)
+		seen := make(map[string]bool)
+		for len(entries) > 0 && startOfDay(entries[0].at).Equal(day) {
+			e := entries[0]
+			entries = entries[1:]
+			if seen[e.verb+e.item] {
+				continue
+			}
+			seen[e.verb+e.item] = true
+			if len(items[e.verb]) == 0 {
+				verbs = append(verbs, e.verb)
+			}
+			items[e.verb] = append(items[e.verb], e.item)
+		}
+
+		var done []string
+		for _, verb := range verbs {
+			done = append(done, verb+" "+strings.Join(items[verb], ", "))
+		}
+		report += fmt.Sprintf("%s: %s\n", day.Format("Mon 2006-01-02"), strings.Join(done, "; "))
+	}
+	return report
+}
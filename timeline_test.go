@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineReport(t *testing.T) {
+	location = time.UTC
+	mon := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tue := mon.AddDate(0, 0, 1)
+	issue, pull := newID("owner/repo", 456), newID("owner/repo", 123)
+	w := &work{
+		issues: map[id]*metadata{issue: {repo: "owner/repo", title: "Crash on start"}},
+		pulls:  map[id]*metadata{pull: {repo: "owner/repo", title: "Fix the crash"}},
+		actions: map[id][]*action{
+			issue: {
+				{action: "opened", object: ObjectIssue, at: mon.Add(time.Hour)},
+				{action: "created", object: ObjectIssueComment, at: tue},
+			},
+			pull: {
+				{action: "created", object: ObjectPRComment, at: mon},
+				{action: "created", object: ObjectPRComment, at: mon.Add(2 * time.Hour)},
+				{action: "merged", object: ObjectPR, at: tue.Add(time.Hour)},
+				{action: "edited", object: ObjectPR}, // no time, left out
+			},
+		},
+	}
+
+	want := `
+Timeline:
+
+Mon 2024-03-04: reviewed owner/repo#123 (Fix the crash); opened owner/repo#456 (Crash on start)
+Tue 2024-03-05: commented on owner/repo#456 (Crash on start); merged owner/repo#123 (Fix the crash)
+`
+	if got := w.timelineReport(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := (&work{}).timelineReport(); got != "" {
+		t.Errorf("got %q, want no section", got)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timesheet
+
+// FormatTimesheet is the output format of the estimated hours per day and
+// project, rounded, to paste into a timesheet system.
+const FormatTimesheet = "timesheet"
+
+// timesheetEntry is the time spent on a project on a day.
+type timesheetEntry struct {
+	day     string // YYYY-MM-DD
+	project string // billing code, or owner/repo without billing codes
+	hours   float64
+	items   []string // owner/repo#N
+}
+
+// roundHours rounds the hours to the nearest increment, but not below one
+// increment: some work on the day is worth a line.
+func roundHours(hours float64, increment time.Duration) float64 {
+	step := increment.Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return math.Max(step, math.Round(hours/step)*step)
+}
+
+// timesheet returns the estimated hours (by the effort weights) per day and
+// project, rounded to the increment. The hours of an item are split in the
+// days by how many of its actions were on each day. The project is the
+// billing code of the repository, or the repository without billing codes.
+func (w *work) timesheet(ids []id, codes map[string]string, weights *effortWeights, increment time.Duration) []*timesheetEntry {
+	entries := make(map[[2]string]*timesheetEntry)
+	for _, id := range ids {
+		meta := w.getIssueOrPR(id)
+		project := meta.repo
+		if len(codes) > 0 {
+			project = billingCode(codes, meta.repo)
+		}
+
+		actions := make(map[string]int)
+		for _, a := range w.actions[id] {
+			at := a.at
+			if at.IsZero() {
+				at = a.updated
+			}
+			actions[at.In(location).Format("2006-01-02")]++
+		}
+		hours := w.estimateEffort(id, weights)
+		for day, count := range actions {
+			key := [2]string{day, project}
+			e := entries[key]
+			if e == nil {
+				e = &timesheetEntry{day: day, project: project}
+				entries[key] = e
+			}
+			e.hours += hours * float64(count) / float64(len(w.actions[id]))
+			e.items = append(e.items, fmt.Sprintf("%s%s", meta.repo, id))
+		}
+	}
+
+	sheet := make([]*timesheetEntry, 0, len(entries))
+	for _, e := range entries {
+		e.hours = roundHours(e.hours, increment)
+		sort.Strings(e.items)
+		sheet = append(sheet, e)
+	}
+	sort.Slice(sheet, func(i, j int) bool {
+		if sheet[i].day != sheet[j].day {
+			return sheet[i].day < sheet[j].day
+		}
+		return sheet[i].project < sheet[j].project
+	})
+	return sheet
+}
+
+// timesheetReport returns the timesheet as CSV (date, project, hours and
+// items).
+func timesheetReport(sheet []*timesheetEntry) (string, error) {
+	var buf bytes.Buffer
+	out := csv.NewWriter(&buf)
+	out.Write([]string{"Date", "Project", "Hours", "Items"})
+	for _, e := range sheet {
+		out.Write([]string{e.day, e.project, fmt.Sprintf("%.2f", e.hours), strings.Join(e.items, " ")})
+	}
+	out.Flush()
+	return buf.String(), out.Error()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundHours(t *testing.T) {
+	for _, c := range []struct {
+		hours     float64
+		increment time.Duration
+		want      float64
+	}{
+		{0, 15 * time.Minute, 0},
+		{0.05, 15 * time.Minute, 0.25},
+		{1.1, 15 * time.Minute, 1},
+		{1.2, 15 * time.Minute, 1.25},
+		{1.2, 30 * time.Minute, 1},
+		{1.3, 30 * time.Minute, 1.5},
+		{2.6, time.Hour, 3},
+	} {
+		if got := roundHours(c.hours, c.increment); got != c.want {
+			t.Errorf("roundHours(%v, %v) = %v, want %v", c.hours, c.increment, got, c.want)
+		}
+	}
+}
+
+func TestTimesheet(t *testing.T) {
+	location = time.UTC
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	i1, p2, p3 := newID("influxdata/influxdb", 1), newID("influxdata/influxdb", 2), newID("other/repo", 3)
+	w := &work{
+		issues: map[id]*metadata{i1: {repo: "influxdata/influxdb"}},
+		pulls: map[id]*metadata{
+			p2: {repo: "influxdata/influxdb", author: true, diffStat: true, additions: 100},
+			p3: {repo: "other/repo"},
+		},
+		actions: map[id][]*action{
+			i1: {{at: day, object: ObjectIssueComment}},
+			p2: {{at: day, object: ObjectPR}, {at: day.AddDate(0, 0, 1), object: ObjectPRComment}},
+			p3: {{at: day, object: ObjectPRComment}},
+		},
+		responses: make(map[id][]time.Time),
+	}
+	ids := []id{i1, p2, p3}
+	weights := defaultEffort.withDefaults()
+
+	want := `Date,Project,Hours,Items
+2024-03-04,ClientA:ProjX,1.50,influxdata/influxdb#1 influxdata/influxdb#2
+2024-03-04,unmapped,0.25,other/repo#3
+2024-03-05,ClientA:ProjX,1.25,influxdata/influxdb#2
+`
+	got, err := timesheetReport(w.timesheet(ids, map[string]string{"influxdata/*": "ClientA:ProjX"}, weights, 15*time.Minute))
+	if err != nil || got != want {
+		t.Errorf("got %q (%v), want %q", got, err, want)
+	}
+
+	sheet := w.timesheet(ids, nil, weights, time.Hour)
+	if len(sheet) != 3 || sheet[1].project != "other/repo" || sheet[1].hours != 1 {
+		t.Errorf("without billing codes, got %+v", sheet[1])
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Toggl Track
+
+const togglAPI = "https://api.track.toggl.com/api/v9/"
+
+// togglProject is the Toggl Track project, and tags, the sessions in a
+// repository are logged to.
+type togglProject struct {
+	ProjectID int64    `yaml:"project_id,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+}
+
+// togglEntry is a Toggl Track time entry, the fields written.
+type togglEntry struct {
+	CreatedWith string    `json:"created_with"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+	Duration    int64     `json:"duration"` // seconds
+	ProjectID   int64     `json:"project_id,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	WorkspaceID int64     `json:"workspace_id"`
+}
+
+// togglEntries returns a time entry per work session, in the project (and
+// with the tags) of the most specific pattern (owner/repo, owner/*, *)
+// matching the repository most of its activity was in, if any.
+func togglEntries(sessions []*session, projects map[string]*togglProject, workspace int64) []*togglEntry {
+	patterns := make([]string, 0, len(projects))
+	for pattern := range projects {
+		patterns = append(patterns, pattern)
+	}
+
+	var entries []*togglEntry
+	for _, s := range sessions {
+		e := &togglEntry{
+			CreatedWith: "ghtimecardator",
+			Description: strings.Join(s.items, "; "),
+			Start:       s.start.UTC(),
+			Stop:        s.end.UTC(),
+			Duration:    int64(s.end.Sub(s.start).Seconds()),
+			WorkspaceID: workspace,
+		}
+		if best, ok := bestPattern(patterns, s.repo); ok {
+			e.ProjectID, e.Tags = projects[best].ProjectID, projects[best].Tags
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// togglPreview returns what the time entries would be, a line each.
+func togglPreview(entries []*togglEntry) string {
+	var preview string
+	for _, e := range entries {
+		line := fmt.Sprintf("%s-%s", e.Start.In(location).Format("2006-01-02 15:04"), e.Stop.In(location).Format("15:04"))
+		if e.ProjectID != 0 {
+			line += fmt.Sprintf(" project %d", e.ProjectID)
+		}
+		if len(e.Tags) > 0 {
+			line += " [" + strings.Join(e.Tags, ", ") + "]"
+		}
+		preview += line + ": " + e.Description + "\n"
+	}
+	return preview
+}
+
+// togglClient writes to the Toggl Track API (v9), with an API token.
+type togglClient struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func newTogglClient(token string) *togglClient {
+	return &togglClient{
+		url:   togglAPI,
+		token: token,
+		http:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends the body (if any), as JSON, to the API path and decodes the JSON
+// answer into out (if any).
+func (c *togglClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		data = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, data)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.token, "api_token")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("toggl: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// create creates the time entries, but the ones already there (same start and
+// description, from an earlier run), and returns how many it created.
+func (c *togglClient) create(ctx context.Context, entries []*togglEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	query := url.Values{}
+	query.Set("start_date", entries[0].Start.Format(time.RFC3339))
+	query.Set("end_date", entries[len(entries)-1].Stop.Add(time.Second).Format(time.RFC3339))
+	var existing []*togglEntry
+	if err := c.do(ctx, http.MethodGet, "me/time_entries?"+query.Encode(), nil, &existing); err != nil {
+		return 0, err
+	}
+	there := make(map[string]bool)
+	for _, e := range existing {
+		there[e.Start.UTC().Format(time.RFC3339)+e.Description] = true
+	}
+
+	created := 0
+	for _, e := range entries {
+		if there[e.Start.Format(time.RFC3339)+e.Description] {
+			continue
+		}
+		if err := c.do(ctx, http.MethodPost, fmt.Sprintf("workspaces/%d/time_entries", e.WorkspaceID), e, nil); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
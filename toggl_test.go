@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestToggl(t *testing.T) {
+	setLocation(t, time.UTC)
+	at := func(hour, min int) time.Time { return time.Date(2024, 3, 4, hour, min, 0, 0, time.UTC) }
+
+	sessions := []*session{
+		{start: at(9, 0), end: at(10, 30), items: []string{"influxdata/influxdb#1 (Crash)", "influxdata/influxdb#2 (Fix)"}, repo: "influxdata/influxdb"},
+		{start: at(14, 0), end: at(14, 15), items: []string{"other/repo#3 (Docs)"}, repo: "other/repo"},
+	}
+	projects := map[string]*togglProject{"influxdata/*": {ProjectID: 7, Tags: []string{"oss"}}}
+
+	entries := togglEntries(sessions, projects, 42)
+	want := `2024-03-04 09:00-10:30 project 7 [oss]: influxdata/influxdb#1 (Crash); influxdata/influxdb#2 (Fix)
+2024-03-04 14:00-14:15: other/repo#3 (Docs)
+`
+	if got := togglPreview(entries); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if entries[0].Duration != 5400 || entries[1].ProjectID != 0 || entries[1].WorkspaceID != 42 {
+		t.Errorf("got %+v, %+v", entries[0], entries[1])
+	}
+
+	var created []*togglEntry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "token" || pass != "api_token" {
+			t.Errorf("auth %s:%s", user, pass)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/me/time_entries":
+			w.Write([]byte(`[{"description": "influxdata/influxdb#1 (Crash); influxdata/influxdb#2 (Fix)", "start": "2024-03-04T09:00:00+00:00"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/workspaces/42/time_entries":
+			e := &togglEntry{}
+			json.NewDecoder(r.Body).Decode(e)
+			created = append(created, e)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	c := newTogglClient("token")
+	c.url = server.URL + "/"
+	n, err := c.create(context.Background(), entries)
+	if err != nil || n != 1 || len(created) != 1 || created[0].Description != "other/repo#3 (Docs)" {
+		t.Errorf("created %d (%v): %+v", n, err, created)
+	}
+}
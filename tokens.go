@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Token Checks
+
+// tokenExpirationHeader is where GitHub tells when the token (a fine-grained,
+// or expiring classic, personal access token) expires.
+const tokenExpirationHeader = "Github-Authentication-Token-Expiration"
+
+const openAIModelsAPI = "https://api.openai.com/v1/models/"
+
+// tokenExpiry returns when the token expires, from the header of a GitHub
+// response (zero if it doesn't expire, or isn't known).
+func tokenExpiry(header http.Header) time.Time {
+	value := header.Get(tokenExpirationHeader)
+	for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// expiryWarning returns the warning for a token expiring within the days of
+// now (empty if it doesn't).
+func expiryWarning(expires, now time.Time, days int) string {
+	if expires.IsZero() || expires.Sub(now) > time.Duration(days)*24*time.Hour {
+		return ""
+	}
+	if !expires.After(now) {
+		return fmt.Sprintf("the GitHub token expired on %s", expires.In(location).Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("the GitHub token expires on %s (in %s)", expires.In(location).Format("2006-01-02 15:04"), formatSpan(expires.Sub(now)))
+}
+
+// checkOpenAIKey returns what is wrong with the OpenAI key (invalid, or without
+// access to the model), before anything is summarized with it.
+func checkOpenAIKey(ctx context.Context, client *http.Client, url, token, organization, project, model string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+model, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if organization != "" {
+		req.Header.Set("OpenAI-Organization", organization)
+	}
+	if project != "" {
+		req.Header.Set("OpenAI-Project", project)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	var answer struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	json.Unmarshal(body, &answer)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("the OpenAI key is invalid, or revoked")
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("the OpenAI key has no access to %s", model)
+	case answer.Error.Message != "":
+		return fmt.Errorf("openai: %s: %s", resp.Status, answer.Error.Message)
+	}
+	return fmt.Errorf("openai: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
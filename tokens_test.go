@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	location = time.UTC
+	now := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	if got := tokenExpiry(header); !got.IsZero() {
+		t.Errorf("got %v without the header", got)
+	}
+	header.Set(tokenExpirationHeader, "2024-03-10 12:00:00 UTC")
+	expires := tokenExpiry(header)
+	if want := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC); !expires.Equal(want) {
+		t.Errorf("got %v, want %v", expires, want)
+	}
+
+	for _, c := range []struct {
+		days int
+		now  time.Time
+		want string
+	}{
+		{7, now, "the GitHub token expires on 2024-03-10 12:00 (in 2d0h)"},
+		{1, now, ""},
+		{0, now.AddDate(0, 0, 3), "the GitHub token expired on 2024-03-10 12:00"},
+	} {
+		if got := expiryWarning(expires, c.now, c.days); got != c.want {
+			t.Errorf("%d days: got %q, want %q", c.days, got, c.want)
+		}
+	}
+	if got := expiryWarning(time.Time{}, now, 7); got != "" {
+		t.Errorf("got %q for a token that doesn't expire", got)
+	}
+}
+
+func TestCheckOpenAIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") != "Bearer good":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"message": "Incorrect API key provided"}}`))
+		case r.Header.Get("OpenAI-Project") != "proj":
+			t.Errorf("no project header")
+		case r.URL.Path != "/v1/models/gpt-4":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	url := server.URL + "/v1/models/"
+	for _, c := range []struct {
+		token, model, want string
+	}{
+		{"good", "gpt-4", ""},
+		{"bad", "gpt-4", "the OpenAI key is invalid, or revoked"},
+		{"good", "gpt-5", "the OpenAI key has no access to gpt-5"},
+	} {
+		got := ""
+		if err := checkOpenAIKey(context.Background(), server.Client(), url, c.token, "", "proj", c.model); err != nil {
+			got = err.Error()
+		}
+		if got != c.want {
+			t.Errorf("%s, %s: got %q, want %q", c.token, c.model, got, c.want)
+		}
+	}
+}
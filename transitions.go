@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Issue Transitions
+
+// transition is an issue I opened, closed or reopened.
+type transition struct {
+	item   id
+	url    string
+	title  string
+	from   string // open or closed, empty when opened
+	to     string // open or closed
+	reason string // why it was closed: completed, not planned, duplicate
+	at     time.Time
+}
+
+// stateReason returns why the issue of an issues event was closed. The
+// library doesn't know the field yet, so it comes from the raw payload.
+func stateReason(e *github.Event) string {
+	var payload struct {
+		Issue struct {
+			StateReason string `json:"state_reason"`
+		} `json:"issue"`
+	}
+	if e.RawPayload == nil || json.Unmarshal(*e.RawPayload, &payload) != nil {
+		return ""
+	}
+	return strings.ReplaceAll(payload.Issue.StateReason, "_", " ")
+}
+
+// addTransition records an issue state change, returning the action as it
+// should be told (closed as not planned, rather than just closed).
+func (w *work) addTransition(e *github.Event, issue *github.Issue, action string) string {
+	t := &transition{
+		item:  newID(e.GetRepo().GetName(), issue.GetNumber()),
+		url:   issue.GetHTMLURL(),
+		title: issue.GetTitle(),
+		at:    e.GetCreatedAt(),
+	}
+	switch action {
+	case "opened":
+		t.to = "open"
+	case "closed":
+		t.from, t.to = "open", "closed"
+		if t.reason = stateReason(e); t.reason != "" && t.reason != "completed" {
+			action = "closed as " + t.reason
+		}
+	case "reopened":
+		t.from, t.to = "closed", "open"
+	default:
+		return action
+	}
+
+	w.transitions = append(w.transitions, t)
+	return action
+}
+
+// transitionsReport returns how many issues I opened, closed and reopened,
+// and each transition, oldest first.
+func (w *work) transitionsReport() string {
+	var lines []string
+	var opened, closed, notPlanned, reopened int
+	for _, t := range w.transitions {
+		if w.getIssue(t.item) == nil {
+			continue // left out
+		}
+		switch {
+		case t.from == "":
+			opened++
+		case t.to == "closed":
+			closed++
+			if t.reason == "not planned" {
+				notPlanned++
+			}
+		default:
+			reopened++
+		}
+
+		line := fmt.Sprintf("%s %s%s (%s) %s: ", t.at.In(location).Format("2006-01-02"), t.item.repo, t.item, t.url, t.title)
+		if t.from != "" {
+			line += t.from + " -> "
+		}
+		line += t.to
+		if t.reason != "" {
+			line += " (" + t.reason + ")"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var counts []string
+	if opened > 0 {
+		counts = append(counts, fmt.Sprintf("opened %d", opened))
+	}
+	if closed > 0 {
+		count := fmt.Sprintf("closed %d", closed)
+		if notPlanned > 0 {
+			count += fmt.Sprintf(" (%d as not planned)", notPlanned)
+		}
+		counts = append(counts, count)
+	}
+	if reopened > 0 {
+		counts = append(counts, fmt.Sprintf("reopened %d", reopened))
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Issue transitions:"))
+	report += fmt.Sprintf("Issues: %s\n", strings.Join(counts, ", "))
+	report += strings.Join(lines, "\n") + "\n"
+	return report
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestTransitionsReport(t *testing.T) {
+	location = time.UTC
+	at := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	w := &work{
+		issues:  make(map[id]*metadata),
+		pulls:   make(map[id]*metadata),
+		actions: make(map[id][]*action),
+		bots:    &botFilter{},
+	}
+
+	issueEvent := func(number int, action, reason string) *github.Event {
+		issue := map[string]interface{}{
+			"number":   number,
+			"title":    "Crash",
+			"html_url": "https://github.com/owner/repo/issues/1",
+		}
+		if reason != "" {
+			issue["state_reason"] = reason
+		}
+		raw, _ := json.Marshal(map[string]interface{}{"action": action, "issue": issue})
+		payload := json.RawMessage(raw)
+		return &github.Event{
+			Type:       github.String("IssuesEvent"),
+			Repo:       &github.Repository{Name: github.String("owner/repo")},
+			CreatedAt:  &at,
+			RawPayload: &payload,
+		}
+	}
+	handleEvent(w, issueEvent(1, "opened", ""))
+	handleEvent(w, issueEvent(1, "closed", "not_planned"))
+	handleEvent(w, issueEvent(1, "reopened", ""))
+	handleEvent(w, issueEvent(1, "closed", "completed"))
+	handleEvent(w, issueEvent(1, "labeled", ""))
+
+	if got := w.actions[newID("owner/repo", 1)][1].action; got != "closed as not planned" {
+		t.Errorf("got action %q, want closed as not planned", got)
+	}
+
+	want := `
+Issue transitions:
+
+Issues: opened 1, closed 2 (1 as not planned), reopened 1
+2024-03-04 owner/repo#1 (https://github.com/owner/repo/issues/1) Crash: open
+2024-03-04 owner/repo#1 (https://github.com/owner/repo/issues/1) Crash: open -> closed (not planned)
+2024-03-04 owner/repo#1 (https://github.com/owner/repo/issues/1) Crash: closed -> open
+2024-03-04 owner/repo#1 (https://github.com/owner/repo/issues/1) Crash: open -> closed (completed)
+`
+	if got := w.transitionsReport(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	w.removeItems(map[id]bool{newID("owner/repo", 1): true})
+	if got := w.transitionsReport(); got != "" {
+		t.Errorf("got %q, want no section", got)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Truncating Actions
+
+// truncation is an item with too many actions: only the first and last ones
+// are given as they are, the ones in the middle are summarized together.
+type truncation struct {
+	total  int    // actions on the item
+	head   int    // first actions kept
+	tail   int    // last actions kept
+	middle string // summary of the actions in between
+}
+
+// truncateActions returns the jobs summarizing the actions in the middle of
+// the items with more than max actions (0 means no limit). It must run after
+// the actions themselves are summarized.
+func (w *work) truncateActions(max int) []*job {
+	if max <= 0 {
+		return nil
+	}
+	if w.truncated == nil {
+		w.truncated = make(map[id]*truncation)
+	}
+
+	var jobs []*job
+	for _, id := range append(w.sortedIds(w.issues), w.sortedIds(w.pulls)...) {
+		actions := w.actions[id]
+		if len(actions) <= max {
+			continue
+		}
+		t := &truncation{total: len(actions), head: max / 2, tail: max - max/2}
+		w.truncated[id] = t
+
+		middle := actions[t.head : len(actions)-t.tail]
+		instr := fmt.Sprintf("Actions %d to %d of %d:\n-\n", t.head+1, len(actions)-t.tail, len(actions))
+		var updated = w.getIssueOrPR(id).updated
+		for _, a := range middle {
+			instr += fmt.Sprintf("Action: %s\nObject: %s\nContent: %s\n-\n", a.action, a.object, a.content)
+			if a.updated.After(updated) {
+				updated = a.updated
+			}
+		}
+
+		jobs = append(jobs, &job{
+			repo:    w.getIssueOrPR(id).repo,
+			item:    id.String(),
+			role:    truncatedSummaryString,
+			instr:   instr,
+			length:  maxAnswerTokens,
+			result:  &t.middle,
+			updated: updated,
+		})
+	}
+	return jobs
+}
+
+// keptActions returns the actions given, as they are, in the summary of the
+// item: all of them, or the first and last ones with the summary of the ones
+// in between.
+func (w *work) keptActions(id id) []*action {
+	actions := w.actions[id]
+	t := w.truncated[id]
+	if t == nil {
+		return actions
+	}
+
+	kept := append([]*action{}, actions[:t.head]...)
+	kept = append(kept, &action{
+		action:  fmt.Sprintf("%d actions", t.total-t.head-t.tail),
+		object:  "summarized together",
+		content: t.middle,
+	})
+	return append(kept, actions[len(actions)-t.tail:]...)
+}
+
+// truncationNote returns a line telling the actions of the item were
+// truncated, or an empty string.
+func (w *work) truncationNote(id id) string {
+	t := w.truncated[id]
+	if t == nil {
+		return ""
+	}
+	return fmt.Sprintf("Truncated: %d actions, the first %d and last %d kept, %d summarized together",
+		t.total, t.head, t.tail, t.total-t.head-t.tail)
+}
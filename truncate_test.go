@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTruncateActions(t *testing.T) {
+	long, short := newID("owner/repo", 1), newID("owner/repo", 2)
+	w := &work{
+		issues:  map[id]*metadata{long: {eventId: long, repo: "owner/repo"}, short: {eventId: short, repo: "owner/repo"}},
+		pulls:   make(map[id]*metadata),
+		actions: make(map[id][]*action),
+	}
+	for i := 1; i <= 10; i++ {
+		w.actions[long] = append(w.actions[long], &action{action: "created", object: ObjectIssueComment, content: fmt.Sprintf("comment %d", i)})
+	}
+	w.actions[short] = []*action{{action: "opened", object: ObjectIssue}}
+
+	if jobs := w.truncateActions(0); jobs != nil {
+		t.Fatalf("got %d jobs without a limit", len(jobs))
+	}
+
+	jobs := w.truncateActions(4)
+	if len(jobs) != 1 {
+		t.Fatalf("got %d jobs, want 1", len(jobs))
+	}
+	if !strings.Contains(jobs[0].instr, "Actions 3 to 8 of 10") || !strings.Contains(jobs[0].instr, "comment 8") || strings.Contains(jobs[0].instr, "comment 9") {
+		t.Errorf("unexpected prompt: %q", jobs[0].instr)
+	}
+	*jobs[0].result = "discussed the fix"
+
+	var got []string
+	for _, a := range w.keptActions(long) {
+		got = append(got, a.content)
+	}
+	if want := "comment 1|comment 2|discussed the fix|comment 9|comment 10"; strings.Join(got, "|") != want {
+		t.Errorf("got %q, want %q", strings.Join(got, "|"), want)
+	}
+	if len(w.keptActions(short)) != 1 || w.truncationNote(short) != "" {
+		t.Errorf("short item truncated")
+	}
+
+	note := w.truncationNote(long)
+	if note != "Truncated: 10 actions, the first 2 and last 2 kept, 6 summarized together" {
+		t.Errorf("got note %q", note)
+	}
+	if instr := w.actionSummary(long, new(string)).instr; !strings.Contains(instr, note) || strings.Contains(instr, "comment 5") {
+		t.Errorf("item summary not truncated: %q", instr)
+	}
+}
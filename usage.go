@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Token Usage and Cost Accounting
+
+// price is the dollar cost per 1K tokens of a model.
+type price struct {
+	prompt     float64
+	completion float64
+}
+
+// pricing is the per-model pricing table (USD per 1K tokens).
+var pricing = map[string]price{
+	"gpt-3.5-turbo":       {prompt: 0.0015, completion: 0.002},
+	"gpt-3.5-turbo-0125":  {prompt: 0.0005, completion: 0.0015},
+	"gpt-3.5-turbo-1106":  {prompt: 0.001, completion: 0.002},
+	"gpt-3.5-turbo-16k":   {prompt: 0.003, completion: 0.004},
+	"gpt-4":               {prompt: 0.03, completion: 0.06},
+	"gpt-4-0125-preview":  {prompt: 0.01, completion: 0.03},
+	"gpt-4-1106-preview":  {prompt: 0.01, completion: 0.03},
+	"gpt-4-32k":           {prompt: 0.06, completion: 0.12},
+	"gpt-4-turbo":         {prompt: 0.01, completion: 0.03},
+	"gpt-4-turbo-preview": {prompt: 0.01, completion: 0.03},
+	"gpt-4o":              {prompt: 0.005, completion: 0.015},
+	"gpt-4o-mini":         {prompt: 0.00015, completion: 0.0006},
+}
+
+type tokens struct {
+	calls      int
+	prompt     int
+	completion int
+	dollars    float64 // cost of the calls, priced by the model that made them
+}
+
+// cost returns the estimated dollar cost of the tokens for the given model.
+func (t *tokens) cost(model string) float64 {
+	p, ok := pricing[model]
+	if !ok {
+		return 0
+	}
+	return float64(t.prompt)/1000*p.prompt + float64(t.completion)/1000*p.completion
+}
+
+type usage struct {
+	mu       sync.Mutex
+	model    string
+	items    map[string]*tokens // keyed by item (owner/repo#123, timecard, etc.)
+	order    []string           // items in the order they were first seen
+	total    tokens
+	models   []string        // models used, other than the primary one
+	unpriced map[string]bool // models used without known pricing
+}
+
+func newUsage(model string) *usage {
+	return &usage{
+		model:    model,
+		items:    make(map[string]*tokens),
+		unpriced: make(map[string]bool),
+	}
+}
+
+// add accounts the tokens of a single LLM call, made with the model, to an
+// item. Local models are free.
+func (u *usage) add(item, model string, prompt, completion int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	call := &tokens{prompt: prompt, completion: completion}
+	dollars := call.cost(model)
+	if _, ok := pricing[model]; !ok && !strings.HasPrefix(model, ollamaPrefix) {
+		u.unpriced[model] = true
+	}
+	if model != u.model && !slices.Contains(u.models, model) {
+		u.models = append(u.models, model)
+	}
+
+	t, ok := u.items[item]
+	if !ok {
+		t = &tokens{}
+		u.items[item] = t
+		u.order = append(u.order, item)
+	}
+	t.calls++
+	t.prompt += prompt
+	t.completion += completion
+	t.dollars += dollars
+
+	u.total.calls++
+	u.total.prompt += prompt
+	u.total.completion += completion
+	u.total.dollars += dollars
+}
+
+// totals returns the tokens used by all the calls.
+func (u *usage) totals() tokens {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.total
+}
+
+// summary returns the per-item and total token usage with estimated costs.
+func (u *usage) summary() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.total.calls == 0 {
+		return ""
+	}
+
+	items := make([]string, len(u.order))
+	copy(items, u.order)
+	sort.SliceStable(items, func(i, j int) bool {
+		return u.items[items[i]].dollars > u.items[items[j]].dollars
+	})
+
+	var summary string
+	summary += fmt.Sprintf("Token usage (%s):\n\n", strings.Join(append([]string{u.model}, u.models...), ", "))
+	for _, item := range items {
+		t := u.items[item]
+		summary += fmt.Sprintf("  %-12s calls %4d  prompt %8d  completion %8d  $%.4f\n",
+			item, t.calls, t.prompt, t.completion, t.dollars,
+		)
+	}
+	summary += fmt.Sprintf("  %-12s calls %4d  prompt %8d  completion %8d  $%.4f\n",
+		"total", u.total.calls, u.total.prompt, u.total.completion, u.total.dollars,
+	)
+	for _, model := range append([]string{u.model}, u.models...) {
+		if u.unpriced[model] {
+			summary += fmt.Sprintf("\n  (no pricing known for %s, costs not estimated)\n", model)
+		}
+	}
+
+	return summary
+}
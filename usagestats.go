@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Usage Statistics
+
+// usageRecorder records the run (how long it took, what it reported on and
+// what it cost) in the database, when usage statistics are wanted: they are
+// opt-in, and never leave the machine. Methods on a nil recorder do nothing.
+type usageRecorder struct {
+	started     time.Time
+	summaryType string
+	format      string
+	events      int
+	items       int
+}
+
+// runUsage is the usage of the run in progress, if recorded.
+var runUsage *usageRecorder
+
+// counted notes how many events and items the run reported on.
+func (u *usageRecorder) counted(events, items int) {
+	if u == nil {
+		return
+	}
+	u.events, u.items = events, items
+}
+
+// record saves the run, once, as ok, failed or interrupted.
+func (u *usageRecorder) record(status string) {
+	if u == nil {
+		return
+	}
+	runUsage = nil // a run is recorded once
+	total := tokenUsage.totals()
+	err := db.saveRun(&runRecord{
+		started:     u.started,
+		finished:    time.Now(),
+		summaryType: u.summaryType,
+		format:      u.format,
+		events:      u.events,
+		items:       u.items,
+		calls:       total.calls,
+		tokens:      total.prompt + total.completion,
+		dollars:     total.dollars,
+		status:      status,
+	})
+	if err != nil {
+		fmt.Println("Error saving usage statistics:", err)
+	}
+}
+
+// runRecord is a run, as recorded for the usage statistics.
+type runRecord struct {
+	started     time.Time
+	finished    time.Time
+	summaryType string
+	format      string
+	events      int
+	items       int
+	calls       int
+	tokens      int
+	dollars     float64
+	status      string
+}
+
+// usageReport returns the usage statistics of the runs: overall, per month,
+// and per summary type.
+func usageReport(runs []*runRecord) string {
+	if len(runs) == 0 {
+		return "No runs recorded (set usage_stats in the profile to record them).\n"
+	}
+
+	type stat struct {
+		runs     int
+		duration time.Duration
+		calls    int
+		tokens   int
+		dollars  float64
+	}
+	add := func(s *stat, r *runRecord) {
+		s.runs++
+		s.duration += r.finished.Sub(r.started)
+		s.calls += r.calls
+		s.tokens += r.tokens
+		s.dollars += r.dollars
+	}
+
+	total := &stat{}
+	statuses := make(map[string]int)
+	months := make(map[string]*stat)
+	types := make(map[string]int)
+	for _, r := range runs {
+		add(total, r)
+		statuses[r.status]++
+		month := r.started.In(location).Format("2006-01")
+		if months[month] == nil {
+			months[month] = &stat{}
+		}
+		add(months[month], r)
+		kind := r.summaryType
+		if r.format != FormatTimecard {
+			kind = r.format
+		}
+		types[kind]++
+	}
+
+	var counts []string
+	for _, status := range []string{"ok", "failed", "interrupted"} {
+		if statuses[status] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", statuses[status], status))
+		}
+	}
+
+	report := fmt.Sprintf("Usage (since %s):\n\n", runs[0].started.In(location).Format("2006-01-02"))
+	report += fmt.Sprintf("Runs: %d (%s), average %v, %d calls, %d tokens, $%.2f\n",
+		total.runs, strings.Join(counts, ", "), (total.duration / time.Duration(total.runs)).Round(time.Second), total.calls, total.tokens, total.dollars)
+
+	keys := make([]string, 0, len(months))
+	for month := range months {
+		keys = append(keys, month)
+	}
+	sort.Strings(keys)
+	report += "\nBy month:\n"
+	for _, month := range keys {
+		s := months[month]
+		report += fmt.Sprintf("  %s  runs %4d  average %8v  calls %6d  tokens %9d  $%.2f\n",
+			month, s.runs, (s.duration / time.Duration(s.runs)).Round(time.Second), s.calls, s.tokens, s.dollars)
+	}
+
+	keys = keys[:0]
+	for kind := range types {
+		keys = append(keys, kind)
+	}
+	sort.Strings(keys)
+	report += "\nBy type:\n"
+	for _, kind := range keys {
+		report += fmt.Sprintf("  %s: %d\n", kind, types[kind])
+	}
+
+	return report
+}
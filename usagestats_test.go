@@ -0,0 +1,49 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsageStats(t *testing.T) {
+	location = time.UTC
+	st, err := openStore(filepath.Join(t.TempDir(), "db.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.close()
+
+	started := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	for _, r := range []*runRecord{
+		{started: started, finished: started.Add(2 * time.Minute), summaryType: "executive", format: FormatTimecard, calls: 10, tokens: 1000, dollars: 0.5, status: "ok"},
+		{started: started.AddDate(0, 1, 0), finished: started.AddDate(0, 1, 0).Add(4 * time.Minute), summaryType: "executive", format: FormatLedger, calls: 20, tokens: 3000, dollars: 1, status: "failed"},
+	} {
+		if err := st.saveRun(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := st.loadRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := usageReport(runs)
+	for _, want := range []string{
+		"Usage (since 2024-03-04):",
+		"Runs: 2 (1 ok, 1 failed), average 3m0s, 30 calls, 4000 tokens, $1.50\n",
+		"  2024-03  runs    1  average     2m0s  calls     10  tokens      1000  $0.50\n",
+		"  2024-04  runs    1  average     4m0s  calls     20  tokens      3000  $1.00\n",
+		"  executive: 1\n",
+		"  ledger: 1\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q doesn't contain %q", report, want)
+		}
+	}
+
+	if got := usageReport(nil); !strings.HasPrefix(got, "No runs recorded") {
+		t.Errorf("got %q, want no runs", got)
+	}
+}
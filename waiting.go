@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Waiting vs Acting
+
+// collectResponses fetches when others (people, not bots) commented on, or
+// reviewed, the pull requests, to tell the time waiting on them from the
+// time the pull request was on me.
+func collectResponses(ctx context.Context, gh *github.Client, w *work) {
+	others := func(login string) bool {
+		return !strings.EqualFold(login, w.user) && !strings.HasSuffix(login, "[bot]")
+	}
+
+	for _, id := range w.sortedIds(w.pulls) {
+		meta := w.pulls[id]
+		owner, name, _ := strings.Cut(meta.repo, "/")
+
+		commentOpt := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			comments, resp, err := gh.Issues.ListComments(ctx, owner, name, id.number, commentOpt)
+			if err != nil {
+				fmt.Printf("Error fetching comments of %s%s: %v\n", meta.repo, id, err)
+				break
+			}
+			for _, c := range comments {
+				if others(c.GetUser().GetLogin()) {
+					w.responses[id] = append(w.responses[id], c.GetCreatedAt())
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			commentOpt.Page = resp.NextPage
+		}
+
+		reviewOpt := &github.ListOptions{PerPage: 100}
+		for {
+			reviews, resp, err := gh.PullRequests.ListReviews(ctx, owner, name, id.number, reviewOpt)
+			if err != nil {
+				fmt.Printf("Error fetching reviews of %s%s: %v\n", meta.repo, id, err)
+				break
+			}
+			for _, r := range reviews {
+				if others(r.GetUser().GetLogin()) {
+					w.responses[id] = append(w.responses[id], r.GetSubmittedAt())
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			reviewOpt.Page = resp.NextPage
+		}
+
+		sort.Slice(w.responses[id], func(i, j int) bool {
+			return w.responses[id][i].Before(w.responses[id][j])
+		})
+	}
+}
+
+// timeSplit returns the wall time between my first and last actions on the
+// item, in the period, and how much of it was spent waiting on others: from
+// each of my actions to the first response, when someone responded before my
+// next action.
+func (w *work) timeSplit(id id) (elapsed, waiting time.Duration) {
+	var mine []time.Time
+	for _, a := range w.actions[id] {
+		if !a.at.IsZero() {
+			mine = append(mine, a.at)
+		}
+	}
+	if len(mine) < 2 {
+		return 0, 0
+	}
+	sort.Slice(mine, func(i, j int) bool { return mine[i].Before(mine[j]) })
+
+	responses := w.responses[id]
+	for i := 0; i < len(mine)-1; i++ {
+		at := sort.Search(len(responses), func(j int) bool { return responses[j].After(mine[i]) })
+		if at < len(responses) && responses[at].Before(mine[i+1]) {
+			waiting += responses[at].Sub(mine[i])
+		}
+	}
+
+	return mine[len(mine)-1].Sub(mine[0]), waiting
+}
+
+// timeReport describes the time spent on the item: the estimated effort (by
+// the effort weights) separately from the elapsed time, so long lived pull
+// requests don't look like more work.
+func (w *work) timeReport(id id, weights *effortWeights) string {
+	report := fmt.Sprintf("%s %.2fh", msg("Estimated effort:"), w.estimateEffort(id, weights))
+	elapsed, waiting := w.timeSplit(id)
+	if elapsed > 0 {
+		report += fmt.Sprintf(", elapsed %s (waiting on others %s, on me %s)",
+			formatSpan(elapsed), formatSpan(waiting), formatSpan(elapsed-waiting))
+	}
+	return report
+}
+
+// formatSpan returns the duration in days and hours (or minutes, if short).
+func formatSpan(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", d/(24*time.Hour), d%(24*time.Hour)/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
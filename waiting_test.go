@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeSplit(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2024, 3, d, h, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		mine      []time.Time
+		responses []time.Time
+		elapsed   time.Duration
+		waiting   time.Duration
+	}{
+		{"single action", []time.Time{day(1, 10)}, nil, 0, 0},
+		{"no responses", []time.Time{day(1, 10), day(3, 10)}, nil, 48 * time.Hour, 0},
+		{"review then fix", []time.Time{day(1, 10), day(4, 10)}, []time.Time{day(3, 10)}, 72 * time.Hour, 48 * time.Hour},
+		{"first response counts", []time.Time{day(1, 10), day(4, 10)}, []time.Time{day(2, 10), day(3, 10)}, 72 * time.Hour, 24 * time.Hour},
+		{"response after the last action", []time.Time{day(1, 10), day(2, 10)}, []time.Time{day(5, 10)}, 24 * time.Hour, 0},
+		{"back and forth", []time.Time{day(1, 10), day(2, 10), day(5, 10)}, []time.Time{day(1, 12), day(4, 10)}, 96 * time.Hour, 2*time.Hour + 48*time.Hour},
+		{"unordered actions", []time.Time{day(4, 10), day(1, 10)}, []time.Time{day(3, 10)}, 72 * time.Hour, 48 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := newID("owner/repo", 1)
+			w := &work{actions: make(map[id][]*action), responses: map[id][]time.Time{item: tt.responses}}
+			for _, at := range tt.mine {
+				w.actions[item] = append(w.actions[item], &action{at: at})
+			}
+			elapsed, waiting := w.timeSplit(item)
+			if elapsed != tt.elapsed || waiting != tt.waiting {
+				t.Errorf("timeSplit() = %v, %v, want %v, %v", elapsed, waiting, tt.elapsed, tt.waiting)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Repository Weights
+
+// sideShare is the share of the narrative below which a repository is a side
+// project, compressed into a sentence.
+const sideShare = 0.1
+
+// repoWeight returns the weight of the repository in the narrative: the one of
+// the most specific pattern (owner/repo, owner/*, *) matching it, or 1.
+func repoWeight(weights map[string]float64, repo string) float64 {
+	patterns := make([]string, 0, len(weights))
+	for pattern := range weights {
+		patterns = append(patterns, pattern)
+	}
+	if best, ok := bestPattern(patterns, repo); ok {
+		return weights[best]
+	}
+	return 1
+}
+
+// checkWeights returns an error if any of the weights is negative.
+func checkWeights(weights map[string]float64) error {
+	for pattern, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("weights: negative weight %g for %s", weight, pattern)
+		}
+	}
+	return nil
+}
+
+// weightsReport returns the share of the narrative of each repository I was
+// active in, in proportion to their weights, the primary ones first.
+func (w *work) weightsReport(weights map[string]float64) string {
+	if len(weights) == 0 {
+		return ""
+	}
+
+	repos := w.activeRepos()
+	weight := make(map[string]float64, len(repos))
+	total := 0.0
+	for _, repo := range repos {
+		weight[repo] = repoWeight(weights, repo)
+		total += weight[repo]
+	}
+	if total == 0 {
+		return ""
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		return weight[repos[i]] > weight[repos[j]]
+	})
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Weights:"))
+	for _, repo := range repos {
+		share := weight[repo] / total
+		line := fmt.Sprintf("%s: %.0f%%", repo, share*100)
+		switch {
+		case share == 0:
+			line += " (leave out)"
+		case share < sideShare:
+			line += " (side project: one sentence)"
+		}
+		report += line + "\n"
+	}
+
+	return report
+}
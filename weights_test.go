@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestWeightsReport(t *testing.T) {
+	w := &work{
+		issues: map[id]*metadata{newID("rafaeldtinoco/blog", 1): {}, newID("other/repo", 2): {}},
+		pulls:  map[id]*metadata{newID("aquasecurity/tracee", 3): {}, newID("rafaeldtinoco/mirror", 4): {}},
+	}
+	weights := map[string]float64{
+		"aquasecurity/tracee":  3,
+		"rafaeldtinoco/*":      0.25,
+		"rafaeldtinoco/mirror": 0,
+	}
+
+	want := `
+Weights:
+
+aquasecurity/tracee: 71%
+other/repo: 24%
+rafaeldtinoco/blog: 6% (side project: one sentence)
+rafaeldtinoco/mirror: 0% (leave out)
+`
+	if got := w.weightsReport(weights); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := w.weightsReport(nil); got != "" {
+		t.Errorf("got %q, want no section without weights", got)
+	}
+	if err := checkWeights(map[string]float64{"*": -1}); err == nil {
+		t.Error("expected an error for a negative weight")
+	}
+}
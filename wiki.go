@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Wiki Pages
+
+type wikiPage struct {
+	repo    string   // owner/repo the wiki belongs to
+	title   string   // page title
+	url     string   // page URL
+	actions []string // created, edited
+}
+
+// addWikiPage records a wiki page created or edited in a repository.
+func (w *work) addWikiPage(repo string, page *github.Page) {
+	url := page.GetHTMLURL()
+
+	wp, ok := w.wiki[url]
+	if !ok {
+		wp = &wikiPage{
+			repo:  repo,
+			title: page.GetTitle(),
+			url:   url,
+		}
+		w.wiki[url] = wp
+		w.wikiOrder = append(w.wikiOrder, url)
+	}
+
+	wp.actions = append(wp.actions, page.GetAction())
+}
+
+// wikiReport returns the docs section of the report: the wiki pages.
+func (w *work) wikiReport() string {
+	if len(w.wiki) == 0 {
+		return ""
+	}
+
+	report := fmt.Sprintf("\n%s\n\n", msg("Docs:"))
+	for _, url := range w.wikiOrder {
+		wp := w.wiki[url]
+		report += fmt.Sprintf("Wiki page: %s (%s) %s\n", wp.repo, wp.url, wp.title)
+		report += fmt.Sprintf("Actions: %s\n", strings.Join(wp.actions, ", "))
+	}
+
+	return report
+}
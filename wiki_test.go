@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestWikiReport(t *testing.T) {
+	w := &work{wiki: make(map[string]*wikiPage)}
+	page := func(action string) *github.Page {
+		return &github.Page{
+			Title:   github.String("Getting Started"),
+			Action:  github.String(action),
+			HTMLURL: github.String("https://github.com/owner/repo/wiki/Getting-Started"),
+		}
+	}
+
+	w.addWikiPage("owner/repo", page("created"))
+	w.addWikiPage("owner/repo", page("edited"))
+
+	want := "\nDocs:\n\nWiki page: owner/repo (https://github.com/owner/repo/wiki/Getting-Started) Getting Started\n" +
+		"Actions: created, edited\n"
+	if report := w.wikiReport(); !strings.Contains(report, want) {
+		t.Errorf("got %q, want %q", report, want)
+	}
+}
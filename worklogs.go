@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Jira Worklogs
+
+// jiraKeyPattern matches Jira issue keys, like PROJ-123. The branch names
+// are lowercase, so the keys in them are matched regardless of case.
+var (
+	jiraKeyPattern       = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[1-9][0-9]*\b`)
+	jiraBranchKeyPattern = regexp.MustCompile(`(?i)\b[A-Z][A-Z0-9]+-[1-9][0-9]*\b`)
+)
+
+// jiraKeys returns the Jira issue keys mentioned in the title of the item
+// and, for pull requests, in the name of its branch and the messages of its
+// commits (correlated from the pushes).
+func (w *work) jiraKeys(id id) []string {
+	meta := w.getIssueOrPR(id)
+	found := jiraKeyPattern.FindAllString(meta.title, -1)
+	if _, branch, ok := strings.Cut(meta.head, ":"); ok {
+		for _, key := range jiraBranchKeyPattern.FindAllString(branch, -1) {
+			found = append(found, strings.ToUpper(key))
+		}
+	}
+	for _, a := range w.actions[id] {
+		if a.object == ObjectCommit {
+			found = append(found, jiraKeyPattern.FindAllString(a.content, -1)...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var keys []string
+	for _, key := range found {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jiraWorklog is a worklog, as the Jira API lists and takes them.
+type jiraWorklog struct {
+	Started          string `json:"started"` // 2006-01-02T15:04:05.000-0700
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Comment          string `json:"comment"`
+}
+
+// jiraStarted is the start of the worklogs of a day: 9 am.
+func jiraStarted(day string) (string, error) {
+	start, err := time.ParseInLocation("2006-01-02", day, location)
+	if err != nil {
+		return "", err
+	}
+	return start.Add(9 * time.Hour).Format("2006-01-02T15:04:05.000-0700"), nil
+}
+
+// logWork adds the worklogs to their Jira issues (Tempo shows the Jira
+// worklogs), skipping the ones already logged (same day and comment), so a
+// rerun doesn't log them twice. It returns how many were added.
+func (c *jiraClient) logWork(worklogs []*tempoWorklog) (int, error) {
+	logged := make(map[string]map[string]bool) // issue key: day and comment
+	added := 0
+
+	for _, wl := range worklogs {
+		if _, ok := logged[wl.IssueKey]; !ok {
+			var list struct {
+				Worklogs []*jiraWorklog `json:"worklogs"`
+			}
+			if err := c.do(http.MethodGet, "issue/"+wl.IssueKey+"/worklog", nil, &list); err != nil {
+				return added, err
+			}
+			logged[wl.IssueKey] = make(map[string]bool)
+			for _, existing := range list.Worklogs {
+				if len(existing.Started) >= 10 {
+					logged[wl.IssueKey][existing.Started[:10]+"\x00"+existing.Comment] = true
+				}
+			}
+		}
+		if logged[wl.IssueKey][wl.StartDate+"\x00"+wl.Description] || wl.TimeSpentSeconds < 60 {
+			continue // Jira logs whole minutes
+		}
+
+		started, err := jiraStarted(wl.StartDate)
+		if err != nil {
+			return added, err
+		}
+		worklog := &jiraWorklog{Started: started, TimeSpentSeconds: wl.TimeSpentSeconds, Comment: wl.Description}
+		if err := c.post("issue/"+wl.IssueKey+"/worklog", worklog); err != nil {
+			return added, fmt.Errorf("%s: %w", wl.IssueKey, err)
+		}
+		logged[wl.IssueKey][wl.StartDate+"\x00"+wl.Description] = true
+		added++
+	}
+
+	return added, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJiraKeys(t *testing.T) {
+	pr, issue := newID("owner/repo", 7), newID("owner/repo", 8)
+	w := &work{
+		issues:  map[id]*metadata{issue: {repo: "owner/repo", title: "Crash on start"}},
+		pulls:   map[id]*metadata{pr: {repo: "owner/repo", title: "PROJ-12: Add the thing", head: "me/repo:feature/ops-3"}},
+		actions: make(map[id][]*action),
+		pushes: map[string]*branchPushes{
+			"me/repo@feature/OPS-3": {repo: "me/repo", branch: "feature/OPS-3", commits: []*pushCommit{
+				{sha: "0123456789", message: "Fix the test (PROJ-12, PROJ-40)"},
+			}},
+			"me/repo@main": {repo: "me/repo", branch: "main", commits: []*pushCommit{{sha: "abcdef0123", message: "ABC-1 unrelated"}}},
+		},
+	}
+
+	w.correlatePushes(context.Background(), nil)
+	if got, want := w.jiraKeys(pr), []string{"OPS-3", "PROJ-12", "PROJ-40"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := w.jiraKeys(issue); len(got) != 0 {
+		t.Errorf("got %v, want none", got)
+	}
+
+	// the branch is known even without pushes to it
+	w = &work{pulls: map[id]*metadata{pr: {repo: "owner/repo", title: "Add the thing", head: "me/repo:proj-5-thing"}}}
+	if got, want := w.jiraKeys(pr), []string{"PROJ-5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTempoWorklogsJiraKeys(t *testing.T) {
+	setLocation(t, time.UTC)
+	day := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	pr := newID("owner/repo", 7)
+	w := &work{
+		pulls:   map[id]*metadata{pr: {repo: "owner/repo", title: "PROJ-1 PROJ-2 Add the thing"}},
+		actions: map[id][]*action{pr: {{action: "opened", object: ObjectPR, at: day}}}, // 0.25h, the least
+	}
+	summary := "feature: adds the thing"
+
+	worklogs, unmapped := w.tempoWorklogs([]id{pr}, map[id]*string{pr: &summary}, nil, defaultEffort.withDefaults())
+	if len(unmapped) != 0 || len(worklogs) != 2 {
+		t.Fatalf("got %d worklogs, unmapped %v", len(worklogs), unmapped)
+	}
+	for i, key := range []string{"PROJ-1", "PROJ-2"} {
+		if wl := worklogs[i]; wl.IssueKey != key || wl.TimeSpentSeconds != 450 {
+			t.Errorf("got %+v, want 7.5 minutes in %s", wl, key)
+		}
+	}
+}
+
+func TestJiraLogWork(t *testing.T) {
+	setLocation(t, time.UTC)
+	var added []*jiraWorklog
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if user, token, _ := r.BasicAuth(); user != "me" || token != "token" {
+			t.Errorf("no credentials")
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-1/worklog":
+			rw.Write([]byte(`{"worklogs": [{"started": "2024-03-04T09:00:00.000+0000", "comment": "owner/repo#7 done"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PROJ-1/worklog":
+			wl := &jiraWorklog{}
+			json.NewDecoder(r.Body).Decode(wl)
+			added = append(added, wl)
+			rw.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	c := newJiraClient(server.URL+"/", "me", "token")
+	n, err := c.logWork([]*tempoWorklog{
+		{IssueKey: "PROJ-1", StartDate: "2024-03-04", TimeSpentSeconds: 3600, Description: "owner/repo#7 done"}, // logged
+		{IssueKey: "PROJ-1", StartDate: "2024-03-05", TimeSpentSeconds: 1800, Description: "owner/repo#7 done"},
+		{IssueKey: "PROJ-1", StartDate: "2024-03-05", TimeSpentSeconds: 30, Description: "owner/repo#8 seen"}, // under a minute
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*jiraWorklog{{Started: "2024-03-05T09:00:00.000+0000", TimeSpentSeconds: 1800, Comment: "owner/repo#7 done"}}
+	if n != 1 || !reflect.DeepEqual(added, want) {
+		t.Errorf("got %d, %+v", n, added)
+	}
+}